@@ -28,14 +28,168 @@ WHERE deleted_at IS NULL;
 		&entity.User{},
 		&entity.Package{},
 		&entity.UserDiscipline{},
+		&entity.Document{},
 		&entity.Comment{},
 		&entity.DisciplineGroup{},
 		&entity.DisciplineGroupConsolidator{},
 		&entity.DisciplineListDocument{},
 		&entity.DisciplineListDocumentConsolidator{},
+		&entity.Job{},
+		&entity.ReportTemplate{},
+		&entity.SignOff{},
+		&entity.SignOffStep{},
+		&entity.AssignmentRule{},
+		&entity.DocumentRoutingRule{},
+		&entity.Delegation{},
+		&entity.Notification{},
+		&entity.SavedView{},
+		&entity.CommentMention{},
+		&entity.CommentReaction{},
+		&entity.CommentRevision{},
+		&entity.CommentClass{},
+		&entity.ReviewOutcome{},
+		&entity.Transmittal{},
+		&entity.TransmittalDocument{},
+		&entity.SLARule{},
+		&entity.Holiday{},
+		&entity.ReviewWindow{},
 	); err != nil {
 		return err
 	}
 
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_report_templates_package_id
+ON report_templates(package_id)
+WHERE deleted_at IS NULL;
+`).Error; err != nil {
+		return err
+	}
+
+	// only one pending or running job per identical request
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_active_dedup_key
+ON jobs(dedup_key)
+WHERE status IN ('PENDING', 'RUNNING') AND deleted_at IS NULL;
+`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_comment_reactions_unique
+ON comment_reactions(comment_id, user_id, type)
+WHERE deleted_at IS NULL;
+`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_comment_classes_code
+ON comment_classes(package_id, kind, LOWER(code))
+WHERE deleted_at IS NULL;
+`).Error; err != nil {
+		return err
+	}
+
+	// one outcome per revision of a document
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_review_outcomes_revision
+ON review_outcomes(document_id, revision)
+WHERE deleted_at IS NULL;
+`).Error; err != nil {
+		return err
+	}
+
+	// transmittal numbers run per package and direction
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_transmittals_number
+ON transmittals(package_id, direction, sequence)
+WHERE deleted_at IS NULL;
+`).Error; err != nil {
+		return err
+	}
+
+	// one rule per purpose and kind of submission, a rule without purpose
+	// counts as its own purpose
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_sla_rules_purpose
+ON sla_rules(package_id, COALESCE(purpose, ''), resubmission)
+WHERE deleted_at IS NULL;
+`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_holidays_date
+ON holidays(package_id, date)
+WHERE deleted_at IS NULL;
+`).Error; err != nil {
+		return err
+	}
+
+	// replies to replies used to point at the reply they answer, move them
+	// under the top level comment one level per pass and keep the answered
+	// reply as their parent
+	for {
+		res := db.Exec(`UPDATE comments c
+SET parent_reply_id = COALESCE(c.parent_reply_id, c.comment_reply_id), comment_reply_id = p.comment_reply_id
+FROM comments p
+WHERE p.id = c.comment_reply_id AND p.comment_reply_id IS NOT NULL;
+`)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			break
+		}
+	}
+
+	if err := db.Exec(`WITH RECURSIVE tree AS (
+	SELECT id, 1 AS depth FROM comments
+	WHERE comment_reply_id IS NOT NULL AND parent_reply_id IS NULL
+	UNION ALL
+	SELECT c.id, tree.depth + 1 FROM comments c
+	JOIN tree ON c.parent_reply_id = tree.id
+)
+UPDATE comments c SET depth = tree.depth
+FROM tree
+WHERE tree.id = c.id AND c.depth <> tree.depth;
+`).Error; err != nil {
+		return err
+	}
+
+	// comments merged before consolidation stages existed
+	if err := db.Exec(`UPDATE comments SET stage = 'MERGED'
+WHERE merged_into_id IS NOT NULL AND stage = 'OFFICIAL';
+`).Error; err != nil {
+		return err
+	}
+
+	// full-text search over comments and documents, see the search repository
+	if err := db.Exec(`ALTER TABLE comments ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple', coalesce(comment, '')), 'A') ||
+	setweight(to_tsvector('simple', coalesce(section, '')), 'B') ||
+	setweight(to_tsvector('simple', coalesce(baseline, '')), 'C')
+) STORED;
+`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_comments_search_vector
+ON comments USING GIN(search_vector);
+`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`ALTER TABLE documents ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple', coalesce(company_document_number, '')), 'A') ||
+	setweight(to_tsvector('simple', coalesce(contractor_document_number, '')), 'A') ||
+	setweight(to_tsvector('simple', coalesce(document_title, '')), 'B') ||
+	setweight(to_tsvector('simple', coalesce(document_serial_number, '')), 'C')
+) STORED;
+`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_documents_search_vector
+ON documents USING GIN(search_vector);
+`).Error; err != nil {
+		return err
+	}
+
 	return nil
 }
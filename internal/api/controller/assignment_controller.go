@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	AssignmentController interface {
+		GetAllRule(ctx *gin.Context)
+		CreateRule(ctx *gin.Context)
+		UpdateRule(ctx *gin.Context)
+		DeleteRule(ctx *gin.Context)
+		Propose(ctx *gin.Context)
+		GetMatrix(ctx *gin.Context)
+		Apply(ctx *gin.Context)
+	}
+
+	assignmentController struct {
+		assignmentService service.AssignmentService
+	}
+)
+
+func NewAssignment(assignmentService service.AssignmentService) AssignmentController {
+	return &assignmentController{
+		assignmentService: assignmentService,
+	}
+}
+
+func (c *assignmentController) GetAllRule(ctx *gin.Context) {
+	disciplineGroupId := ctx.Param("discipline_group_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.assignmentService.GetAllRule(ctx.Request.Context(), userId, disciplineGroupId)
+	if err != nil {
+		response.NewFailed("failed get all assignment rules", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all assignment rules", res).Send(ctx)
+}
+
+func (c *assignmentController) CreateRule(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.AssignmentRuleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.AssignmentRuleRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.DisciplineGroupID = ctx.Param("discipline_group_id")
+	req.UserId = userId
+	res, err := c.assignmentService.CreateRule(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create assignment rule", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create assignment rule", res).Send(ctx)
+}
+
+func (c *assignmentController) UpdateRule(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.AssignmentRuleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.AssignmentRuleRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("assignment_rule_id")
+	req.DisciplineGroupID = ctx.Param("discipline_group_id")
+	req.UserId = userId
+	res, err := c.assignmentService.UpdateRule(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed update assignment rule", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success update assignment rule", res).Send(ctx)
+}
+
+func (c *assignmentController) DeleteRule(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	err = c.assignmentService.DeleteRule(ctx.Request.Context(), userId, ctx.Param("discipline_group_id"), ctx.Param("assignment_rule_id"))
+	if err != nil {
+		response.NewFailed("failed delete assignment rule", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete assignment rule", nil).Send(ctx)
+}
+
+func (c *assignmentController) Propose(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.AssignmentProposeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.AssignmentProposeRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.DisciplineGroupID = ctx.Param("discipline_group_id")
+	req.UserId = userId
+	res, err := c.assignmentService.Propose(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed propose assignment", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success propose assignment", res).Send(ctx)
+}
+
+func (c *assignmentController) GetMatrix(ctx *gin.Context) {
+	disciplineGroupId := ctx.Param("discipline_group_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.assignmentService.GetMatrix(ctx.Request.Context(), userId, disciplineGroupId)
+	if err != nil {
+		response.NewFailed("failed get assignment matrix", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get assignment matrix", res).Send(ctx)
+}
+
+func (c *assignmentController) Apply(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.AssignmentApplyRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.AssignmentApplyRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.DisciplineGroupID = ctx.Param("discipline_group_id")
+	req.UserId = userId
+	res, err := c.assignmentService.Apply(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed apply assignment", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success apply assignment", res).Send(ctx)
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	CommentClassController interface {
+		GetAll(ctx *gin.Context)
+		Create(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+	}
+
+	commentClassController struct {
+		commentClassService service.CommentClassService
+	}
+)
+
+func NewCommentClass(commentClassService service.CommentClassService) CommentClassController {
+	return &commentClassController{
+		commentClassService: commentClassService,
+	}
+}
+
+func (c *commentClassController) GetAll(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.commentClassService.GetAll(ctx.Request.Context(), userId, packageId)
+	if err != nil {
+		response.NewFailed("failed get all comment classes", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all comment classes", res).Send(ctx)
+}
+
+func (c *commentClassController) Create(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.CommentClassRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.CommentClassRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.commentClassService.Create(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create comment class", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create comment class", res).Send(ctx)
+}
+
+func (c *commentClassController) Update(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.CommentClassRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.CommentClassRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("comment_class_id")
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.commentClassService.Update(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed update comment class", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success update comment class", res).Send(ctx)
+}
+
+func (c *commentClassController) Delete(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	err = c.commentClassService.Delete(ctx.Request.Context(), userId, ctx.Param("id"), ctx.Param("comment_class_id"))
+	if err != nil {
+		response.NewFailed("failed delete comment class", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete comment class", nil).Send(ctx)
+}
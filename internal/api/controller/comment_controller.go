@@ -21,6 +21,12 @@ type (
 		GetById(ctx *gin.Context)
 		Update(ctx *gin.Context)
 		Delete(ctx *gin.Context)
+		GetDuplicates(ctx *gin.Context)
+		Merge(ctx *gin.Context)
+		Consolidate(ctx *gin.Context)
+		React(ctx *gin.Context)
+		Unreact(ctx *gin.Context)
+		GetRevisions(ctx *gin.Context)
 	}
 
 	commentController struct {
@@ -127,7 +133,13 @@ func (c *commentController) GetAllReplyByCommentId(ctx *gin.Context) {
 
 func (c *commentController) GetById(ctx *gin.Context) {
 	commentId := ctx.Param("comment_id")
-	result, err := c.commentService.GetById(ctx.Request.Context(), commentId)
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	result, err := c.commentService.GetById(ctx.Request.Context(), userId, commentId)
 	if err != nil {
 		response.NewFailed("failed get detail comment", err).Send(ctx)
 		return
@@ -180,3 +192,137 @@ func (c *commentController) Delete(ctx *gin.Context) {
 
 	response.NewSuccess("success delete comment", nil).Send(ctx)
 }
+
+func (c *commentController) GetDuplicates(ctx *gin.Context) {
+	disciplineListDocumentId := ctx.Param("discipline_list_document_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	clusters, err := c.commentService.GetDuplicates(ctx.Request.Context(), userId, disciplineListDocumentId)
+	if err != nil {
+		response.NewFailed("failed get duplicate comments", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get duplicate comments", clusters).Send(ctx)
+}
+
+func (c *commentController) Merge(ctx *gin.Context) {
+	disciplineListDocumentId := ctx.Param("discipline_list_document_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.MergeCommentRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.MergeCommentRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.DisciplineListDocumentId = disciplineListDocumentId
+	req.UserId = userId
+	comment, err := c.commentService.Merge(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed merge comments", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success merge comments", comment).Send(ctx)
+}
+
+func (c *commentController) Consolidate(ctx *gin.Context) {
+	disciplineListDocumentId := ctx.Param("discipline_list_document_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.ConsolidateCommentRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.ConsolidateCommentRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("comment_id")
+	req.DisciplineListDocumentId = disciplineListDocumentId
+	req.UserId = userId
+	comment, err := c.commentService.Consolidate(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed consolidate comment", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success consolidate comment", comment).Send(ctx)
+}
+
+func (c *commentController) React(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.CommentReactionRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.CommentReactionRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("comment_id")
+	req.DisciplineListDocumentId = ctx.Param("discipline_list_document_id")
+	req.UserId = userId
+	reactions, err := c.commentService.React(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed react to comment", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success react to comment", reactions).Send(ctx)
+}
+
+func (c *commentController) Unreact(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	req := dto.CommentReactionRequest{
+		ID:                       ctx.Param("comment_id"),
+		Type:                     ctx.Param("type"),
+		DisciplineListDocumentId: ctx.Param("discipline_list_document_id"),
+		UserId:                   userId,
+	}
+	reactions, err := c.commentService.Unreact(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed remove reaction", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success remove reaction", reactions).Send(ctx)
+}
+
+func (c *commentController) GetRevisions(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	revisions, err := c.commentService.GetRevisions(ctx.Request.Context(), userId, ctx.Param("discipline_list_document_id"), ctx.Param("comment_id"))
+	if err != nil {
+		response.NewFailed("failed get comment revisions", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get comment revisions", revisions).Send(ctx)
+}
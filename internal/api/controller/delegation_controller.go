@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	DelegationController interface {
+		GetAll(ctx *gin.Context)
+		Create(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+	}
+
+	delegationController struct {
+		delegationService service.DelegationService
+	}
+)
+
+func NewDelegation(delegationService service.DelegationService) DelegationController {
+	return &delegationController{
+		delegationService: delegationService,
+	}
+}
+
+func (c *delegationController) GetAll(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.delegationService.GetAll(ctx.Request.Context(), userId)
+	if err != nil {
+		response.NewFailed("failed get all delegations", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all delegations", res).Send(ctx)
+}
+
+func (c *delegationController) Create(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.DelegationRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.DelegationRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.UserId = userId
+	res, err := c.delegationService.Create(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create delegation", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create delegation", res).Send(ctx)
+}
+
+func (c *delegationController) Update(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.DelegationRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.DelegationRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("delegation_id")
+	req.UserId = userId
+	res, err := c.delegationService.Update(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed update delegation", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success update delegation", res).Send(ctx)
+}
+
+func (c *delegationController) Delete(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	err = c.delegationService.Delete(ctx.Request.Context(), userId, ctx.Param("delegation_id"))
+	if err != nil {
+		response.NewFailed("failed delete delegation", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete delegation", nil).Send(ctx)
+}
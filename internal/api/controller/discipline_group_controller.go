@@ -182,7 +182,13 @@ func (c *disciplineGroupController) GenerateExcel(ctx *gin.Context) {
 
 func (c *disciplineGroupController) Statistic(ctx *gin.Context) {
 	packageId := ctx.Param("package_id")
-	res, err := c.disciplineGroupService.GetStatistic(ctx.Request.Context(), packageId)
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.disciplineGroupService.GetStatistic(ctx.Request.Context(), userId, packageId)
 	if err != nil {
 		response.NewFailed("failed get statistic discipline group", err).Send(ctx)
 		return
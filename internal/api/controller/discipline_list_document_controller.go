@@ -16,11 +16,13 @@ import (
 type (
 	DisciplineListDocumentController interface {
 		Create(ctx *gin.Context)
+		CreateBulk(ctx *gin.Context)
 		GetAll(ctx *gin.Context)
 		GetById(ctx *gin.Context)
 		Update(ctx *gin.Context)
 		Delete(ctx *gin.Context)
 		GenerateExcel(ctx *gin.Context)
+		GenerateMarkupPDF(ctx *gin.Context)
 	}
 
 	disciplineListDocumentController struct {
@@ -61,6 +63,33 @@ func (c *disciplineListDocumentController) Create(ctx *gin.Context) {
 	response.NewSuccess("success create discipline list document", disciplineListDocument).Send(ctx)
 }
 
+func (c *disciplineListDocumentController) CreateBulk(ctx *gin.Context) {
+	disciplineGroupId := ctx.Param("discipline_group_id")
+
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.BulkDisciplineListDocumentRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.BulkDisciplineListDocumentRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.UserId = userId
+	req.DisciplineGroupID = disciplineGroupId
+	res, err := c.disciplineListDocumentService.CreateBulk(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create bulk discipline list document", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create bulk discipline list document", res).Send(ctx)
+}
+
 func (c *disciplineListDocumentController) GetAll(ctx *gin.Context) {
 	disciplineGroupId := ctx.Param("discipline_group_id")
 	userId, err := utils.GetUserIdFromCtx(ctx)
@@ -150,3 +179,22 @@ func (c *disciplineListDocumentController) GenerateExcel(ctx *gin.Context) {
 	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
 	ctx.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", excelBuffer.Bytes())
 }
+
+func (c *disciplineListDocumentController) GenerateMarkupPDF(ctx *gin.Context) {
+	disciplineListDocumentId := ctx.Param("discipline_list_document_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	pdfBuffer, filename, err := c.disciplineListDocumentService.GenerateMarkupPDF(ctx.Request.Context(), userId, disciplineListDocumentId)
+	if err != nil {
+		response.NewFailed("failed generate markup pdf", err).Send(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/pdf")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	ctx.Data(http.StatusOK, "application/pdf", pdfBuffer.Bytes())
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	DocumentRoutingRuleController interface {
+		GetAll(ctx *gin.Context)
+		Create(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+	}
+
+	documentRoutingRuleController struct {
+		documentRoutingRuleService service.DocumentRoutingRuleService
+	}
+)
+
+func NewDocumentRoutingRule(documentRoutingRuleService service.DocumentRoutingRuleService) DocumentRoutingRuleController {
+	return &documentRoutingRuleController{
+		documentRoutingRuleService: documentRoutingRuleService,
+	}
+}
+
+func (c *documentRoutingRuleController) GetAll(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.documentRoutingRuleService.GetAll(ctx.Request.Context(), userId, packageId)
+	if err != nil {
+		response.NewFailed("failed get all routing rules", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all routing rules", res).Send(ctx)
+}
+
+func (c *documentRoutingRuleController) Create(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.DocumentRoutingRuleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.DocumentRoutingRuleRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.documentRoutingRuleService.Create(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create routing rule", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create routing rule", res).Send(ctx)
+}
+
+func (c *documentRoutingRuleController) Update(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.DocumentRoutingRuleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.DocumentRoutingRuleRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("routing_rule_id")
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.documentRoutingRuleService.Update(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed update routing rule", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success update routing rule", res).Send(ctx)
+}
+
+func (c *documentRoutingRuleController) Delete(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	err = c.documentRoutingRuleService.Delete(ctx.Request.Context(), userId, ctx.Param("id"), ctx.Param("routing_rule_id"))
+	if err != nil {
+		response.NewFailed("failed delete routing rule", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete routing rule", nil).Send(ctx)
+}
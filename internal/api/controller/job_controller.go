@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	JobController interface {
+		Submit(ctx *gin.Context)
+		GetByID(ctx *gin.Context)
+		Download(ctx *gin.Context)
+	}
+
+	jobController struct {
+		jobService service.JobService
+	}
+)
+
+func NewJob(jobService service.JobService) JobController {
+	return &jobController{
+		jobService: jobService,
+	}
+}
+
+func (c *jobController) Submit(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.JobRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.JobRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.UserId = userId
+	res, err := c.jobService.Submit(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed submit job", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success submit job", res).Send(ctx)
+}
+
+func (c *jobController) GetByID(ctx *gin.Context) {
+	jobId := ctx.Param("job_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.jobService.GetByID(ctx.Request.Context(), userId, jobId)
+	if err != nil {
+		response.NewFailed("failed get job", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get job", res).Send(ctx)
+}
+
+func (c *jobController) Download(ctx *gin.Context) {
+	jobId := ctx.Param("job_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	artifact, err := c.jobService.GetArtifact(ctx.Request.Context(), userId, jobId)
+	if err != nil {
+		response.NewFailed("failed download job artifact", err).Send(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", artifact.ContentType)
+	ctx.FileAttachment(artifact.Path, artifact.Filename)
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	NotificationController interface {
+		GetAll(ctx *gin.Context)
+		Read(ctx *gin.Context)
+	}
+
+	notificationController struct {
+		notificationService service.NotificationService
+	}
+)
+
+func NewNotification(notificationService service.NotificationService) NotificationController {
+	return &notificationController{
+		notificationService: notificationService,
+	}
+}
+
+func (c *notificationController) GetAll(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	unreadOnly := ctx.Query("unread") == "true"
+	res, metaRes, err := c.notificationService.GetAll(ctx.Request.Context(), userId, unreadOnly, meta.NewWithDefault(ctx, 0, 0, "desc", "created_at"))
+	if err != nil {
+		response.NewFailed("failed get all notifications", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all notifications", res, metaRes).Send(ctx)
+}
+
+// Read marks the listed notifications as read, an empty body marks all
+func (c *notificationController) Read(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.ReadNotificationRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBind(&req); err != nil {
+			err = myerror.GetErrBodyRequest(err, dto.ReadNotificationRequest{})
+			response.NewFailed("failed get data from body", err).Send(ctx)
+			return
+		}
+	}
+
+	req.UserId = userId
+	if err := c.notificationService.Read(ctx.Request.Context(), req); err != nil {
+		response.NewFailed("failed read notifications", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success read notifications", nil).Send(ctx)
+}
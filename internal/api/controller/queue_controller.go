@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	QueueController interface {
+		GetQueue(ctx *gin.Context)
+	}
+
+	queueController struct {
+		queueService service.QueueService
+	}
+)
+
+func NewQueue(queueService service.QueueService) QueueController {
+	return &queueController{
+		queueService: queueService,
+	}
+}
+
+func (c *queueController) GetQueue(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, metaRes, err := c.queueService.GetQueue(ctx.Request.Context(), userId, meta.NewWithDefault(ctx, 0, 0, "asc", "urgency"))
+	if err != nil {
+		response.NewFailed("failed get review queue", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get review queue", res, metaRes).Send(ctx)
+}
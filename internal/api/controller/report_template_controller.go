@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	ReportTemplateController interface {
+		GetByPackageID(ctx *gin.Context)
+		Upsert(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+	}
+
+	reportTemplateController struct {
+		reportTemplateService service.ReportTemplateService
+	}
+)
+
+func NewReportTemplate(reportTemplateService service.ReportTemplateService) ReportTemplateController {
+	return &reportTemplateController{
+		reportTemplateService: reportTemplateService,
+	}
+}
+
+func (c *reportTemplateController) GetByPackageID(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.reportTemplateService.GetByPackageID(ctx.Request.Context(), userId, packageId)
+	if err != nil {
+		response.NewFailed("failed get report template", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get report template", res).Send(ctx)
+}
+
+func (c *reportTemplateController) Upsert(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.ReportTemplateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.ReportTemplateRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.PackageId = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.reportTemplateService.Upsert(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed save report template", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success save report template", res).Send(ctx)
+}
+
+func (c *reportTemplateController) Delete(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	if err := c.reportTemplateService.Delete(ctx.Request.Context(), userId, packageId); err != nil {
+		response.NewFailed("failed delete report template", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete report template", nil).Send(ctx)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	ReviewOutcomeController interface {
+		Get(ctx *gin.Context)
+		Set(ctx *gin.Context)
+	}
+
+	reviewOutcomeController struct {
+		reviewOutcomeService service.ReviewOutcomeService
+	}
+)
+
+func NewReviewOutcome(reviewOutcomeService service.ReviewOutcomeService) ReviewOutcomeController {
+	return &reviewOutcomeController{
+		reviewOutcomeService: reviewOutcomeService,
+	}
+}
+
+func (c *reviewOutcomeController) Get(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.reviewOutcomeService.Get(ctx.Request.Context(), userId, ctx.Param("document_id"))
+	if err != nil {
+		response.NewFailed("failed get review outcome", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get review outcome", res).Send(ctx)
+}
+
+func (c *reviewOutcomeController) Set(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.ReviewOutcomeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.ReviewOutcomeRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.DocumentID = ctx.Param("document_id")
+	req.UserId = userId
+	res, err := c.reviewOutcomeService.Set(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed set review outcome", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success set review outcome", res).Send(ctx)
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	ReviewWindowController interface {
+		GetAll(ctx *gin.Context)
+		Create(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+	}
+
+	reviewWindowController struct {
+		reviewWindowService service.ReviewWindowService
+	}
+)
+
+func NewReviewWindow(reviewWindowService service.ReviewWindowService) ReviewWindowController {
+	return &reviewWindowController{
+		reviewWindowService: reviewWindowService,
+	}
+}
+
+func (c *reviewWindowController) GetAll(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.reviewWindowService.GetAll(ctx.Request.Context(), userId, packageId, ctx.Query("discipline_group_id"))
+	if err != nil {
+		response.NewFailed("failed get all review windows", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all review windows", res).Send(ctx)
+}
+
+func (c *reviewWindowController) Create(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.ReviewWindowRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.ReviewWindowRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.reviewWindowService.Create(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create review window", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create review window", res).Send(ctx)
+}
+
+func (c *reviewWindowController) Update(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.ReviewWindowRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.ReviewWindowRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("review_window_id")
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.reviewWindowService.Update(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed update review window", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success update review window", res).Send(ctx)
+}
+
+func (c *reviewWindowController) Delete(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	err = c.reviewWindowService.Delete(ctx.Request.Context(), userId, ctx.Param("id"), ctx.Param("review_window_id"))
+	if err != nil {
+		response.NewFailed("failed delete review window", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete review window", nil).Send(ctx)
+}
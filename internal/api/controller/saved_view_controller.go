@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SavedViewController interface {
+		Create(ctx *gin.Context)
+		GetAll(ctx *gin.Context)
+		GetByID(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+	}
+
+	savedViewController struct {
+		savedViewService service.SavedViewService
+	}
+)
+
+func NewSavedView(savedViewService service.SavedViewService) SavedViewController {
+	return &savedViewController{
+		savedViewService: savedViewService,
+	}
+}
+
+func (c *savedViewController) Create(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.SavedViewRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.SavedViewRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.UserId = userId
+	res, err := c.savedViewService.Create(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create view", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create view", res).Send(ctx)
+}
+
+func (c *savedViewController) GetAll(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.savedViewService.GetAll(ctx.Request.Context(), userId, ctx.Query("resource"))
+	if err != nil {
+		response.NewFailed("failed get all views", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all views", res).Send(ctx)
+}
+
+func (c *savedViewController) GetByID(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.savedViewService.GetByID(ctx.Request.Context(), userId, ctx.Param("view_id"))
+	if err != nil {
+		response.NewFailed("failed get view", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get view", res).Send(ctx)
+}
+
+func (c *savedViewController) Update(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.SavedViewRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.SavedViewRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("view_id")
+	req.UserId = userId
+	res, err := c.savedViewService.Update(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed update view", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success update view", res).Send(ctx)
+}
+
+func (c *savedViewController) Delete(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	err = c.savedViewService.Delete(ctx.Request.Context(), userId, ctx.Param("view_id"))
+	if err != nil {
+		response.NewFailed("failed delete view", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete view", nil).Send(ctx)
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SearchController interface {
+		Search(ctx *gin.Context)
+	}
+
+	searchController struct {
+		searchService service.SearchService
+	}
+)
+
+func NewSearch(searchService service.SearchService) SearchController {
+	return &searchController{
+		searchService: searchService,
+	}
+}
+
+func (c *searchController) Search(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	req := dto.SearchRequest{
+		Keyword: ctx.Query("keyword"),
+		Type:    ctx.Query("type"),
+		UserId:  userId,
+	}
+
+	res, metaRes, err := c.searchService.Search(ctx.Request.Context(), req, meta.NewWithDefault(ctx, 20, 0, "desc", "rank"))
+	if err != nil {
+		response.NewFailed("failed search", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success search", res, metaRes).Send(ctx)
+}
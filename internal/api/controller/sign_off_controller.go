@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SignOffController interface {
+		Create(ctx *gin.Context)
+		GetByID(ctx *gin.Context)
+		Approve(ctx *gin.Context)
+		Reject(ctx *gin.Context)
+		Download(ctx *gin.Context)
+		Verify(ctx *gin.Context)
+	}
+
+	signOffController struct {
+		signOffService service.SignOffService
+	}
+)
+
+func NewSignOff(signOffService service.SignOffService) SignOffController {
+	return &signOffController{
+		signOffService: signOffService,
+	}
+}
+
+func (c *signOffController) Create(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.SignOffRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.SignOffRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.UserId = userId
+	res, err := c.signOffService.Create(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create sign-off", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create sign-off", res).Send(ctx)
+}
+
+func (c *signOffController) GetByID(ctx *gin.Context) {
+	signOffId := ctx.Param("sign_off_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.signOffService.GetByID(ctx.Request.Context(), userId, signOffId)
+	if err != nil {
+		response.NewFailed("failed get sign-off", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get sign-off", res).Send(ctx)
+}
+
+func (c *signOffController) Approve(ctx *gin.Context) {
+	req, ok := c.bindAction(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := c.signOffService.Approve(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed approve sign-off", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success approve sign-off", res).Send(ctx)
+}
+
+func (c *signOffController) Reject(ctx *gin.Context) {
+	req, ok := c.bindAction(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := c.signOffService.Reject(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed reject sign-off", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success reject sign-off", res).Send(ctx)
+}
+
+func (c *signOffController) Download(ctx *gin.Context) {
+	signOffId := ctx.Param("sign_off_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	path, filename, err := c.signOffService.GetArtifact(ctx.Request.Context(), userId, signOffId)
+	if err != nil {
+		response.NewFailed("failed download signed crs", err).Send(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/pdf")
+	ctx.FileAttachment(path, filename)
+}
+
+func (c *signOffController) Verify(ctx *gin.Context) {
+	var req dto.SignOffVerifyRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.SignOffVerifyRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	res, err := c.signOffService.Verify(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed verify signed crs", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success verify signed crs", res).Send(ctx)
+}
+
+func (c *signOffController) bindAction(ctx *gin.Context) (dto.SignOffActionRequest, bool) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return dto.SignOffActionRequest{}, false
+	}
+
+	// the note is optional, so an empty body is fine
+	var req dto.SignOffActionRequest
+	if ctx.Request.ContentLength == 0 {
+		req = dto.SignOffActionRequest{}
+	} else if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.SignOffActionRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return dto.SignOffActionRequest{}, false
+	}
+
+	req.UserId = userId
+	req.SignOffId = ctx.Param("sign_off_id")
+	return req, true
+}
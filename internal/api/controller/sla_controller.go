@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SLAController interface {
+		GetAllRules(ctx *gin.Context)
+		CreateRule(ctx *gin.Context)
+		UpdateRule(ctx *gin.Context)
+		DeleteRule(ctx *gin.Context)
+		GetAllHolidays(ctx *gin.Context)
+		CreateHoliday(ctx *gin.Context)
+		UpdateHoliday(ctx *gin.Context)
+		DeleteHoliday(ctx *gin.Context)
+	}
+
+	slaController struct {
+		slaService service.SLAService
+	}
+)
+
+func NewSLA(slaService service.SLAService) SLAController {
+	return &slaController{
+		slaService: slaService,
+	}
+}
+
+func (c *slaController) GetAllRules(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.slaService.GetAllRules(ctx.Request.Context(), userId, packageId)
+	if err != nil {
+		response.NewFailed("failed get all sla rules", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all sla rules", res).Send(ctx)
+}
+
+func (c *slaController) CreateRule(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.SLARuleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.SLARuleRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.slaService.CreateRule(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create sla rule", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create sla rule", res).Send(ctx)
+}
+
+func (c *slaController) UpdateRule(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.SLARuleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.SLARuleRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("sla_rule_id")
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.slaService.UpdateRule(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed update sla rule", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success update sla rule", res).Send(ctx)
+}
+
+func (c *slaController) DeleteRule(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	err = c.slaService.DeleteRule(ctx.Request.Context(), userId, ctx.Param("id"), ctx.Param("sla_rule_id"))
+	if err != nil {
+		response.NewFailed("failed delete sla rule", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete sla rule", nil).Send(ctx)
+}
+
+func (c *slaController) GetAllHolidays(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	// all years when left out
+	year, _ := strconv.Atoi(ctx.Query("year"))
+	res, err := c.slaService.GetAllHolidays(ctx.Request.Context(), userId, packageId, year)
+	if err != nil {
+		response.NewFailed("failed get all holidays", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all holidays", res).Send(ctx)
+}
+
+func (c *slaController) CreateHoliday(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.HolidayRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.HolidayRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.slaService.CreateHoliday(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create holiday", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create holiday", res).Send(ctx)
+}
+
+func (c *slaController) UpdateHoliday(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.HolidayRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.HolidayRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.ID = ctx.Param("holiday_id")
+	req.PackageID = ctx.Param("id")
+	req.UserId = userId
+	res, err := c.slaService.UpdateHoliday(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed update holiday", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success update holiday", res).Send(ctx)
+}
+
+func (c *slaController) DeleteHoliday(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	err = c.slaService.DeleteHoliday(ctx.Request.Context(), userId, ctx.Param("id"), ctx.Param("holiday_id"))
+	if err != nil {
+		response.NewFailed("failed delete holiday", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete holiday", nil).Send(ctx)
+}
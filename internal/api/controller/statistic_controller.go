@@ -1,18 +1,29 @@
 package controller
 
 import (
+	"net/http"
+
 	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
 	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
 	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
 type (
 	StatisticController interface {
 		GetAOCAndCommentChart(ctx *gin.Context)
+		GetCommentChart(ctx *gin.Context)
 		GetCommentCard(ctx *gin.Context)
 		GetCommentUserChart(ctx *gin.Context)
 		GetCommentUserData(ctx *gin.Context)
+		GetCommentCategoryChart(ctx *gin.Context)
+		GetCommentSeverityChart(ctx *gin.Context)
+		GetReviewOutcomeChart(ctx *gin.Context)
+		GetSLACompliance(ctx *gin.Context)
+		GetMe(ctx *gin.Context)
 	}
 
 	statisticController struct {
@@ -28,7 +39,44 @@ func NewStatistic(statisticService service.StatisticService) StatisticController
 
 func (c *statisticController) GetAOCAndCommentChart(ctx *gin.Context) {
 	packageId := ctx.Param("package_id")
-	res, err := c.statisticService.GetAOCAndCommentChart(ctx.Request.Context(), packageId)
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var filter dto.StatisticSeriesFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.StatisticSeriesFilter{})
+		response.NewFailed("failed get data from query", err).Send(ctx)
+		return
+	}
+
+	res, err := c.statisticService.GetAOCAndCommentChart(ctx.Request.Context(), userId, packageId, filter)
+	if err != nil {
+		response.NewFailed("failed to get statistic", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get statistic", res).Send(ctx)
+}
+
+func (c *statisticController) GetCommentChart(ctx *gin.Context) {
+	packageId := ctx.Param("package_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var filter dto.StatisticCommentChartFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.StatisticCommentChartFilter{})
+		response.NewFailed("failed get data from query", err).Send(ctx)
+		return
+	}
+
+	res, err := c.statisticService.GetCommentChart(ctx.Request.Context(), userId, packageId, filter)
 	if err != nil {
 		response.NewFailed("failed to get statistic", err).Send(ctx)
 		return
@@ -39,7 +87,13 @@ func (c *statisticController) GetAOCAndCommentChart(ctx *gin.Context) {
 
 func (c *statisticController) GetCommentCard(ctx *gin.Context) {
 	packageId := ctx.Param("package_id")
-	res, err := c.statisticService.GetCommentCard(ctx.Request.Context(), packageId)
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.statisticService.GetCommentCard(ctx.Request.Context(), userId, packageId)
 	if err != nil {
 		response.NewFailed("failed to get statistic", err).Send(ctx)
 		return
@@ -50,7 +104,13 @@ func (c *statisticController) GetCommentCard(ctx *gin.Context) {
 
 func (c *statisticController) GetCommentUserChart(ctx *gin.Context) {
 	packageId := ctx.Param("package_id")
-	res, err := c.statisticService.GetCommentUserChart(ctx.Request.Context(), packageId)
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.statisticService.GetCommentUserChart(ctx.Request.Context(), userId, packageId)
 	if err != nil {
 		response.NewFailed("failed to get statistic", err).Send(ctx)
 		return
@@ -61,7 +121,13 @@ func (c *statisticController) GetCommentUserChart(ctx *gin.Context) {
 
 func (c *statisticController) GetCommentUserData(ctx *gin.Context) {
 	packageId := ctx.Param("package_id")
-	res, metares, err := c.statisticService.GetCommentUserData(ctx.Request.Context(), packageId, meta.New(ctx))
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, metares, err := c.statisticService.GetCommentUserData(ctx.Request.Context(), userId, packageId, meta.New(ctx))
 	if err != nil {
 		response.NewFailed("failed to get statistic", err).Send(ctx)
 		return
@@ -69,3 +135,87 @@ func (c *statisticController) GetCommentUserData(ctx *gin.Context) {
 
 	response.NewSuccess("success get statistic", res, metares).Send(ctx)
 }
+
+func (c *statisticController) GetCommentCategoryChart(ctx *gin.Context) {
+	packageId := ctx.Param("package_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.statisticService.GetCommentCategoryChart(ctx.Request.Context(), userId, packageId)
+	if err != nil {
+		response.NewFailed("failed to get statistic", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get statistic", res).Send(ctx)
+}
+
+func (c *statisticController) GetCommentSeverityChart(ctx *gin.Context) {
+	packageId := ctx.Param("package_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.statisticService.GetCommentSeverityChart(ctx.Request.Context(), userId, packageId)
+	if err != nil {
+		response.NewFailed("failed to get statistic", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get statistic", res).Send(ctx)
+}
+
+func (c *statisticController) GetReviewOutcomeChart(ctx *gin.Context) {
+	packageId := ctx.Param("package_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.statisticService.GetReviewOutcomeChart(ctx.Request.Context(), userId, packageId)
+	if err != nil {
+		response.NewFailed("failed to get statistic", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get statistic", res).Send(ctx)
+}
+
+func (c *statisticController) GetSLACompliance(ctx *gin.Context) {
+	packageId := ctx.Param("package_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.statisticService.GetSLACompliance(ctx.Request.Context(), userId, packageId)
+	if err != nil {
+		response.NewFailed("failed to get statistic", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get statistic", res).Send(ctx)
+}
+
+func (c *statisticController) GetMe(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.statisticService.GetMe(ctx.Request.Context(), userId, ctx.Query("package_id"))
+	if err != nil {
+		response.NewFailed("failed to get statistic", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get statistic", res).Send(ctx)
+}
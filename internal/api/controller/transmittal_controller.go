@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/service"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	TransmittalController interface {
+		GetAll(ctx *gin.Context)
+		GetByID(ctx *gin.Context)
+		Create(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+		GenerateCoverSheet(ctx *gin.Context)
+	}
+
+	transmittalController struct {
+		transmittalService service.TransmittalService
+	}
+)
+
+func NewTransmittal(transmittalService service.TransmittalService) TransmittalController {
+	return &transmittalController{
+		transmittalService: transmittalService,
+	}
+}
+
+func (c *transmittalController) GetAll(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, metaRes, err := c.transmittalService.GetAll(ctx.Request.Context(), userId, packageId, meta.NewWithDefault(ctx, 0, 0, "desc", "transmittal_date"))
+	if err != nil {
+		response.NewFailed("failed get all transmittals", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get all transmittals", res, metaRes).Send(ctx)
+}
+
+func (c *transmittalController) GetByID(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	transmittalId := ctx.Param("transmittal_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	res, err := c.transmittalService.GetByID(ctx.Request.Context(), userId, packageId, transmittalId)
+	if err != nil {
+		response.NewFailed("failed get transmittal", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success get transmittal", res).Send(ctx)
+}
+
+func (c *transmittalController) Create(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.TransmittalRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.TransmittalRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.UserId = userId
+	req.PackageID = ctx.Param("id")
+	res, err := c.transmittalService.Create(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed create transmittal", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success create transmittal", res).Send(ctx)
+}
+
+func (c *transmittalController) Update(ctx *gin.Context) {
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	var req dto.TransmittalRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err = myerror.GetErrBodyRequest(err, dto.TransmittalRequest{})
+		response.NewFailed("failed get data from body", err).Send(ctx)
+		return
+	}
+
+	req.UserId = userId
+	req.PackageID = ctx.Param("id")
+	req.ID = ctx.Param("transmittal_id")
+	res, err := c.transmittalService.Update(ctx.Request.Context(), req)
+	if err != nil {
+		response.NewFailed("failed update transmittal", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success update transmittal", res).Send(ctx)
+}
+
+func (c *transmittalController) Delete(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	transmittalId := ctx.Param("transmittal_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	if err := c.transmittalService.Delete(ctx.Request.Context(), userId, packageId, transmittalId); err != nil {
+		response.NewFailed("failed delete transmittal", err).Send(ctx)
+		return
+	}
+
+	response.NewSuccess("success delete transmittal", nil).Send(ctx)
+}
+
+func (c *transmittalController) GenerateCoverSheet(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+	transmittalId := ctx.Param("transmittal_id")
+	userId, err := utils.GetUserIdFromCtx(ctx)
+	if err != nil {
+		response.NewFailed("failed get data from body", myerror.New(err.Error(), http.StatusBadRequest)).Send(ctx)
+		return
+	}
+
+	pdfBuffer, filename, err := c.transmittalService.GenerateCoverSheet(ctx.Request.Context(), userId, packageId, transmittalId)
+	if err != nil {
+		response.NewFailed("failed generate transmittal cover sheet", err).Send(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/pdf")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	ctx.Data(http.StatusOK, "application/pdf", pdfBuffer.Bytes())
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	AssignmentRuleRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, assignmentRule entity.AssignmentRule, preloads ...string) (entity.AssignmentRule, error)
+		GetByID(ctx context.Context, tx *gorm.DB, assignmentRuleId string, preloads ...string) (entity.AssignmentRule, error)
+		GetAllByDisciplineGroupID(ctx context.Context, tx *gorm.DB, disciplineGroupId string, preloads ...string) ([]entity.AssignmentRule, error)
+		Update(ctx context.Context, tx *gorm.DB, assignmentRule entity.AssignmentRule) (entity.AssignmentRule, error)
+		Delete(ctx context.Context, tx *gorm.DB, assignmentRule entity.AssignmentRule) error
+	}
+
+	assignmentRuleRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewAssignmentRule(db *gorm.DB) AssignmentRuleRepository {
+	return &assignmentRuleRepository{
+		db: db,
+	}
+}
+
+func (r *assignmentRuleRepository) Create(ctx context.Context, tx *gorm.DB, assignmentRule entity.AssignmentRule, preloads ...string) (entity.AssignmentRule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	if err := tx.WithContext(ctx).Create(&assignmentRule).Error; err != nil {
+		return entity.AssignmentRule{}, err
+	}
+
+	return assignmentRule, nil
+}
+
+func (r *assignmentRuleRepository) GetByID(ctx context.Context, tx *gorm.DB, assignmentRuleId string, preloads ...string) (entity.AssignmentRule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var assignmentRule entity.AssignmentRule
+	if err := tx.WithContext(ctx).Where("id = ?", assignmentRuleId).First(&assignmentRule).Error; err != nil {
+		return entity.AssignmentRule{}, err
+	}
+
+	return assignmentRule, nil
+}
+
+func (r *assignmentRuleRepository) GetAllByDisciplineGroupID(ctx context.Context, tx *gorm.DB, disciplineGroupId string, preloads ...string) ([]entity.AssignmentRule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var assignmentRules []entity.AssignmentRule
+	if err := tx.WithContext(ctx).
+		Where("discipline_group_id = ?", disciplineGroupId).
+		Order("priority asc, created_at asc").
+		Find(&assignmentRules).Error; err != nil {
+		return nil, err
+	}
+
+	return assignmentRules, nil
+}
+
+func (r *assignmentRuleRepository) Update(ctx context.Context, tx *gorm.DB, assignmentRule entity.AssignmentRule) (entity.AssignmentRule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).
+		Omit("UserDiscipline", "User", "DisciplineGroup").
+		Save(&assignmentRule).Error; err != nil {
+		return entity.AssignmentRule{}, err
+	}
+
+	return assignmentRule, nil
+}
+
+func (r *assignmentRuleRepository) Delete(ctx context.Context, tx *gorm.DB, assignmentRule entity.AssignmentRule) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if assignmentRule.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.AssignmentRule{}).
+			Where("id = ?", assignmentRule.ID).
+			Updates(map[string]interface{}{"deleted_by": assignmentRule.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&assignmentRule).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
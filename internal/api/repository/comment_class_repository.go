@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	CommentClassRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, commentClass entity.CommentClass) (entity.CommentClass, error)
+		GetByID(ctx context.Context, tx *gorm.DB, commentClassId string, preloads ...string) (entity.CommentClass, error)
+		GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, preloads ...string) ([]entity.CommentClass, error)
+		ExistsByCode(ctx context.Context, tx *gorm.DB, commentClass entity.CommentClass) (bool, error)
+		Update(ctx context.Context, tx *gorm.DB, commentClass entity.CommentClass) (entity.CommentClass, error)
+		Delete(ctx context.Context, tx *gorm.DB, commentClass entity.CommentClass) error
+	}
+
+	commentClassRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewCommentClass(db *gorm.DB) CommentClassRepository {
+	return &commentClassRepository{
+		db: db,
+	}
+}
+
+func (r *commentClassRepository) Create(ctx context.Context, tx *gorm.DB, commentClass entity.CommentClass) (entity.CommentClass, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Create(&commentClass).Error; err != nil {
+		return entity.CommentClass{}, err
+	}
+
+	return commentClass, nil
+}
+
+func (r *commentClassRepository) GetByID(ctx context.Context, tx *gorm.DB, commentClassId string, preloads ...string) (entity.CommentClass, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var commentClass entity.CommentClass
+	if err := tx.WithContext(ctx).Where("id = ?", commentClassId).First(&commentClass).Error; err != nil {
+		return entity.CommentClass{}, err
+	}
+
+	return commentClass, nil
+}
+
+// GetAllByPackageID lists the classes of the package by kind and rank
+func (r *commentClassRepository) GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, preloads ...string) ([]entity.CommentClass, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var commentClasses []entity.CommentClass
+	if err := tx.WithContext(ctx).
+		Where("package_id = ?", packageId).
+		Order("kind asc, rank asc, code asc").
+		Find(&commentClasses).Error; err != nil {
+		return nil, err
+	}
+
+	return commentClasses, nil
+}
+
+// ExistsByCode tells whether another class of the same kind in the package
+// already uses the code
+func (r *commentClassRepository) ExistsByCode(ctx context.Context, tx *gorm.DB, commentClass entity.CommentClass) (bool, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).Model(&entity.CommentClass{}).
+		Where("package_id = ? AND kind = ? AND LOWER(code) = LOWER(?) AND id <> ?", commentClass.PackageID, commentClass.Kind, commentClass.Code, commentClass.ID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *commentClassRepository) Update(ctx context.Context, tx *gorm.DB, commentClass entity.CommentClass) (entity.CommentClass, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).
+		Omit("Package").
+		Save(&commentClass).Error; err != nil {
+		return entity.CommentClass{}, err
+	}
+
+	return commentClass, nil
+}
+
+func (r *commentClassRepository) Delete(ctx context.Context, tx *gorm.DB, commentClass entity.CommentClass) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if commentClass.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.CommentClass{}).
+			Where("id = ?", commentClass.ID).
+			Updates(map[string]interface{}{"deleted_by": commentClass.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&commentClass).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/CRS-Project/crs-backend/internal/entity"
 	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type (
@@ -15,13 +18,30 @@ type (
 		GetByID(ctx context.Context, tx *gorm.DB, commentID string, preloads ...string) (entity.Comment, error)
 		GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error)
 		GetAllByDocumentID(ctx context.Context, tx *gorm.DB, documentId string, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error)
-		GetAllByDisciplineListDocumentID(ctx context.Context, tx *gorm.DB, disciplineListDocumentId string, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error)
-		GetAllByReplyID(ctx context.Context, tx *gorm.DB, replyId string, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error)
+		GetAllByDisciplineListDocumentID(ctx context.Context, tx *gorm.DB, disciplineListDocumentId string, scope CommentScope, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error)
+		GetAllByReplyID(ctx context.Context, tx *gorm.DB, replyId string, scope CommentScope, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error)
+		GetOpenByDisciplineListDocumentID(ctx context.Context, tx *gorm.DB, disciplineListDocumentId string, scope CommentScope, preloads ...string) ([]entity.Comment, error)
+		GetByIDs(ctx context.Context, tx *gorm.DB, commentIds []string, preloads ...string) ([]entity.Comment, error)
+		MergeInto(ctx context.Context, tx *gorm.DB, commentIds []string, targetId, updatedBy uuid.UUID) error
+		ReplaceMentions(ctx context.Context, tx *gorm.DB, commentId uuid.UUID, userIds []uuid.UUID) error
+		AddReaction(ctx context.Context, tx *gorm.DB, reaction entity.CommentReaction) error
+		RemoveReaction(ctx context.Context, tx *gorm.DB, commentId, userId string, reactionType entity.CommentReactionType) error
+		CreateRevision(ctx context.Context, tx *gorm.DB, revision entity.CommentRevision) error
+		GetRevisions(ctx context.Context, tx *gorm.DB, commentId string, preloads ...string) ([]entity.CommentRevision, error)
+		CountByClassID(ctx context.Context, tx *gorm.DB, commentClassId string) (int64, error)
 		Update(ctx context.Context, tx *gorm.DB, comment entity.Comment, preloads ...string) error
 		Delete(ctx context.Context, tx *gorm.DB, comment entity.Comment, preloads ...string) error
 		DeleteByDisciplineListDocumentID(ctx context.Context, tx *gorm.DB, disciplineListDocumentID []string) error
 	}
 
+	// CommentScope narrows comments down to what the caller may read, the
+	// zero value reads everything. PublicOnly also applies to the preloaded
+	// replies.
+	CommentScope struct {
+		Stages     []entity.CommentStage
+		PublicOnly bool
+	}
+
 	commentRepository struct {
 		db *gorm.DB
 	}
@@ -35,7 +55,7 @@ func NewComment(db *gorm.DB) CommentRepository {
 
 func (r *commentRepository) Create(ctx context.Context, tx *gorm.DB, comment entity.Comment, preloads ...string) (entity.Comment, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -57,7 +77,7 @@ func (r *commentRepository) Create(ctx context.Context, tx *gorm.DB, comment ent
 
 func (r *commentRepository) GetByID(ctx context.Context, tx *gorm.DB, commentID string, preloads ...string) (entity.Comment, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -74,7 +94,7 @@ func (r *commentRepository) GetByID(ctx context.Context, tx *gorm.DB, commentID
 
 func (r *commentRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -84,16 +104,17 @@ func (r *commentRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq met
 	var comments []entity.Comment
 
 	tx = tx.WithContext(ctx).Model(&entity.Comment{})
-	if err := WithFilters(tx, &metaReq, AddModels(entity.Comment{})).Find(&comments).Error; err != nil {
+	if err := WithFilters(tx, &metaReq, AddModels(entity.Comment{}), WithCursor()).Find(&comments).Error; err != nil {
 		return nil, meta.Meta{}, err
 	}
 
+	SetCursor(&metaReq, &comments)
 	return comments, metaReq, nil
 }
 
 func (r *commentRepository) GetAllByDocumentID(ctx context.Context, tx *gorm.DB, documentId string, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -103,54 +124,207 @@ func (r *commentRepository) GetAllByDocumentID(ctx context.Context, tx *gorm.DB,
 	var comments []entity.Comment
 
 	tx = tx.WithContext(ctx).Model(&entity.Comment{}).Where("document_id = ? AND comment_reply_id IS NULL", documentId)
-	if err := WithFilters(tx, &metaReq, AddModels(entity.Comment{})).Find(&comments).Error; err != nil {
+	if err := WithFilters(tx, &metaReq, AddModels(entity.Comment{}), WithCursor()).Find(&comments).Error; err != nil {
 		return nil, meta.Meta{}, err
 	}
 
+	SetCursor(&metaReq, &comments)
 	return comments, metaReq, nil
 }
 
-func (r *commentRepository) GetAllByDisciplineListDocumentID(ctx context.Context, tx *gorm.DB, disciplineListDocumentId string, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error) {
+// GetAllByDisciplineListDocumentID lists the top level comments that were not
+// merged, limited to the given stages when there are any
+func (r *commentRepository) GetAllByDisciplineListDocumentID(ctx context.Context, tx *gorm.DB, disciplineListDocumentId string, scope CommentScope, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
-		tx = tx.Preload(preload)
+		tx = scope.preload(tx, preload)
 	}
 
 	var comments []entity.Comment
 
-	tx = tx.WithContext(ctx).Model(&entity.Comment{}).Where("discipline_list_document_id = ? AND comment_reply_id IS NULL", disciplineListDocumentId)
-	if err := WithFilters(tx, &metaReq, AddModels(entity.Comment{})).Find(&comments).Error; err != nil {
+	tx = tx.WithContext(ctx).Model(&entity.Comment{}).Where("discipline_list_document_id = ? AND comment_reply_id IS NULL AND merged_into_id IS NULL", disciplineListDocumentId)
+	tx = scope.apply(tx)
+
+	if err := WithFilters(tx, &metaReq, AddModels(entity.Comment{}), WithCursor()).Find(&comments).Error; err != nil {
 		return nil, meta.Meta{}, err
 	}
 
+	SetCursor(&metaReq, &comments)
 	return comments, metaReq, nil
 }
 
-func (r *commentRepository) GetAllByReplyID(ctx context.Context, tx *gorm.DB, replyId string, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error) {
+func (r *commentRepository) GetAllByReplyID(ctx context.Context, tx *gorm.DB, replyId string, scope CommentScope, metaReq meta.Meta, preloads ...string) ([]entity.Comment, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
-		tx = tx.Preload(preload)
+		tx = scope.preload(tx, preload)
 	}
 
 	var comments []entity.Comment
 
 	tx = tx.WithContext(ctx).Model(&entity.Comment{}).Where("comment_reply_id = ?", replyId)
-	if err := WithFilters(tx, &metaReq, AddModels(entity.Comment{})).Find(&comments).Error; err != nil {
+	tx = scope.apply(tx)
+	if err := WithFilters(tx, &metaReq, AddModels(entity.Comment{}), WithCursor()).Find(&comments).Error; err != nil {
 		return nil, meta.Meta{}, err
 	}
 
+	SetCursor(&metaReq, &comments)
 	return comments, metaReq, nil
 }
 
+// GetOpenByDisciplineListDocumentID returns the top level comments without a
+// status that were neither merged nor discarded within the scope, oldest
+// first
+func (r *commentRepository) GetOpenByDisciplineListDocumentID(ctx context.Context, tx *gorm.DB, disciplineListDocumentId string, scope CommentScope, preloads ...string) ([]entity.Comment, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var comments []entity.Comment
+	tx = tx.WithContext(ctx).
+		Where("discipline_list_document_id = ? AND comment_reply_id IS NULL AND merged_into_id IS NULL AND status IS NULL AND stage <> ?", disciplineListDocumentId, entity.CommentStageDiscarded)
+	if err := scope.apply(tx).
+		Order("created_at asc").
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *commentRepository) GetByIDs(ctx context.Context, tx *gorm.DB, commentIds []string, preloads ...string) ([]entity.Comment, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if len(commentIds) == 0 {
+		return []entity.Comment{}, nil
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var comments []entity.Comment
+	if err := tx.WithContext(ctx).Where("id IN ?", commentIds).Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// MergeInto links the comments, and the ones merged into them before, to the
+// comment they are merged into
+func (r *commentRepository) MergeInto(ctx context.Context, tx *gorm.DB, commentIds []string, targetId, updatedBy uuid.UUID) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Comment{}).
+		Where("id IN ? OR merged_into_id IN ?", commentIds, commentIds).
+		Updates(map[string]interface{}{"merged_into_id": targetId, "stage": entity.CommentStageMerged, "updated_by": updatedBy}).Error
+}
+
+// ReplaceMentions sets the users mentioned in the comment
+func (r *commentRepository) ReplaceMentions(ctx context.Context, tx *gorm.DB, commentId uuid.UUID, userIds []uuid.UUID) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Where("comment_id = ?", commentId).Delete(&entity.CommentMention{}).Error; err != nil {
+		return err
+	}
+
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	mentions := make([]entity.CommentMention, len(userIds))
+	for i, userId := range userIds {
+		mentions[i] = entity.CommentMention{CommentID: commentId, UserID: userId}
+	}
+
+	return tx.WithContext(ctx).Create(&mentions).Error
+}
+
+// AddReaction does nothing when the user already gave this reaction
+func (r *commentRepository) AddReaction(ctx context.Context, tx *gorm.DB, reaction entity.CommentReaction) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	return tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "comment_id"}, {Name: "user_id"}, {Name: "type"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(&reaction).Error
+}
+
+func (r *commentRepository) RemoveReaction(ctx context.Context, tx *gorm.DB, commentId, userId string, reactionType entity.CommentReactionType) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	return tx.WithContext(ctx).
+		Where("comment_id = ? AND user_id = ? AND type = ?", commentId, userId, reactionType).
+		Delete(&entity.CommentReaction{}).Error
+}
+
+func (r *commentRepository) CreateRevision(ctx context.Context, tx *gorm.DB, revision entity.CommentRevision) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	return tx.WithContext(ctx).Create(&revision).Error
+}
+
+// GetRevisions lists the earlier versions of the comment, latest first
+func (r *commentRepository) GetRevisions(ctx context.Context, tx *gorm.DB, commentId string, preloads ...string) ([]entity.CommentRevision, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var revisions []entity.CommentRevision
+	if err := tx.WithContext(ctx).Where("comment_id = ?", commentId).Order("created_at DESC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// CountByClassID counts the comments classified with the class, either as
+// their category or as their severity
+func (r *commentRepository) CountByClassID(ctx context.Context, tx *gorm.DB, commentClassId string) (int64, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).Model(&entity.Comment{}).
+		Where("category_id = ? OR severity_id = ?", commentClassId, commentClassId).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (r *commentRepository) Update(ctx context.Context, tx *gorm.DB, comment entity.Comment, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -166,7 +340,7 @@ func (r *commentRepository) Update(ctx context.Context, tx *gorm.DB, comment ent
 
 func (r *commentRepository) Delete(ctx context.Context, tx *gorm.DB, comment entity.Comment, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -191,7 +365,7 @@ func (r *commentRepository) Delete(ctx context.Context, tx *gorm.DB, comment ent
 
 func (r *commentRepository) DeleteByDisciplineListDocumentID(ctx context.Context, tx *gorm.DB, disciplineListDocumentID []string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	if err := tx.WithContext(ctx).Where("discipline_list_document_id IN (?)", disciplineListDocumentID).Delete(&entity.Comment{}).Error; err != nil {
@@ -200,3 +374,48 @@ func (r *commentRepository) DeleteByDisciplineListDocumentID(ctx context.Context
 
 	return nil
 }
+
+func (s CommentScope) apply(tx *gorm.DB) *gorm.DB {
+	if len(s.Stages) > 0 {
+		tx = tx.Where("stage IN ?", s.Stages)
+	}
+
+	if s.PublicOnly {
+		tx = tx.Where("visibility = ?", entity.CommentVisibilityPublic)
+	}
+
+	return tx
+}
+
+// condition keeps the comments aliased as alias that are not deleted and
+// within the scope, for the raw queries that can't go through apply
+func (s CommentScope) condition(alias string) string {
+	conditions := []string{fmt.Sprintf("%s.deleted_at IS NULL", alias)}
+	if len(s.Stages) > 0 {
+		stages := make([]string, len(s.Stages))
+		for i, stage := range s.Stages {
+			stages[i] = fmt.Sprintf("'%s'", stage)
+		}
+		conditions = append(conditions, fmt.Sprintf("%s.stage IN (%s)", alias, strings.Join(stages, ", ")))
+	}
+
+	if s.PublicOnly {
+		conditions = append(conditions, fmt.Sprintf("%s.visibility = '%s'", alias, entity.CommentVisibilityPublic))
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+// replyCondition keeps the replies aliased as alias that are not deleted and
+// within the scope, replies take the visibility but not the stage of scope
+func (s CommentScope) replyCondition(alias string) string {
+	return CommentScope{PublicOnly: s.PublicOnly}.condition(alias)
+}
+
+func (s CommentScope) preload(tx *gorm.DB, preload string) *gorm.DB {
+	if s.PublicOnly && preload == "CommentReplies" {
+		return tx.Preload(preload, "visibility = ?", entity.CommentVisibilityPublic)
+	}
+
+	return tx.Preload(preload)
+}
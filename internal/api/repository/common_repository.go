@@ -16,12 +16,27 @@ var (
 	ErrSortBy           = errors.New("invalid sort (must be 'asc' or 'desc')")
 	ErrInvalidTypeModel = errors.New("invalid type model")
 	ErrInvalidField     = errors.New("invalid filter or sort field")
+	ErrCursor           = errors.New("cursor pagination is not supported here")
 )
 
+// hiddenFields are never filtered, sorted or queried on, whatever the model
+var hiddenFields = map[string]bool{
+	"password": true,
+}
+
 type MetaService struct {
 	Filter map[string]string
 	Sorter map[string]string
-	DB     *gorm.DB
+	// columns the q expression may compare, keyed by their json name
+	Columns map[string]string
+	DB      *gorm.DB
+	// table of the first model, its id breaks ties in cursor pagination
+	Table string
+	// json names of the fields of the first model, the rows read carry them
+	// so a cursor can point at them
+	RowFields map[string]bool
+	// the rows are finished with SetCursor, see WithCursor
+	Cursor bool
 }
 
 type Option func(*MetaService)
@@ -33,9 +48,11 @@ type Option func(*MetaService)
 // Dont forget to set model in GORM query tx.Model(enitity{})
 func WithFilters(db *gorm.DB, m *meta.Meta, opts ...Option) *gorm.DB {
 	metaService := MetaService{
-		Filter: make(map[string]string),
-		Sorter: make(map[string]string),
-		DB:     db,
+		Filter:    make(map[string]string),
+		Sorter:    make(map[string]string),
+		Columns:   make(map[string]string),
+		RowFields: make(map[string]bool),
+		DB:        db,
 	}
 
 	for _, opt := range opts {
@@ -58,6 +75,29 @@ func AddModels(model any) Option {
 			mylog.Errorln(err)
 		}
 		tableName := stmt.Schema.Table
+		first := ms.Table == ""
+		if first {
+			ms.Table = tableName
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+
+			jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
+			if jsonTag == "" || jsonTag == "-" {
+				jsonTag = field.DBName
+			}
+			if hiddenFields[jsonTag] || hiddenFields[field.DBName] {
+				continue
+			}
+
+			ms.Columns[jsonTag] = fmt.Sprintf("%s.%s", tableName, field.DBName)
+			if first {
+				ms.RowFields[jsonTag] = true
+			}
+		}
 
 		v := reflect.TypeOf(model)
 		if v.Kind() == reflect.Ptr {
@@ -72,6 +112,10 @@ func AddModels(model any) Option {
 					jsonTag = field.Name
 				}
 
+				if hiddenFields[jsonTag] {
+					continue
+				}
+
 				fullField := fmt.Sprintf("%s.%s", tableName, jsonTag)
 
 				if field.Anonymous {
@@ -105,10 +149,19 @@ func AddCustomField(field string, filterquery string, alias ...string) Option {
 		ms.Sorter[field] = field
 		if len(alias) > 0 {
 			ms.Sorter[field] = alias[0]
+			ms.Columns[field] = alias[0]
 		}
 	}
 }
 
+// WithCursor lets the list be paged with a cursor. The rows read must be
+// passed to SetCursor, a list without it answers a cursor with ErrCursor.
+func WithCursor() Option {
+	return func(ms *MetaService) {
+		ms.Cursor = true
+	}
+}
+
 func (ms *MetaService) buildFilter(db *gorm.DB, meta *meta.Meta) *gorm.DB {
 	query := db
 
@@ -153,6 +206,22 @@ func (ms *MetaService) buildFilter(db *gorm.DB, meta *meta.Meta) *gorm.DB {
 		}
 	}
 
+	if meta.Query != "" {
+		query = ms.buildQuery(query, meta.Query)
+		if err := query.Error; err != nil {
+			return query
+		}
+	}
+
+	if meta.UseCursor {
+		if !ms.Cursor {
+			query.Error = ErrCursor
+			return query
+		}
+
+		return ms.buildCursor(query, meta)
+	}
+
 	if meta.SortBy != "" {
 		if _, ok := ms.Sorter[meta.SortBy]; !ok {
 			query.Error = ErrInvalidTypeModel
@@ -186,6 +255,301 @@ func (ms *MetaService) buildFilter(db *gorm.DB, meta *meta.Meta) *gorm.DB {
 	return query
 }
 
+// ValidateMeta checks list params against the fields registered by opts
+// without running a query, for params stored to be used later
+func ValidateMeta(db *gorm.DB, m meta.Meta, opts ...Option) error {
+	ms := MetaService{
+		Filter:    make(map[string]string),
+		Sorter:    make(map[string]string),
+		Columns:   make(map[string]string),
+		RowFields: make(map[string]bool),
+		DB:        db,
+	}
+
+	for _, opt := range opts {
+		opt(&ms)
+	}
+
+	if m.Sort != "" && m.Sort != "asc" && m.Sort != "desc" {
+		return ErrSortBy
+	}
+
+	if m.SortBy != "" {
+		if _, ok := ms.Sorter[m.SortBy]; !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidField, m.SortBy)
+		}
+	}
+
+	if m.FilterBy != "" || m.Filter != "" {
+		filterBy := strings.Split(m.FilterBy, ",")
+		if len(filterBy) != len(strings.Split(m.Filter, ",")) {
+			return fmt.Errorf("%w: filter and filter_by must have the same length", ErrInvalidField)
+		}
+
+		for _, field := range filterBy {
+			if _, ok := ms.Filter[field]; !ok {
+				return fmt.Errorf("%w: %s", ErrInvalidField, field)
+			}
+		}
+	}
+
+	if m.Query != "" {
+		expr, err := meta.ParseQuery(m.Query)
+		if err != nil {
+			return err
+		}
+
+		for _, clause := range expr {
+			for _, condition := range clause {
+				if _, ok := ms.Columns[condition.Field]; !ok {
+					return fmt.Errorf("%w: %s", ErrInvalidField, condition.Field)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildQuery applies the q expression. Only the columns registered through
+// AddModels or an aliased AddCustomField can be compared.
+func (ms *MetaService) buildQuery(query *gorm.DB, q string) *gorm.DB {
+	expr, err := meta.ParseQuery(q)
+	if err != nil {
+		query.Error = err
+		return query
+	}
+
+	for _, clause := range expr {
+		var (
+			conditions []string
+			args       []any
+		)
+
+		for _, condition := range clause {
+			column, ok := ms.Columns[condition.Field]
+			if !ok {
+				query.Error = fmt.Errorf("%w: %s", ErrInvalidField, condition.Field)
+				return query
+			}
+
+			sql, values := queryCondition(column, condition)
+			conditions = append(conditions, sql)
+			args = append(args, values...)
+		}
+
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+
+	return query
+}
+
+func queryCondition(column string, condition meta.Condition) (string, []any) {
+	switch condition.Operator {
+	case meta.OpIsNull:
+		return fmt.Sprintf("%s IS NULL", column), nil
+	case meta.OpIsNotNull:
+		return fmt.Sprintf("%s IS NOT NULL", column), nil
+	case meta.OpIn:
+		return fmt.Sprintf("%s IN ?", column), []any{condition.Values}
+	case meta.OpNotIn:
+		return fmt.Sprintf("(%s IS NULL OR %s NOT IN ?)", column, column), []any{condition.Values}
+	case meta.OpContains:
+		return fmt.Sprintf("CAST(%s AS TEXT) ILIKE ?", column), []any{"%" + EscapeLike(condition.Values[0]) + "%"}
+	case meta.OpNotContains:
+		return fmt.Sprintf("(%s IS NULL OR CAST(%s AS TEXT) NOT ILIKE ?)", column, column), []any{"%" + EscapeLike(condition.Values[0]) + "%"}
+	case meta.OpNotEqual:
+		return fmt.Sprintf("%s IS DISTINCT FROM ?", column), []any{condition.Values[0]}
+	default:
+		return fmt.Sprintf("%s %s ?", column, condition.Operator), []any{condition.Values[0]}
+	}
+}
+
+// buildCursor orders by the sort key with the id as tie breaker and reads
+// one row past the page, so SetCursor can tell whether more rows follow
+func (ms *MetaService) buildCursor(query *gorm.DB, m *meta.Meta) *gorm.DB {
+	idColumn := "id"
+	if ms.Table != "" {
+		idColumn = ms.Table + ".id"
+	}
+
+	sortColumn := idColumn
+	if m.SortBy != "" {
+		column, ok := ms.Sorter[m.SortBy]
+		if !ok {
+			query.Error = ErrInvalidTypeModel
+			return query
+		}
+
+		// the cursor takes the sort value from the row, computed sorts
+		// aren't on it
+		if !ms.RowFields[m.SortBy] {
+			query.Error = fmt.Errorf("%w: %s can't be used with a cursor", ErrInvalidField, m.SortBy)
+			return query
+		}
+		sortColumn = column
+	}
+
+	if m.Sort != "asc" && m.Sort != "desc" {
+		query.Error = ErrSortBy
+		return query
+	}
+
+	cursor, err := m.DecodeCursor()
+	if err != nil {
+		query.Error = err
+		return query
+	}
+
+	direction := m.Sort
+	if cursor != nil && cursor.Prev {
+		direction = map[string]string{"asc": "desc", "desc": "asc"}[direction]
+	}
+
+	if cursor != nil {
+		condition, args := keysetCondition(sortColumn, idColumn, direction, cursor.Value, cursor.ID)
+		query = query.Where(condition, args...)
+	}
+
+	order := fmt.Sprintf("%s %s", sortColumn, direction)
+	if sortColumn != idColumn {
+		order += fmt.Sprintf(", %s %s", idColumn, direction)
+	}
+
+	m.Page = 0
+	m.TotalData = 0
+	m.TotalPage = 0
+	return query.Order(order).Limit(m.Take + 1)
+}
+
+// keysetCondition selects the rows after (value, id) in the given order.
+// Postgres puts nulls last when ascending and first when descending.
+func keysetCondition(sortColumn, idColumn, direction string, value, id any) (string, []any) {
+	op := ">"
+	if direction == "desc" {
+		op = "<"
+	}
+
+	if sortColumn == idColumn {
+		return fmt.Sprintf("%s %s ?", idColumn, op), []any{id}
+	}
+
+	switch {
+	case value == nil && direction == "asc":
+		return fmt.Sprintf("(%s IS NULL AND %s %s ?)", sortColumn, idColumn, op), []any{id}
+	case value == nil:
+		return fmt.Sprintf("((%s IS NULL AND %s %s ?) OR %s IS NOT NULL)", sortColumn, idColumn, op, sortColumn), []any{id}
+	case direction == "asc":
+		return fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?) OR %s IS NULL)", sortColumn, op, sortColumn, idColumn, op, sortColumn), []any{value, value, id}
+	default:
+		return fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", sortColumn, op, sortColumn, idColumn, op), []any{value, value, id}
+	}
+}
+
+// SetCursor finishes a cursor page read through WithFilters: it drops the
+// extra row, restores the order of a backward page and fills the next and
+// previous cursors from the rows' json fields. It does nothing for offset
+// pagination.
+func SetCursor[T any](m *meta.Meta, rows *[]T) {
+	if !m.UseCursor {
+		return
+	}
+
+	cursor, err := m.DecodeCursor()
+	if err != nil {
+		return
+	}
+	prev := cursor != nil && cursor.Prev
+
+	hasMore := len(*rows) > m.Take
+	if hasMore {
+		*rows = (*rows)[:m.Take]
+	}
+
+	items := *rows
+	if prev {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	m.NextCursor, m.PrevCursor = "", ""
+	if len(items) == 0 {
+		return
+	}
+
+	sortBy := m.SortBy
+	if sortBy == "" {
+		sortBy = "id"
+	}
+
+	makeCursor := func(row T, prev bool) string {
+		value, _ := jsonField(reflect.ValueOf(row), sortBy)
+		id, _ := jsonField(reflect.ValueOf(row), "id")
+		return meta.EncodeCursor(meta.Cursor{SortBy: m.SortBy, Sort: m.Sort, Value: value, ID: id, Prev: prev})
+	}
+
+	if prev || hasMore {
+		m.NextCursor = makeCursor(items[len(items)-1], false)
+	}
+
+	if (prev && hasMore) || (!prev && cursor != nil) {
+		m.PrevCursor = makeCursor(items[0], true)
+	}
+}
+
+// jsonField returns the value of the field with the given json name, looking
+// into embedded structs. Nil pointers give nil.
+func jsonField(v reflect.Value, name string) (any, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			if value, ok := jsonField(v.Field(i), name); ok {
+				return value, true
+			}
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "" {
+			tag = field.Name
+		}
+
+		if tag != name {
+			continue
+		}
+
+		value := v.Field(i)
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return nil, true
+			}
+			value = value.Elem()
+		}
+
+		return value.Interface(), true
+	}
+
+	return nil, false
+}
+
+// EscapeLike escapes the wildcards of s so a LIKE pattern matches it as is
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func paginate(page, perPage int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Offset(page).Limit(perPage)
@@ -204,6 +568,10 @@ func addEmbeddedFields(embedType reflect.Type, ms *MetaService, tablePrefix stri
 				jsonTag = field.Name
 			}
 
+			if hiddenFields[jsonTag] {
+				continue
+			}
+
 			fullField := fmt.Sprintf("%s.%s", tablePrefix, jsonTag)
 
 			switch field.Type.Kind() {
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	DelegationRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, delegation entity.Delegation, preloads ...string) (entity.Delegation, error)
+		GetByID(ctx context.Context, tx *gorm.DB, delegationId string, preloads ...string) (entity.Delegation, error)
+		GetAllByUserID(ctx context.Context, tx *gorm.DB, userId string, preloads ...string) ([]entity.Delegation, error)
+		GetActiveByUserIDs(ctx context.Context, tx *gorm.DB, userIds []string, at time.Time, preloads ...string) ([]entity.Delegation, error)
+		GetActiveBySubstituteID(ctx context.Context, tx *gorm.DB, substituteId string, at time.Time, preloads ...string) ([]entity.Delegation, error)
+		GetOverlapping(ctx context.Context, tx *gorm.DB, userId string, startAt, endAt time.Time, excludeId string) ([]entity.Delegation, error)
+		Update(ctx context.Context, tx *gorm.DB, delegation entity.Delegation) (entity.Delegation, error)
+		Delete(ctx context.Context, tx *gorm.DB, delegation entity.Delegation) error
+	}
+
+	delegationRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewDelegation(db *gorm.DB) DelegationRepository {
+	return &delegationRepository{
+		db: db,
+	}
+}
+
+func (r *delegationRepository) Create(ctx context.Context, tx *gorm.DB, delegation entity.Delegation, preloads ...string) (entity.Delegation, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	if err := tx.WithContext(ctx).Create(&delegation).Error; err != nil {
+		return entity.Delegation{}, err
+	}
+
+	return delegation, nil
+}
+
+func (r *delegationRepository) GetByID(ctx context.Context, tx *gorm.DB, delegationId string, preloads ...string) (entity.Delegation, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var delegation entity.Delegation
+	if err := tx.WithContext(ctx).Where("id = ?", delegationId).First(&delegation).Error; err != nil {
+		return entity.Delegation{}, err
+	}
+
+	return delegation, nil
+}
+
+// GetAllByUserID returns the delegations the user gave and the ones they
+// received, latest first
+func (r *delegationRepository) GetAllByUserID(ctx context.Context, tx *gorm.DB, userId string, preloads ...string) ([]entity.Delegation, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var delegations []entity.Delegation
+	if err := tx.WithContext(ctx).
+		Where("user_id = ? OR substitute_id = ?", userId, userId).
+		Order("start_at desc").
+		Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+func (r *delegationRepository) GetActiveByUserIDs(ctx context.Context, tx *gorm.DB, userIds []string, at time.Time, preloads ...string) ([]entity.Delegation, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if len(userIds) == 0 {
+		return []entity.Delegation{}, nil
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var delegations []entity.Delegation
+	if err := tx.WithContext(ctx).
+		Where("user_id IN ? AND start_at <= ? AND end_at > ?", userIds, at, at).
+		Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+func (r *delegationRepository) GetActiveBySubstituteID(ctx context.Context, tx *gorm.DB, substituteId string, at time.Time, preloads ...string) ([]entity.Delegation, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var delegations []entity.Delegation
+	if err := tx.WithContext(ctx).
+		Where("substitute_id = ? AND start_at <= ? AND end_at > ?", substituteId, at, at).
+		Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+// GetOverlapping returns the delegations of the user that share at least a
+// moment with the given range
+func (r *delegationRepository) GetOverlapping(ctx context.Context, tx *gorm.DB, userId string, startAt, endAt time.Time, excludeId string) ([]entity.Delegation, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	tx = tx.WithContext(ctx).Where("user_id = ? AND start_at < ? AND end_at > ?", userId, endAt, startAt)
+	if excludeId != "" {
+		tx = tx.Where("id <> ?", excludeId)
+	}
+
+	var delegations []entity.Delegation
+	if err := tx.Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+func (r *delegationRepository) Update(ctx context.Context, tx *gorm.DB, delegation entity.Delegation) (entity.Delegation, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).
+		Omit("User", "Substitute").
+		Save(&delegation).Error; err != nil {
+		return entity.Delegation{}, err
+	}
+
+	return delegation, nil
+}
+
+func (r *delegationRepository) Delete(ctx context.Context, tx *gorm.DB, delegation entity.Delegation) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if delegation.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.Delegation{}).
+			Where("id = ?", delegation.ID).
+			Updates(map[string]interface{}{"deleted_by": delegation.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&delegation).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/CRS-Project/crs-backend/internal/dto"
 	"github.com/CRS-Project/crs-backend/internal/entity"
 	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
 	"github.com/google/uuid"
@@ -17,6 +18,8 @@ type (
 		GetAllConsolidator(ctx context.Context, tx *gorm.DB, search, disciplineGroupId string, preloads ...string) ([]entity.DisciplineGroupConsolidator, error)
 		GetByID(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidatorID string, preloads ...string) (entity.DisciplineGroupConsolidator, error)
 		GetByUserID(ctx context.Context, tx *gorm.DB, userID string, preloads ...string) ([]entity.DisciplineGroupConsolidator, error)
+		GetByIDs(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidatorIDs []string, preloads ...string) ([]entity.DisciplineGroupConsolidator, error)
+		GetReviewerLoad(ctx context.Context, tx *gorm.DB, userIDs []string) ([]dto.ReviewerLoad, error)
 		Update(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidator entity.DisciplineGroupConsolidator, preloads ...string) error
 		Delete(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidator entity.DisciplineGroupConsolidator, preloads ...string) error
 		DeleteByID(ctx context.Context, tx *gorm.DB, id string) error
@@ -38,7 +41,7 @@ func NewDisciplineGroupConsolidator(db *gorm.DB) DisciplineGroupConsolidatorRepo
 
 func (r *disciplineGroupConsolidatorRepository) Create(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidator entity.DisciplineGroupConsolidator, preloads ...string) (entity.DisciplineGroupConsolidator, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -54,7 +57,7 @@ func (r *disciplineGroupConsolidatorRepository) Create(ctx context.Context, tx *
 
 func (r *disciplineGroupConsolidatorRepository) CreateBulk(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidators []entity.DisciplineGroupConsolidator, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -70,7 +73,7 @@ func (r *disciplineGroupConsolidatorRepository) CreateBulk(ctx context.Context,
 
 func (r *disciplineGroupConsolidatorRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.DisciplineGroupConsolidator, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -89,7 +92,7 @@ func (r *disciplineGroupConsolidatorRepository) GetAll(ctx context.Context, tx *
 
 func (r *disciplineGroupConsolidatorRepository) GetAllConsolidator(ctx context.Context, tx *gorm.DB, search, disciplineGroupId string, preloads ...string) ([]entity.DisciplineGroupConsolidator, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -115,7 +118,7 @@ func (r *disciplineGroupConsolidatorRepository) GetAllConsolidator(ctx context.C
 
 func (r *disciplineGroupConsolidatorRepository) GetByID(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidatorID string, preloads ...string) (entity.DisciplineGroupConsolidator, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -132,7 +135,7 @@ func (r *disciplineGroupConsolidatorRepository) GetByID(ctx context.Context, tx
 
 func (r *disciplineGroupConsolidatorRepository) GetByUserID(ctx context.Context, tx *gorm.DB, userID string, preloads ...string) ([]entity.DisciplineGroupConsolidator, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -147,9 +150,64 @@ func (r *disciplineGroupConsolidatorRepository) GetByUserID(ctx context.Context,
 	return disciplineGroupConsolidator, nil
 }
 
+func (r *disciplineGroupConsolidatorRepository) GetByIDs(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidatorIDs []string, preloads ...string) ([]entity.DisciplineGroupConsolidator, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if len(disciplineGroupConsolidatorIDs) == 0 {
+		return []entity.DisciplineGroupConsolidator{}, nil
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var disciplineGroupConsolidator []entity.DisciplineGroupConsolidator
+	if err := tx.WithContext(ctx).Find(&disciplineGroupConsolidator, "id IN ?", disciplineGroupConsolidatorIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return disciplineGroupConsolidator, nil
+}
+
+// GetReviewerLoad counts, over every discipline group, the open comments on
+// the documents each user consolidates and the number of those documents
+func (r *disciplineGroupConsolidatorRepository) GetReviewerLoad(ctx context.Context, tx *gorm.DB, userIDs []string) ([]dto.ReviewerLoad, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	query := `
+	SELECT
+		dgc.user_id,
+		COUNT(DISTINCT dldc.discipline_list_document_id) AS assigned_document,
+		COUNT(DISTINCT c.id) AS open_comment
+	FROM discipline_group_consolidators dgc
+	JOIN discipline_list_document_consolidators dldc ON dldc.discipline_group_consolidator_id = dgc.id
+		AND dldc.deleted_at IS NULL
+	JOIN discipline_list_documents dld ON dld.id = dldc.discipline_list_document_id
+		AND dld.deleted_at IS NULL
+	LEFT JOIN comments c ON c.discipline_list_document_id = dld.id
+		AND c.deleted_at IS NULL
+		AND c.comment_reply_id IS NULL
+		AND c.status IS NULL
+	WHERE dgc.deleted_at IS NULL
+		AND dgc.user_id IN ?
+	GROUP BY dgc.user_id;
+	`
+
+	var loads []dto.ReviewerLoad
+	if err := tx.WithContext(ctx).Raw(query, userIDs).Scan(&loads).Error; err != nil {
+		return nil, err
+	}
+
+	return loads, nil
+}
+
 func (r *disciplineGroupConsolidatorRepository) Update(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidator entity.DisciplineGroupConsolidator, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -165,7 +223,7 @@ func (r *disciplineGroupConsolidatorRepository) Update(ctx context.Context, tx *
 
 func (r *disciplineGroupConsolidatorRepository) Delete(ctx context.Context, tx *gorm.DB, disciplineGroupConsolidator entity.DisciplineGroupConsolidator, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -190,7 +248,7 @@ func (r *disciplineGroupConsolidatorRepository) Delete(ctx context.Context, tx *
 
 func (r *disciplineGroupConsolidatorRepository) DeleteByID(ctx context.Context, tx *gorm.DB, id string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	if err := tx.WithContext(ctx).
@@ -204,7 +262,7 @@ func (r *disciplineGroupConsolidatorRepository) DeleteByID(ctx context.Context,
 
 func (r *disciplineGroupConsolidatorRepository) DeleteByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	if err := tx.WithContext(ctx).
@@ -218,7 +276,7 @@ func (r *disciplineGroupConsolidatorRepository) DeleteByUserID(ctx context.Conte
 
 func (r *disciplineGroupConsolidatorRepository) DeleteByDisciplineGroupID(ctx context.Context, tx *gorm.DB, disciplineGroupID string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	if err := tx.WithContext(ctx).
@@ -232,7 +290,7 @@ func (r *disciplineGroupConsolidatorRepository) DeleteByDisciplineGroupID(ctx co
 
 func (r *disciplineGroupConsolidatorRepository) DeleteBulk(ctx context.Context, tx *gorm.DB, disciplineGroupConcolidatorIDs []string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	if err := tx.WithContext(ctx).
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/CRS-Project/crs-backend/internal/dto"
 	"github.com/CRS-Project/crs-backend/internal/entity"
@@ -17,7 +18,7 @@ type (
 		GetByID(ctx context.Context, tx *gorm.DB, disciplineGroupID string, preloads ...string) (entity.DisciplineGroup, error)
 		Update(ctx context.Context, tx *gorm.DB, disciplineGroup entity.DisciplineGroup, preloads ...string) error
 		Delete(ctx context.Context, tx *gorm.DB, disciplineGroup entity.DisciplineGroup, preloads ...string) error
-		Statistic(ctx context.Context, tx *gorm.DB, packageId string) (dto.DisciplineGroupStatistic, error)
+		Statistic(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope) (dto.DisciplineGroupStatistic, error)
 	}
 
 	disciplineGroupRepository struct {
@@ -33,7 +34,7 @@ func NewDisciplineGroup(db *gorm.DB) DisciplineGroupRepository {
 
 func (r *disciplineGroupRepository) Create(ctx context.Context, tx *gorm.DB, disciplineGroup entity.DisciplineGroup, preloads ...string) (entity.DisciplineGroup, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -49,7 +50,7 @@ func (r *disciplineGroupRepository) Create(ctx context.Context, tx *gorm.DB, dis
 
 func (r *disciplineGroupRepository) GetAll(ctx context.Context, tx *gorm.DB, packageId string, metaReq meta.Meta, preloads ...string) ([]entity.DisciplineGroup, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -80,7 +81,7 @@ func (r *disciplineGroupRepository) GetAll(ctx context.Context, tx *gorm.DB, pac
 
 func (r *disciplineGroupRepository) GetByID(ctx context.Context, tx *gorm.DB, disciplineGroupID string, preloads ...string) (entity.DisciplineGroup, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -97,7 +98,7 @@ func (r *disciplineGroupRepository) GetByID(ctx context.Context, tx *gorm.DB, di
 
 func (r *disciplineGroupRepository) Update(ctx context.Context, tx *gorm.DB, disciplineGroup entity.DisciplineGroup, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -113,7 +114,7 @@ func (r *disciplineGroupRepository) Update(ctx context.Context, tx *gorm.DB, dis
 
 func (r *disciplineGroupRepository) Delete(ctx context.Context, tx *gorm.DB, disciplineGroup entity.DisciplineGroup, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -136,20 +137,20 @@ func (r *disciplineGroupRepository) Delete(ctx context.Context, tx *gorm.DB, dis
 	return nil
 }
 
-func (r *disciplineGroupRepository) Statistic(ctx context.Context, tx *gorm.DB, packageId string) (dto.DisciplineGroupStatistic, error) {
+func (r *disciplineGroupRepository) Statistic(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope) (dto.DisciplineGroupStatistic, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	var stats dto.DisciplineGroupStatistic
-	err := tx.Raw(`
+	err := tx.Raw(fmt.Sprintf(`
 		SELECT
 		(SELECT COUNT(*) FROM discipline_groups ag WHERE ag.package_id = ? AND deleted_at is null) AS total_discipline_group,
 		(SELECT COUNT(*) FROM discipline_list_documents a WHERE a.package_id = ? AND deleted_at is null) AS total_discipline_list_document,
 		(SELECT COUNT(*) FROM comments c
 			JOIN discipline_list_documents a ON a.id = c.discipline_list_document_id
-			WHERE a.package_id = ? AND c.comment_reply_id IS NULL AND c.deleted_at is null) AS total_comment;
-	`, packageId, packageId, packageId).Scan(&stats).Error
+			WHERE a.package_id = ? AND c.comment_reply_id IS NULL AND %s) AS total_comment;
+	`, scope.condition("c")), packageId, packageId, packageId).Scan(&stats).Error
 
 	if err != nil {
 		return dto.DisciplineGroupStatistic{}, err
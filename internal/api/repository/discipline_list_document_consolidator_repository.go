@@ -35,7 +35,7 @@ func NewDisciplineListDocumentConsolidator(db *gorm.DB) DisciplineListDocumentCo
 
 func (r *disciplineListDocumentConsolidatorRepository) Create(ctx context.Context, tx *gorm.DB, disciplineListDocumentConsolidator entity.DisciplineListDocumentConsolidator, preloads ...string) (entity.DisciplineListDocumentConsolidator, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -51,7 +51,7 @@ func (r *disciplineListDocumentConsolidatorRepository) Create(ctx context.Contex
 
 func (r *disciplineListDocumentConsolidatorRepository) CreateBulk(ctx context.Context, tx *gorm.DB, disciplineListDocumentConsolidators []entity.DisciplineListDocumentConsolidator, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -67,7 +67,7 @@ func (r *disciplineListDocumentConsolidatorRepository) CreateBulk(ctx context.Co
 
 func (r *disciplineListDocumentConsolidatorRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.DisciplineListDocumentConsolidator, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -86,7 +86,7 @@ func (r *disciplineListDocumentConsolidatorRepository) GetAll(ctx context.Contex
 
 func (r *disciplineListDocumentConsolidatorRepository) GetByID(ctx context.Context, tx *gorm.DB, disciplineListDocumentConsolidatorID string, preloads ...string) (entity.DisciplineListDocumentConsolidator, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -103,7 +103,7 @@ func (r *disciplineListDocumentConsolidatorRepository) GetByID(ctx context.Conte
 
 func (r *disciplineListDocumentConsolidatorRepository) Update(ctx context.Context, tx *gorm.DB, disciplineListDocumentConsolidator entity.DisciplineListDocumentConsolidator, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -119,7 +119,7 @@ func (r *disciplineListDocumentConsolidatorRepository) Update(ctx context.Contex
 
 func (r *disciplineListDocumentConsolidatorRepository) Delete(ctx context.Context, tx *gorm.DB, disciplineListDocumentConsolidator entity.DisciplineListDocumentConsolidator, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -144,7 +144,7 @@ func (r *disciplineListDocumentConsolidatorRepository) Delete(ctx context.Contex
 
 func (r *disciplineListDocumentConsolidatorRepository) DeleteByDisciplineListDocumentID(ctx context.Context, tx *gorm.DB, disciplineListDocumentID []string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	if err := tx.WithContext(ctx).
@@ -158,7 +158,7 @@ func (r *disciplineListDocumentConsolidatorRepository) DeleteByDisciplineListDoc
 
 func (r *disciplineListDocumentConsolidatorRepository) DeleteByDisciplineGroupConsolidatorID(ctx context.Context, tx *gorm.DB, disciplineGroupIDs []string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	if err := tx.WithContext(ctx).
@@ -172,7 +172,7 @@ func (r *disciplineListDocumentConsolidatorRepository) DeleteByDisciplineGroupCo
 
 func (r *disciplineListDocumentConsolidatorRepository) DeleteBulk(ctx context.Context, tx *gorm.DB, disciplineListDocumentConcolidatorIDs []string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	if err := tx.WithContext(ctx).
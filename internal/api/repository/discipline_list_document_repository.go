@@ -14,6 +14,7 @@ type (
 		Create(ctx context.Context, tx *gorm.DB, disciplineListDocument entity.DisciplineListDocument, preloads ...string) (entity.DisciplineListDocument, error)
 		GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.DisciplineListDocument, meta.Meta, error)
 		GetAllByDisciplineGroupID(ctx context.Context, tx *gorm.DB, disciplineGroupId string, metaReq meta.Meta, preloads ...string) ([]entity.DisciplineListDocument, meta.Meta, error)
+		GetAllByDocumentIDs(ctx context.Context, tx *gorm.DB, disciplineGroupId string, documentIds []string, preloads ...string) ([]entity.DisciplineListDocument, error)
 		GetByID(ctx context.Context, tx *gorm.DB, disciplineListDocumentID string, preloads ...string) (entity.DisciplineListDocument, error)
 		Update(ctx context.Context, tx *gorm.DB, disciplineListDocument entity.DisciplineListDocument, preloads ...string) error
 		Delete(ctx context.Context, tx *gorm.DB, disciplineListDocument entity.DisciplineListDocument, preloads ...string) error
@@ -33,7 +34,7 @@ func NewDisciplineListDocument(db *gorm.DB) DisciplineListDocumentRepository {
 
 func (r *disciplineListDocumentRepository) Create(ctx context.Context, tx *gorm.DB, disciplineListDocument entity.DisciplineListDocument, preloads ...string) (entity.DisciplineListDocument, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -49,7 +50,7 @@ func (r *disciplineListDocumentRepository) Create(ctx context.Context, tx *gorm.
 
 func (r *disciplineListDocumentRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.DisciplineListDocument, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -67,9 +68,19 @@ func (r *disciplineListDocumentRepository) GetAll(ctx context.Context, tx *gorm.
 	return disciplineListDocuments, metaReq, nil
 }
 
+// disciplineListDocumentFilterOptions are the fields GetAllByDisciplineGroupID
+// filters and sorts on
+func disciplineListDocumentFilterOptions() []Option {
+	return []Option{
+		AddModels(entity.DisciplineListDocument{}),
+		AddCustomField("search", ""),
+		AddCustomField("due_date", "", "d.due_date"),
+	}
+}
+
 func (r *disciplineListDocumentRepository) GetAllByDisciplineGroupID(ctx context.Context, tx *gorm.DB, disciplineGroupId string, metaReq meta.Meta, preloads ...string) ([]entity.DisciplineListDocument, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -83,9 +94,9 @@ func (r *disciplineListDocumentRepository) GetAllByDisciplineGroupID(ctx context
 	filterMap := metaReq.SeparateFilter()
 
 	// Check if we need to join documents
-	// Join if search is present OR if sorting by due_date
+	// Join if search is present OR if due_date is sorted, filtered or queried on
 	_, hasSearch := filterMap["search"]
-	if hasSearch || metaReq.SortBy == "due_date" {
+	if hasSearch || metaReq.Uses("due_date") {
 		tx = tx.Joins("LEFT JOIN documents d ON d.id = discipline_list_documents.document_id")
 	}
 
@@ -94,18 +105,40 @@ func (r *disciplineListDocumentRepository) GetAllByDisciplineGroupID(ctx context
 			"%"+find+"%",
 			"%"+find+"%")
 	}
-	if err := WithFilters(tx, &metaReq, AddModels(entity.DisciplineListDocument{}),
-		AddCustomField("search", ""),
-		AddCustomField("due_date", "", "d.due_date")).Find(&disciplineListDocuments).Error; err != nil {
+	if err := WithFilters(tx, &metaReq, disciplineListDocumentFilterOptions()...).Find(&disciplineListDocuments).Error; err != nil {
 		return nil, meta.Meta{}, err
 	}
 
 	return disciplineListDocuments, metaReq, nil
 }
 
+// GetAllByDocumentIDs returns the discipline list documents of a group
+// without pagination, all of them when documentIds is empty
+func (r *disciplineListDocumentRepository) GetAllByDocumentIDs(ctx context.Context, tx *gorm.DB, disciplineGroupId string, documentIds []string, preloads ...string) ([]entity.DisciplineListDocument, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	tx = tx.WithContext(ctx).Where("discipline_group_id = ?", disciplineGroupId)
+	if len(documentIds) > 0 {
+		tx = tx.Where("document_id IN ?", documentIds)
+	}
+
+	var disciplineListDocuments []entity.DisciplineListDocument
+	if err := tx.Order("created_at asc").Find(&disciplineListDocuments).Error; err != nil {
+		return nil, err
+	}
+
+	return disciplineListDocuments, nil
+}
+
 func (r *disciplineListDocumentRepository) GetByID(ctx context.Context, tx *gorm.DB, disciplineListDocumentID string, preloads ...string) (entity.DisciplineListDocument, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -122,7 +155,7 @@ func (r *disciplineListDocumentRepository) GetByID(ctx context.Context, tx *gorm
 
 func (r *disciplineListDocumentRepository) Update(ctx context.Context, tx *gorm.DB, disciplineListDocument entity.DisciplineListDocument, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -138,7 +171,7 @@ func (r *disciplineListDocumentRepository) Update(ctx context.Context, tx *gorm.
 
 func (r *disciplineListDocumentRepository) Delete(ctx context.Context, tx *gorm.DB, disciplineListDocument entity.DisciplineListDocument, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -163,7 +196,7 @@ func (r *disciplineListDocumentRepository) Delete(ctx context.Context, tx *gorm.
 
 func (r *disciplineListDocumentRepository) DeleteByDisciplineGroupID(ctx context.Context, tx *gorm.DB, disciplineGroupID string, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
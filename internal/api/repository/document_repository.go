@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/CRS-Project/crs-backend/internal/dto"
 	"github.com/CRS-Project/crs-backend/internal/entity"
 	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
 	"github.com/google/uuid"
@@ -12,8 +15,10 @@ import (
 type (
 	DocumentRepository interface {
 		GetByID(ctx context.Context, tx *gorm.DB, documentID string, preloads ...string) (entity.Document, error)
+		GetByIDs(ctx context.Context, tx *gorm.DB, documentIDs []string, preloads ...string) ([]entity.Document, error)
+		GetAllByFilter(ctx context.Context, tx *gorm.DB, packageId string, filter dto.DocumentFilter, preloads ...string) ([]entity.Document, error)
 		Create(ctx context.Context, tx *gorm.DB, document entity.Document, preloads ...string) (entity.Document, error)
-		GetAll(ctx context.Context, tx *gorm.DB, packageId string, metaReq meta.Meta, preloads ...string) ([]entity.Document, meta.Meta, error)
+		GetAll(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, metaReq meta.Meta, preloads ...string) ([]entity.Document, meta.Meta, error)
 		Delete(ctx context.Context, tx *gorm.DB, document entity.Document, preloads ...string) error
 		Update(ctx context.Context, tx *gorm.DB, document entity.Document, preloads ...string) (entity.Document, error)
 	}
@@ -31,7 +36,7 @@ func NewDocument(db *gorm.DB) DocumentRepository {
 
 func (r *documentRepository) Create(ctx context.Context, tx *gorm.DB, document entity.Document, preloads ...string) (entity.Document, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -53,7 +58,7 @@ func (r *documentRepository) Create(ctx context.Context, tx *gorm.DB, document e
 
 func (r *documentRepository) GetByID(ctx context.Context, tx *gorm.DB, documentID string, preloads ...string) (entity.Document, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -68,9 +73,87 @@ func (r *documentRepository) GetByID(ctx context.Context, tx *gorm.DB, documentI
 	return document, nil
 }
 
-func (r *documentRepository) GetAll(ctx context.Context, tx *gorm.DB, packageId string, metaReq meta.Meta, preloads ...string) ([]entity.Document, meta.Meta, error) {
+func (r *documentRepository) GetByIDs(ctx context.Context, tx *gorm.DB, documentIDs []string, preloads ...string) ([]entity.Document, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var documents []entity.Document
+	if err := tx.WithContext(ctx).
+		Where("id IN ?", documentIDs).
+		Order("company_document_number asc").
+		Find(&documents).Error; err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+func (r *documentRepository) GetAllByFilter(ctx context.Context, tx *gorm.DB, packageId string, filter dto.DocumentFilter, preloads ...string) ([]entity.Document, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	tx = tx.WithContext(ctx).Where("package_id = ?", packageId)
+	if len(filter.DocumentIDs) > 0 {
+		tx = tx.Where("id IN ?", filter.DocumentIDs)
+	}
+
+	for column, values := range map[string][]string{
+		"discipline":        filter.Disciplines,
+		"sub_discipline":    filter.SubDisciplines,
+		"document_type":     filter.DocumentTypes,
+		"document_category": filter.DocumentCategories,
+		"status":            filter.Statuses,
+	} {
+		if len(values) == 0 {
+			continue
+		}
+
+		lowered := make([]string, len(values))
+		for i, v := range values {
+			lowered[i] = strings.ToLower(strings.TrimSpace(v))
+		}
+		tx = tx.Where(fmt.Sprintf("LOWER(TRIM(%s)) IN ?", column), lowered)
+	}
+
+	var documents []entity.Document
+	if err := tx.Order("company_document_number asc").Find(&documents).Error; err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+// documentCommentCount counts the comments within scope of a document across
+// its discipline lists, e.g. q=comment_count=0 lists documents nobody
+// commented on
+func documentCommentCount(scope CommentScope) string {
+	return fmt.Sprintf(`(SELECT COUNT(*) FROM comments c
+	JOIN discipline_list_documents dld ON dld.id = c.discipline_list_document_id AND dld.deleted_at IS NULL
+	WHERE dld.document_id = documents.id AND %s)`, scope.condition("c"))
+}
+
+// documentFilterOptions are the fields GetAll filters and sorts on
+func documentFilterOptions(scope CommentScope) []Option {
+	return []Option{
+		AddModels(entity.Document{}),
+		AddCustomField("search", ""),
+		AddCustomField("comment_count", "", documentCommentCount(scope)),
+	}
+}
+
+func (r *documentRepository) GetAll(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, metaReq meta.Meta, preloads ...string) ([]entity.Document, meta.Meta, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -96,18 +179,18 @@ func (r *documentRepository) GetAll(ctx context.Context, tx *gorm.DB, packageId
 			"%"+find+"%")
 	}
 
-	if err := WithFilters(tx, &metaReq, AddModels(entity.Document{}),
-		AddCustomField("search", "")).
+	if err := WithFilters(tx, &metaReq, append(documentFilterOptions(scope), WithCursor())...).
 		Find(&documents).Error; err != nil {
 		return nil, meta.Meta{}, err
 	}
 
+	SetCursor(&metaReq, &documents)
 	return documents, metaReq, nil
 }
 
 func (r *documentRepository) Update(ctx context.Context, tx *gorm.DB, document entity.Document, preloads ...string) (entity.Document, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -123,7 +206,7 @@ func (r *documentRepository) Update(ctx context.Context, tx *gorm.DB, document e
 
 func (r *documentRepository) Delete(ctx context.Context, tx *gorm.DB, document entity.Document, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
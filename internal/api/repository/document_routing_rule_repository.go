@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	DocumentRoutingRuleRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, documentRoutingRule entity.DocumentRoutingRule) (entity.DocumentRoutingRule, error)
+		GetByID(ctx context.Context, tx *gorm.DB, documentRoutingRuleId string, preloads ...string) (entity.DocumentRoutingRule, error)
+		GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, preloads ...string) ([]entity.DocumentRoutingRule, error)
+		Update(ctx context.Context, tx *gorm.DB, documentRoutingRule entity.DocumentRoutingRule) (entity.DocumentRoutingRule, error)
+		Delete(ctx context.Context, tx *gorm.DB, documentRoutingRule entity.DocumentRoutingRule) error
+	}
+
+	documentRoutingRuleRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewDocumentRoutingRule(db *gorm.DB) DocumentRoutingRuleRepository {
+	return &documentRoutingRuleRepository{
+		db: db,
+	}
+}
+
+func (r *documentRoutingRuleRepository) Create(ctx context.Context, tx *gorm.DB, documentRoutingRule entity.DocumentRoutingRule) (entity.DocumentRoutingRule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Create(&documentRoutingRule).Error; err != nil {
+		return entity.DocumentRoutingRule{}, err
+	}
+
+	return documentRoutingRule, nil
+}
+
+func (r *documentRoutingRuleRepository) GetByID(ctx context.Context, tx *gorm.DB, documentRoutingRuleId string, preloads ...string) (entity.DocumentRoutingRule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var documentRoutingRule entity.DocumentRoutingRule
+	if err := tx.WithContext(ctx).Where("id = ?", documentRoutingRuleId).First(&documentRoutingRule).Error; err != nil {
+		return entity.DocumentRoutingRule{}, err
+	}
+
+	return documentRoutingRule, nil
+}
+
+func (r *documentRoutingRuleRepository) GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, preloads ...string) ([]entity.DocumentRoutingRule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var documentRoutingRules []entity.DocumentRoutingRule
+	if err := tx.WithContext(ctx).
+		Where("package_id = ?", packageId).
+		Order("discipline asc, document_type asc").
+		Find(&documentRoutingRules).Error; err != nil {
+		return nil, err
+	}
+
+	return documentRoutingRules, nil
+}
+
+func (r *documentRoutingRuleRepository) Update(ctx context.Context, tx *gorm.DB, documentRoutingRule entity.DocumentRoutingRule) (entity.DocumentRoutingRule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).
+		Omit("Package", "DisciplineGroup").
+		Save(&documentRoutingRule).Error; err != nil {
+		return entity.DocumentRoutingRule{}, err
+	}
+
+	return documentRoutingRule, nil
+}
+
+func (r *documentRoutingRuleRepository) Delete(ctx context.Context, tx *gorm.DB, documentRoutingRule entity.DocumentRoutingRule) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if documentRoutingRule.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.DocumentRoutingRule{}).
+			Where("id = ?", documentRoutingRule.ID).
+			Updates(map[string]interface{}{"deleted_by": documentRoutingRule.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&documentRoutingRule).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
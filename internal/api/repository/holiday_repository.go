@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	HolidayRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, holiday entity.Holiday) (entity.Holiday, error)
+		GetByID(ctx context.Context, tx *gorm.DB, holidayId string, preloads ...string) (entity.Holiday, error)
+		GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, year int) ([]entity.Holiday, error)
+		GetAllFrom(ctx context.Context, tx *gorm.DB, packageId string, from time.Time) ([]entity.Holiday, error)
+		ExistsByDate(ctx context.Context, tx *gorm.DB, holiday entity.Holiday) (bool, error)
+		Update(ctx context.Context, tx *gorm.DB, holiday entity.Holiday) (entity.Holiday, error)
+		Delete(ctx context.Context, tx *gorm.DB, holiday entity.Holiday) error
+	}
+
+	holidayRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewHoliday(db *gorm.DB) HolidayRepository {
+	return &holidayRepository{
+		db: db,
+	}
+}
+
+func (r *holidayRepository) Create(ctx context.Context, tx *gorm.DB, holiday entity.Holiday) (entity.Holiday, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Create(&holiday).Error; err != nil {
+		return entity.Holiday{}, err
+	}
+
+	return holiday, nil
+}
+
+func (r *holidayRepository) GetByID(ctx context.Context, tx *gorm.DB, holidayId string, preloads ...string) (entity.Holiday, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var holiday entity.Holiday
+	if err := tx.WithContext(ctx).Where("id = ?", holidayId).First(&holiday).Error; err != nil {
+		return entity.Holiday{}, err
+	}
+
+	return holiday, nil
+}
+
+// GetAllByPackageID lists the holidays of the package by date, a year of 0
+// lists all of them
+func (r *holidayRepository) GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, year int) ([]entity.Holiday, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	tx = tx.WithContext(ctx).Where("package_id = ?", packageId)
+	if year > 0 {
+		tx = tx.Where("EXTRACT(YEAR FROM date) = ?", year)
+	}
+
+	var holidays []entity.Holiday
+	if err := tx.Order("date asc").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+
+	return holidays, nil
+}
+
+// GetAllFrom lists the holidays of the package on or after the day
+func (r *holidayRepository) GetAllFrom(ctx context.Context, tx *gorm.DB, packageId string, from time.Time) ([]entity.Holiday, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	var holidays []entity.Holiday
+	if err := tx.WithContext(ctx).
+		Where("package_id = ? AND date >= ?", packageId, from.Format(time.DateOnly)).
+		Order("date asc").
+		Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+
+	return holidays, nil
+}
+
+// ExistsByDate tells whether the package already has another holiday on the
+// day
+func (r *holidayRepository) ExistsByDate(ctx context.Context, tx *gorm.DB, holiday entity.Holiday) (bool, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).Model(&entity.Holiday{}).
+		Where("package_id = ? AND date = ? AND id <> ?", holiday.PackageID, holiday.Date.Format(time.DateOnly), holiday.ID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *holidayRepository) Update(ctx context.Context, tx *gorm.DB, holiday entity.Holiday) (entity.Holiday, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).
+		Omit("Package").
+		Save(&holiday).Error; err != nil {
+		return entity.Holiday{}, err
+	}
+
+	return holiday, nil
+}
+
+func (r *holidayRepository) Delete(ctx context.Context, tx *gorm.DB, holiday entity.Holiday) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if holiday.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.Holiday{}).
+			Where("id = ?", holiday.ID).
+			Updates(map[string]interface{}{"deleted_by": holiday.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&holiday).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	JobRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, job entity.Job) (entity.Job, error)
+		GetByID(ctx context.Context, tx *gorm.DB, jobId string, preloads ...string) (entity.Job, error)
+		GetActiveByDedupKey(ctx context.Context, tx *gorm.DB, dedupKey string) (entity.Job, error)
+		GetAllPending(ctx context.Context, tx *gorm.DB) ([]entity.Job, error)
+		GetAllExpired(ctx context.Context, tx *gorm.DB, now time.Time) ([]entity.Job, error)
+		Claim(ctx context.Context, tx *gorm.DB, jobId string, startedAt time.Time) (bool, error)
+		UpdateProgress(ctx context.Context, tx *gorm.DB, jobId string, progress int) error
+		ResetRunning(ctx context.Context, tx *gorm.DB) error
+		Update(ctx context.Context, tx *gorm.DB, job entity.Job) (entity.Job, error)
+		Delete(ctx context.Context, tx *gorm.DB, job entity.Job) error
+	}
+
+	jobRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewJob(db *gorm.DB) JobRepository {
+	return &jobRepository{db}
+}
+
+func (r *jobRepository) Create(ctx context.Context, tx *gorm.DB, job entity.Job) (entity.Job, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Create(&job).Error; err != nil {
+		return entity.Job{}, err
+	}
+
+	return job, nil
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, tx *gorm.DB, jobId string, preloads ...string) (entity.Job, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var job entity.Job
+	if err := tx.WithContext(ctx).Where("id = ?", jobId).First(&job).Error; err != nil {
+		return entity.Job{}, err
+	}
+
+	return job, nil
+}
+
+func (r *jobRepository) GetActiveByDedupKey(ctx context.Context, tx *gorm.DB, dedupKey string) (entity.Job, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	var job entity.Job
+	if err := tx.WithContext(ctx).
+		Where("dedup_key = ? AND status IN ?", dedupKey, []entity.JobStatus{entity.JobStatusPending, entity.JobStatusRunning}).
+		First(&job).Error; err != nil {
+		return entity.Job{}, err
+	}
+
+	return job, nil
+}
+
+func (r *jobRepository) GetAllPending(ctx context.Context, tx *gorm.DB) ([]entity.Job, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	var jobs []entity.Job
+	if err := tx.WithContext(ctx).
+		Where("status = ?", entity.JobStatusPending).
+		Order("created_at asc").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (r *jobRepository) GetAllExpired(ctx context.Context, tx *gorm.DB, now time.Time) ([]entity.Job, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	var jobs []entity.Job
+	if err := tx.WithContext(ctx).
+		Where("expires_at IS NOT NULL AND expires_at < ?", now).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Claim moves a pending job to running. It returns false when another worker
+// picked the job up first.
+func (r *jobRepository) Claim(ctx context.Context, tx *gorm.DB, jobId string, startedAt time.Time) (bool, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	res := tx.WithContext(ctx).Model(&entity.Job{}).
+		Where("id = ? AND status = ?", jobId, entity.JobStatusPending).
+		Updates(map[string]any{
+			"status":     entity.JobStatusRunning,
+			"started_at": startedAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *jobRepository) UpdateProgress(ctx context.Context, tx *gorm.DB, jobId string, progress int) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Job{}).
+		Where("id = ?", jobId).
+		Update("progress", progress).Error
+}
+
+// ResetRunning puts jobs interrupted by a restart back in the queue
+func (r *jobRepository) ResetRunning(ctx context.Context, tx *gorm.DB) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Job{}).
+		Where("status = ?", entity.JobStatusRunning).
+		Updates(map[string]any{
+			"status":   entity.JobStatusPending,
+			"progress": 0,
+		}).Error
+}
+
+func (r *jobRepository) Update(ctx context.Context, tx *gorm.DB, job entity.Job) (entity.Job, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Save(&job).Error; err != nil {
+		return entity.Job{}, err
+	}
+
+	return job, nil
+}
+
+func (r *jobRepository) Delete(ctx context.Context, tx *gorm.DB, job entity.Job) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Delete(&job).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"gorm.io/gorm"
+)
+
+type (
+	NotificationRepository interface {
+		CreateBulk(ctx context.Context, tx *gorm.DB, notifications []entity.Notification) error
+		GetAllByUserID(ctx context.Context, tx *gorm.DB, userId string, unreadOnly bool, metaReq meta.Meta, preloads ...string) ([]entity.Notification, meta.Meta, error)
+		GetByID(ctx context.Context, tx *gorm.DB, notificationId string, preloads ...string) (entity.Notification, error)
+		MarkRead(ctx context.Context, tx *gorm.DB, userId string, notificationIds []string) error
+	}
+
+	notificationRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewNotification(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) CreateBulk(ctx context.Context, tx *gorm.DB, notifications []entity.Notification) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Create(&notifications).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) GetAllByUserID(ctx context.Context, tx *gorm.DB, userId string, unreadOnly bool, metaReq meta.Meta, preloads ...string) ([]entity.Notification, meta.Meta, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	tx = tx.WithContext(ctx).Model(&entity.Notification{}).Where("user_id = ?", userId)
+	if unreadOnly {
+		tx = tx.Where("read_at IS NULL")
+	}
+
+	var notifications []entity.Notification
+	if err := WithFilters(tx, &metaReq, AddModels(entity.Notification{})).Find(&notifications).Error; err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	return notifications, metaReq, nil
+}
+
+func (r *notificationRepository) GetByID(ctx context.Context, tx *gorm.DB, notificationId string, preloads ...string) (entity.Notification, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var notification entity.Notification
+	if err := tx.WithContext(ctx).Where("id = ?", notificationId).First(&notification).Error; err != nil {
+		return entity.Notification{}, err
+	}
+
+	return notification, nil
+}
+
+// MarkRead marks the unread notifications of the user as read, all of them
+// when no id is given
+func (r *notificationRepository) MarkRead(ctx context.Context, tx *gorm.DB, userId string, notificationIds []string) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	tx = tx.WithContext(ctx).Model(&entity.Notification{}).Where("user_id = ? AND read_at IS NULL", userId)
+	if len(notificationIds) > 0 {
+		tx = tx.Where("id IN ?", notificationIds)
+	}
+
+	if err := tx.Update("read_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -33,7 +33,7 @@ func NewPackage(db *gorm.DB) PackageRepository {
 
 func (r *packageRepository) GetByID(ctx context.Context, tx *gorm.DB, pkgID string, preloads ...string) (entity.Package, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -50,7 +50,7 @@ func (r *packageRepository) GetByID(ctx context.Context, tx *gorm.DB, pkgID stri
 
 func (r *packageRepository) GetByName(ctx context.Context, tx *gorm.DB, pkgName string, preloads ...string) (entity.Package, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -67,7 +67,7 @@ func (r *packageRepository) GetByName(ctx context.Context, tx *gorm.DB, pkgName
 
 func (r *packageRepository) Create(ctx context.Context, tx *gorm.DB, pkg entity.Package, preloads ...string) (entity.Package, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -83,7 +83,7 @@ func (r *packageRepository) Create(ctx context.Context, tx *gorm.DB, pkg entity.
 
 func (r *packageRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.Package, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -103,7 +103,7 @@ func (r *packageRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq met
 
 func (r *packageRepository) GetAllNoPag(ctx context.Context, tx *gorm.DB, preloads ...string) ([]entity.Package, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -122,7 +122,7 @@ func (r *packageRepository) GetAllNoPag(ctx context.Context, tx *gorm.DB, preloa
 
 func (r *packageRepository) Update(ctx context.Context, tx *gorm.DB, pkg entity.Package, preloads ...string) (entity.Package, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -138,7 +138,7 @@ func (r *packageRepository) Update(ctx context.Context, tx *gorm.DB, pkg entity.
 
 func (r *packageRepository) Delete(ctx context.Context, tx *gorm.DB, pkg entity.Package, preloads ...string) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	QueueRepository interface {
+		GetAssignedDocuments(ctx context.Context, tx *gorm.DB, userIds []string) ([]dto.QueueDocumentRow, error)
+		GetAwaitingCloseOut(ctx context.Context, tx *gorm.DB, userIds []string, scope CommentScope) ([]dto.QueueCommentRow, error)
+		GetReplies(ctx context.Context, tx *gorm.DB, userIds []string, since time.Time, scope CommentScope) ([]dto.QueueCommentRow, error)
+	}
+
+	queueRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewQueue(db *gorm.DB) QueueRepository {
+	return &queueRepository{
+		db: db,
+	}
+}
+
+// officialSet keeps the comments of the consolidated CRS, the only ones that
+// can be closed out
+var officialSet = CommentScope{
+	Stages:     []entity.CommentStage{entity.CommentStageOfficial},
+	PublicOnly: true,
+}
+
+// GetAssignedDocuments lists the documents consolidated by the users, with
+// how many comments each of them already wrote on it and how many are open
+func (r *queueRepository) GetAssignedDocuments(ctx context.Context, tx *gorm.DB, userIds []string) ([]dto.QueueDocumentRow, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if len(userIds) == 0 {
+		return []dto.QueueDocumentRow{}, nil
+	}
+
+	query := `
+	SELECT
+		dgc.user_id AS owner_id,
+		dld.id AS discipline_list_document_id,
+		dld.discipline_group_id,
+		dg.review_focus,
+		dld.package_id,
+		p.name AS package_name,
+		d.id AS document_id,
+		d.company_document_number,
+		d.document_title,
+		d.due_date,
+		dldc.created_at AS assigned_at,
+		COUNT(c.id) AS my_comment,
+		COUNT(c.id) FILTER (WHERE c.comment_reply_id IS NULL AND c.status IS NULL) AS open_comment
+	FROM discipline_group_consolidators dgc
+	JOIN discipline_list_document_consolidators dldc ON dldc.discipline_group_consolidator_id = dgc.id
+		AND dldc.deleted_at IS NULL
+	JOIN discipline_list_documents dld ON dld.id = dldc.discipline_list_document_id
+		AND dld.deleted_at IS NULL
+	JOIN discipline_groups dg ON dg.id = dld.discipline_group_id
+		AND dg.deleted_at IS NULL
+	JOIN documents d ON d.id = dld.document_id
+		AND d.deleted_at IS NULL
+	JOIN packages p ON p.id = dld.package_id
+	LEFT JOIN comments c ON c.discipline_list_document_id = dld.id
+		AND c.deleted_at IS NULL
+		AND (c.user_id = dgc.user_id OR c.on_behalf_of_id = dgc.user_id)
+	WHERE dgc.deleted_at IS NULL
+		AND dgc.user_id IN ?
+	GROUP BY dgc.user_id, dld.id, dg.review_focus, p.name, d.id, dldc.created_at;
+	`
+
+	var rows []dto.QueueDocumentRow
+	if err := tx.WithContext(ctx).Raw(query, userIds).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetAwaitingCloseOut lists the open comments of the official set of the
+// users that somebody else already answered, the latest answer within scope
+// is returned with each of them
+func (r *queueRepository) GetAwaitingCloseOut(ctx context.Context, tx *gorm.DB, userIds []string, scope CommentScope) ([]dto.QueueCommentRow, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if len(userIds) == 0 {
+		return []dto.QueueCommentRow{}, nil
+	}
+
+	query := fmt.Sprintf(`
+	SELECT DISTINCT ON (c.id)
+		COALESCE(c.on_behalf_of_id, c.user_id) AS owner_id,
+		c.id AS comment_id,
+		c.comment,
+		r.id AS reply_id,
+		r.comment AS reply,
+		u.name AS reply_by,
+		r.created_at AS at,
+		dld.id AS discipline_list_document_id,
+		dld.discipline_group_id,
+		dg.review_focus,
+		dld.package_id,
+		p.name AS package_name,
+		d.id AS document_id,
+		d.company_document_number,
+		d.document_title,
+		d.due_date
+	FROM comments c
+	JOIN comments r ON r.comment_reply_id = c.id
+		AND %s
+		AND r.user_id <> c.user_id
+		AND r.user_id NOT IN ?
+	JOIN users u ON u.id = r.user_id
+	JOIN discipline_list_documents dld ON dld.id = c.discipline_list_document_id
+		AND dld.deleted_at IS NULL
+	JOIN discipline_groups dg ON dg.id = dld.discipline_group_id
+		AND dg.deleted_at IS NULL
+	JOIN documents d ON d.id = dld.document_id
+		AND d.deleted_at IS NULL
+	JOIN packages p ON p.id = dld.package_id
+	WHERE %s
+		AND c.comment_reply_id IS NULL
+		AND c.status IS NULL
+		AND COALESCE(c.on_behalf_of_id, c.user_id) IN ?
+	ORDER BY c.id, r.created_at DESC;
+	`, scope.replyCondition("r"), officialSet.condition("c"))
+
+	var rows []dto.QueueCommentRow
+	if err := tx.WithContext(ctx).Raw(query, userIds, userIds).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetReplies lists what others replied within scope on the comments of the
+// users since the given time
+func (r *queueRepository) GetReplies(ctx context.Context, tx *gorm.DB, userIds []string, since time.Time, scope CommentScope) ([]dto.QueueCommentRow, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if len(userIds) == 0 {
+		return []dto.QueueCommentRow{}, nil
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		COALESCE(c.on_behalf_of_id, c.user_id) AS owner_id,
+		c.id AS comment_id,
+		c.comment,
+		r.id AS reply_id,
+		r.comment AS reply,
+		u.name AS reply_by,
+		r.created_at AS at,
+		dld.id AS discipline_list_document_id,
+		dld.discipline_group_id,
+		dg.review_focus,
+		dld.package_id,
+		p.name AS package_name,
+		d.id AS document_id,
+		d.company_document_number,
+		d.document_title,
+		d.due_date
+	FROM comments r
+	JOIN comments c ON c.id = r.comment_reply_id
+		AND %s
+	JOIN users u ON u.id = r.user_id
+	JOIN discipline_list_documents dld ON dld.id = c.discipline_list_document_id
+		AND dld.deleted_at IS NULL
+	JOIN discipline_groups dg ON dg.id = dld.discipline_group_id
+		AND dg.deleted_at IS NULL
+	JOIN documents d ON d.id = dld.document_id
+		AND d.deleted_at IS NULL
+	JOIN packages p ON p.id = dld.package_id
+	WHERE %s
+		AND r.created_at >= ?
+		AND r.user_id NOT IN ?
+		AND COALESCE(c.on_behalf_of_id, c.user_id) IN ?;
+	`, scope.condition("c"), scope.replyCondition("r"))
+
+	var rows []dto.QueueCommentRow
+	if err := tx.WithContext(ctx).Raw(query, since, userIds, userIds).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	ReportTemplateRepository interface {
+		GetByPackageID(ctx context.Context, tx *gorm.DB, packageId string, preloads ...string) (entity.ReportTemplate, error)
+		Create(ctx context.Context, tx *gorm.DB, reportTemplate entity.ReportTemplate) (entity.ReportTemplate, error)
+		Update(ctx context.Context, tx *gorm.DB, reportTemplate entity.ReportTemplate) (entity.ReportTemplate, error)
+		Delete(ctx context.Context, tx *gorm.DB, reportTemplate entity.ReportTemplate) error
+	}
+
+	reportTemplateRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewReportTemplate(db *gorm.DB) ReportTemplateRepository {
+	return &reportTemplateRepository{db}
+}
+
+func (r *reportTemplateRepository) GetByPackageID(ctx context.Context, tx *gorm.DB, packageId string, preloads ...string) (entity.ReportTemplate, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var reportTemplate entity.ReportTemplate
+	if err := tx.WithContext(ctx).Where("package_id = ?", packageId).First(&reportTemplate).Error; err != nil {
+		return entity.ReportTemplate{}, err
+	}
+
+	return reportTemplate, nil
+}
+
+func (r *reportTemplateRepository) Create(ctx context.Context, tx *gorm.DB, reportTemplate entity.ReportTemplate) (entity.ReportTemplate, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Create(&reportTemplate).Error; err != nil {
+		return entity.ReportTemplate{}, err
+	}
+
+	return reportTemplate, nil
+}
+
+func (r *reportTemplateRepository) Update(ctx context.Context, tx *gorm.DB, reportTemplate entity.ReportTemplate) (entity.ReportTemplate, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Save(&reportTemplate).Error; err != nil {
+		return entity.ReportTemplate{}, err
+	}
+
+	return reportTemplate, nil
+}
+
+func (r *reportTemplateRepository) Delete(ctx context.Context, tx *gorm.DB, reportTemplate entity.ReportTemplate) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if reportTemplate.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.ReportTemplate{}).
+			Where("id = ?", reportTemplate.ID).
+			Updates(map[string]interface{}{"deleted_by": reportTemplate.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&reportTemplate).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	ReviewOutcomeRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, reviewOutcome entity.ReviewOutcome) (entity.ReviewOutcome, error)
+		GetByRevision(ctx context.Context, tx *gorm.DB, documentId, revision string, preloads ...string) (entity.ReviewOutcome, error)
+		GetAllByDocumentID(ctx context.Context, tx *gorm.DB, documentId string, preloads ...string) ([]entity.ReviewOutcome, error)
+		Update(ctx context.Context, tx *gorm.DB, reviewOutcome entity.ReviewOutcome) (entity.ReviewOutcome, error)
+	}
+
+	reviewOutcomeRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewReviewOutcome(db *gorm.DB) ReviewOutcomeRepository {
+	return &reviewOutcomeRepository{
+		db: db,
+	}
+}
+
+func (r *reviewOutcomeRepository) Create(ctx context.Context, tx *gorm.DB, reviewOutcome entity.ReviewOutcome) (entity.ReviewOutcome, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Create(&reviewOutcome).Error; err != nil {
+		return entity.ReviewOutcome{}, err
+	}
+
+	return reviewOutcome, nil
+}
+
+func (r *reviewOutcomeRepository) GetByRevision(ctx context.Context, tx *gorm.DB, documentId, revision string, preloads ...string) (entity.ReviewOutcome, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var reviewOutcome entity.ReviewOutcome
+	if err := tx.WithContext(ctx).Where("document_id = ? AND revision = ?", documentId, revision).First(&reviewOutcome).Error; err != nil {
+		return entity.ReviewOutcome{}, err
+	}
+
+	return reviewOutcome, nil
+}
+
+// GetAllByDocumentID lists the outcomes of every revision, latest first
+func (r *reviewOutcomeRepository) GetAllByDocumentID(ctx context.Context, tx *gorm.DB, documentId string, preloads ...string) ([]entity.ReviewOutcome, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var reviewOutcomes []entity.ReviewOutcome
+	if err := tx.WithContext(ctx).Where("document_id = ?", documentId).Order("set_at DESC").Find(&reviewOutcomes).Error; err != nil {
+		return nil, err
+	}
+
+	return reviewOutcomes, nil
+}
+
+func (r *reviewOutcomeRepository) Update(ctx context.Context, tx *gorm.DB, reviewOutcome entity.ReviewOutcome) (entity.ReviewOutcome, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).
+		Omit("Document", "SetBy").
+		Save(&reviewOutcome).Error; err != nil {
+		return entity.ReviewOutcome{}, err
+	}
+
+	return reviewOutcome, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	ReviewWindowRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, reviewWindow entity.ReviewWindow) (entity.ReviewWindow, error)
+		GetByID(ctx context.Context, tx *gorm.DB, reviewWindowId string, preloads ...string) (entity.ReviewWindow, error)
+		GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId, disciplineGroupId string, preloads ...string) ([]entity.ReviewWindow, error)
+		Update(ctx context.Context, tx *gorm.DB, reviewWindow entity.ReviewWindow) (entity.ReviewWindow, error)
+		Delete(ctx context.Context, tx *gorm.DB, reviewWindow entity.ReviewWindow) error
+	}
+
+	reviewWindowRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewReviewWindow(db *gorm.DB) ReviewWindowRepository {
+	return &reviewWindowRepository{
+		db: db,
+	}
+}
+
+func (r *reviewWindowRepository) Create(ctx context.Context, tx *gorm.DB, reviewWindow entity.ReviewWindow) (entity.ReviewWindow, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Omit("Package", "DisciplineGroup").Create(&reviewWindow).Error; err != nil {
+		return entity.ReviewWindow{}, err
+	}
+
+	return reviewWindow, nil
+}
+
+func (r *reviewWindowRepository) GetByID(ctx context.Context, tx *gorm.DB, reviewWindowId string, preloads ...string) (entity.ReviewWindow, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var reviewWindow entity.ReviewWindow
+	if err := tx.WithContext(ctx).Where("id = ?", reviewWindowId).First(&reviewWindow).Error; err != nil {
+		return entity.ReviewWindow{}, err
+	}
+
+	return reviewWindow, nil
+}
+
+// GetAllByPackageID lists the windows of the package by opening time. Given
+// a discipline group only the windows that apply to it are listed, those of
+// the group and those of the whole package.
+func (r *reviewWindowRepository) GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId, disciplineGroupId string, preloads ...string) ([]entity.ReviewWindow, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	tx = tx.WithContext(ctx).Where("package_id = ?", packageId)
+	if disciplineGroupId != "" {
+		tx = tx.Where("discipline_group_id IS NULL OR discipline_group_id = ?", disciplineGroupId)
+	}
+
+	var reviewWindows []entity.ReviewWindow
+	if err := tx.Order("opens_at asc").Find(&reviewWindows).Error; err != nil {
+		return nil, err
+	}
+
+	return reviewWindows, nil
+}
+
+func (r *reviewWindowRepository) Update(ctx context.Context, tx *gorm.DB, reviewWindow entity.ReviewWindow) (entity.ReviewWindow, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).
+		Omit("Package", "DisciplineGroup").
+		Save(&reviewWindow).Error; err != nil {
+		return entity.ReviewWindow{}, err
+	}
+
+	return reviewWindow, nil
+}
+
+func (r *reviewWindowRepository) Delete(ctx context.Context, tx *gorm.DB, reviewWindow entity.ReviewWindow) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if reviewWindow.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.ReviewWindow{}).
+			Where("id = ?", reviewWindow.ID).
+			Updates(map[string]interface{}{"deleted_by": reviewWindow.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&reviewWindow).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
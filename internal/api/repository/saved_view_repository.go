@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	SavedViewRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, savedView entity.SavedView, preloads ...string) (entity.SavedView, error)
+		GetByID(ctx context.Context, tx *gorm.DB, savedViewId string, preloads ...string) (entity.SavedView, error)
+		GetAllVisible(ctx context.Context, tx *gorm.DB, user entity.User, resource string, preloads ...string) ([]entity.SavedView, error)
+		GetDefault(ctx context.Context, tx *gorm.DB, packageId string, resource entity.SavedViewResource) (entity.SavedView, error)
+		ClearDefault(ctx context.Context, tx *gorm.DB, packageId string, resource entity.SavedViewResource, excludeId string) error
+		Validate(savedView entity.SavedView) error
+		Update(ctx context.Context, tx *gorm.DB, savedView entity.SavedView) (entity.SavedView, error)
+		Delete(ctx context.Context, tx *gorm.DB, savedView entity.SavedView) error
+	}
+
+	savedViewRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewSavedView(db *gorm.DB) SavedViewRepository {
+	return &savedViewRepository{
+		db: db,
+	}
+}
+
+func (r *savedViewRepository) Create(ctx context.Context, tx *gorm.DB, savedView entity.SavedView, preloads ...string) (entity.SavedView, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	if err := tx.WithContext(ctx).Create(&savedView).Error; err != nil {
+		return entity.SavedView{}, err
+	}
+
+	return savedView, nil
+}
+
+func (r *savedViewRepository) GetByID(ctx context.Context, tx *gorm.DB, savedViewId string, preloads ...string) (entity.SavedView, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var savedView entity.SavedView
+	if err := tx.WithContext(ctx).Where("id = ?", savedViewId).First(&savedView).Error; err != nil {
+		return entity.SavedView{}, err
+	}
+
+	return savedView, nil
+}
+
+// GetAllVisible returns the user's own views and the ones shared with or
+// published for their package, package defaults first. A super admin sees
+// every view.
+func (r *savedViewRepository) GetAllVisible(ctx context.Context, tx *gorm.DB, user entity.User, resource string, preloads ...string) ([]entity.SavedView, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	tx = tx.WithContext(ctx).Model(&entity.SavedView{})
+	if user.PackageID != nil {
+		tx = tx.Where("user_id = ? OR ((is_shared OR is_default) AND package_id = ?)", user.ID, user.PackageID)
+	}
+
+	if resource != "" {
+		tx = tx.Where("resource = ?", resource)
+	}
+
+	var savedViews []entity.SavedView
+	if err := tx.Order("is_default desc, name asc").Find(&savedViews).Error; err != nil {
+		return nil, err
+	}
+
+	return savedViews, nil
+}
+
+func (r *savedViewRepository) GetDefault(ctx context.Context, tx *gorm.DB, packageId string, resource entity.SavedViewResource) (entity.SavedView, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	var savedView entity.SavedView
+	if err := tx.WithContext(ctx).
+		Where("package_id = ? AND resource = ? AND is_default", packageId, resource).
+		First(&savedView).Error; err != nil {
+		return entity.SavedView{}, err
+	}
+
+	return savedView, nil
+}
+
+// ClearDefault unpublishes the package default of the resource, a package
+// has at most one per resource
+func (r *savedViewRepository) ClearDefault(ctx context.Context, tx *gorm.DB, packageId string, resource entity.SavedViewResource, excludeId string) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	tx = tx.WithContext(ctx).Model(&entity.SavedView{}).
+		Where("package_id = ? AND resource = ? AND is_default", packageId, resource)
+	if excludeId != "" {
+		tx = tx.Where("id <> ?", excludeId)
+	}
+
+	return tx.Update("is_default", false).Error
+}
+
+// Validate checks the view params against the fields the listing of its
+// resource filters and sorts on
+func (r *savedViewRepository) Validate(savedView entity.SavedView) error {
+	var opts []Option
+	switch savedView.Resource {
+	case entity.SavedViewDocument:
+		opts = documentFilterOptions(CommentScope{})
+	case entity.SavedViewComment:
+		opts = []Option{AddModels(entity.Comment{})}
+	case entity.SavedViewDisciplineListDocument:
+		opts = disciplineListDocumentFilterOptions()
+	default:
+		return ErrInvalidTypeModel
+	}
+
+	return ValidateMeta(r.db, meta.Meta{
+		Take:     savedView.Take,
+		Sort:     savedView.Sort,
+		SortBy:   savedView.SortBy,
+		Filter:   savedView.Filter,
+		FilterBy: savedView.FilterBy,
+		Query:    savedView.Query,
+	}, opts...)
+}
+
+func (r *savedViewRepository) Update(ctx context.Context, tx *gorm.DB, savedView entity.SavedView) (entity.SavedView, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Omit("User", "Package").Save(&savedView).Error; err != nil {
+		return entity.SavedView{}, err
+	}
+
+	return savedView, nil
+}
+
+func (r *savedViewRepository) Delete(ctx context.Context, tx *gorm.DB, savedView entity.SavedView) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if savedView.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.SavedView{}).
+			Where("id = ?", savedView.ID).
+			Updates(map[string]interface{}{"deleted_by": savedView.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&savedView).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
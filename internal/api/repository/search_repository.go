@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"gorm.io/gorm"
+)
+
+type (
+	SearchRepository interface {
+		Search(ctx context.Context, tx *gorm.DB, params dto.SearchParams) ([]dto.SearchResult, int64, error)
+	}
+
+	searchRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewSearch(db *gorm.DB) SearchRepository {
+	return &searchRepository{
+		db: db,
+	}
+}
+
+const searchHeadline = `'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2'`
+
+// searchQuery matches documents and comments against the search_vector
+// columns created by the migration. Document numbers are also matched as a
+// plain fragment since they rarely split into useful words.
+const searchQuery = `
+	SELECT
+		'DOCUMENT' AS type,
+		d.id::text AS id,
+		ts_rank(d.search_vector, q) + CASE WHEN d.company_document_number ILIKE @pattern OR d.contractor_document_number ILIKE @pattern THEN 1 ELSE 0 END AS rank,
+		ts_headline('simple', concat_ws(' - ', d.company_document_number, d.contractor_document_number, d.document_title), q, ` + searchHeadline + `) AS snippet,
+		d.package_id::text AS package_id,
+		p.name AS package_name,
+		d.id::text AS document_id,
+		d.company_document_number,
+		d.document_title,
+		NULL::text AS discipline_group_id,
+		NULL::text AS discipline_list_document_id,
+		NULL::text AS comment_reply_id,
+		d.created_at
+	FROM documents d
+	JOIN packages p ON p.id = d.package_id
+	CROSS JOIN to_tsquery('simple', @query) q
+	WHERE @documents
+		AND d.deleted_at IS NULL
+		AND (@package_id = '' OR d.package_id::text = @package_id)
+		AND (d.search_vector @@ q OR d.company_document_number ILIKE @pattern OR d.contractor_document_number ILIKE @pattern)
+
+	UNION ALL
+
+	SELECT
+		'COMMENT' AS type,
+		c.id::text AS id,
+		ts_rank(c.search_vector, q) AS rank,
+		ts_headline('simple', concat_ws(' - ', c.section, c.comment, c.baseline), q, ` + searchHeadline + `) AS snippet,
+		dld.package_id::text AS package_id,
+		p.name AS package_name,
+		d.id::text AS document_id,
+		d.company_document_number,
+		d.document_title,
+		dld.discipline_group_id::text AS discipline_group_id,
+		dld.id::text AS discipline_list_document_id,
+		c.comment_reply_id::text AS comment_reply_id,
+		c.created_at
+	FROM comments c
+	JOIN comments t ON t.id = COALESCE(c.comment_reply_id, c.id)
+	JOIN discipline_list_documents dld ON dld.id = c.discipline_list_document_id
+		AND dld.deleted_at IS NULL
+	JOIN documents d ON d.id = dld.document_id
+		AND d.deleted_at IS NULL
+	JOIN packages p ON p.id = dld.package_id
+	CROSS JOIN to_tsquery('simple', @query) q
+	WHERE @comments
+		AND c.deleted_at IS NULL
+		AND (@package_id = '' OR dld.package_id::text = @package_id)
+		AND (NOT @public_only OR (t.stage = 'OFFICIAL' AND t.merged_into_id IS NULL AND t.visibility = 'PUBLIC' AND c.visibility = 'PUBLIC'))
+		AND c.search_vector @@ q
+`
+
+// Search returns one page of results ordered by rank, with the total count
+func (r *searchRepository) Search(ctx context.Context, tx *gorm.DB, params dto.SearchParams) ([]dto.SearchResult, int64, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	args := map[string]any{
+		"query":       params.Query,
+		"pattern":     params.Pattern,
+		"package_id":  params.PackageID,
+		"documents":   params.Documents,
+		"comments":    params.Comments,
+		"public_only": params.PublicOnly,
+		"limit":       params.Limit,
+		"offset":      params.Offset,
+	}
+
+	var total int64
+	if err := tx.WithContext(ctx).Raw(`SELECT COUNT(*) FROM (`+searchQuery+`) s`, args).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []dto.SearchResult
+	if err := tx.WithContext(ctx).Raw(`SELECT * FROM (`+searchQuery+`) s
+	ORDER BY rank DESC, created_at DESC
+	LIMIT @limit OFFSET @offset`, args).Scan(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
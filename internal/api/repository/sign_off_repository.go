@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	SignOffRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, signOff entity.SignOff) (entity.SignOff, error)
+		GetByID(ctx context.Context, tx *gorm.DB, signOffId string, preloads ...string) (entity.SignOff, error)
+		GetByFileHash(ctx context.Context, tx *gorm.DB, fileHash string, preloads ...string) (entity.SignOff, error)
+		GetByContentHash(ctx context.Context, tx *gorm.DB, contentHash string, preloads ...string) (entity.SignOff, error)
+		Update(ctx context.Context, tx *gorm.DB, signOff entity.SignOff) (entity.SignOff, error)
+		UpdateStep(ctx context.Context, tx *gorm.DB, step entity.SignOffStep) (entity.SignOffStep, error)
+	}
+
+	signOffRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewSignOff(db *gorm.DB) SignOffRepository {
+	return &signOffRepository{db}
+}
+
+func (r *signOffRepository) Create(ctx context.Context, tx *gorm.DB, signOff entity.SignOff) (entity.SignOff, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Create(&signOff).Error; err != nil {
+		return entity.SignOff{}, err
+	}
+
+	return signOff, nil
+}
+
+func (r *signOffRepository) GetByID(ctx context.Context, tx *gorm.DB, signOffId string, preloads ...string) (entity.SignOff, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var signOff entity.SignOff
+	if err := tx.WithContext(ctx).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("sequence asc") }).
+		Where("id = ?", signOffId).
+		First(&signOff).Error; err != nil {
+		return entity.SignOff{}, err
+	}
+
+	return signOff, nil
+}
+
+func (r *signOffRepository) GetByFileHash(ctx context.Context, tx *gorm.DB, fileHash string, preloads ...string) (entity.SignOff, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var signOff entity.SignOff
+	if err := tx.WithContext(ctx).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("sequence asc") }).
+		Where("file_hash = ?", fileHash).
+		First(&signOff).Error; err != nil {
+		return entity.SignOff{}, err
+	}
+
+	return signOff, nil
+}
+
+func (r *signOffRepository) GetByContentHash(ctx context.Context, tx *gorm.DB, contentHash string, preloads ...string) (entity.SignOff, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var signOff entity.SignOff
+	if err := tx.WithContext(ctx).
+		Where("content_hash = ? AND status = ?", contentHash, entity.SignOffStatusCompleted).
+		Order("completed_at desc").
+		First(&signOff).Error; err != nil {
+		return entity.SignOff{}, err
+	}
+
+	return signOff, nil
+}
+
+func (r *signOffRepository) Update(ctx context.Context, tx *gorm.DB, signOff entity.SignOff) (entity.SignOff, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Omit("Steps").Save(&signOff).Error; err != nil {
+		return entity.SignOff{}, err
+	}
+
+	return signOff, nil
+}
+
+func (r *signOffRepository) UpdateStep(ctx context.Context, tx *gorm.DB, step entity.SignOffStep) (entity.SignOffStep, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Omit("User").Save(&step).Error; err != nil {
+		return entity.SignOffStep{}, err
+	}
+
+	return step, nil
+}
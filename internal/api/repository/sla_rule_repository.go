@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	SLARuleRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, slaRule entity.SLARule) (entity.SLARule, error)
+		GetByID(ctx context.Context, tx *gorm.DB, slaRuleId string, preloads ...string) (entity.SLARule, error)
+		GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, preloads ...string) ([]entity.SLARule, error)
+		ExistsByPurpose(ctx context.Context, tx *gorm.DB, slaRule entity.SLARule) (bool, error)
+		Update(ctx context.Context, tx *gorm.DB, slaRule entity.SLARule) (entity.SLARule, error)
+		Delete(ctx context.Context, tx *gorm.DB, slaRule entity.SLARule) error
+	}
+
+	slaRuleRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewSLARule(db *gorm.DB) SLARuleRepository {
+	return &slaRuleRepository{
+		db: db,
+	}
+}
+
+func (r *slaRuleRepository) Create(ctx context.Context, tx *gorm.DB, slaRule entity.SLARule) (entity.SLARule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Create(&slaRule).Error; err != nil {
+		return entity.SLARule{}, err
+	}
+
+	return slaRule, nil
+}
+
+func (r *slaRuleRepository) GetByID(ctx context.Context, tx *gorm.DB, slaRuleId string, preloads ...string) (entity.SLARule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var slaRule entity.SLARule
+	if err := tx.WithContext(ctx).Where("id = ?", slaRuleId).First(&slaRule).Error; err != nil {
+		return entity.SLARule{}, err
+	}
+
+	return slaRule, nil
+}
+
+// GetAllByPackageID lists the rules of the package, rules without purpose
+// come last
+func (r *slaRuleRepository) GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, preloads ...string) ([]entity.SLARule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var slaRules []entity.SLARule
+	if err := tx.WithContext(ctx).
+		Where("package_id = ?", packageId).
+		Order("purpose asc nulls last, resubmission asc").
+		Find(&slaRules).Error; err != nil {
+		return nil, err
+	}
+
+	return slaRules, nil
+}
+
+// ExistsByPurpose tells whether another rule of the package already covers
+// the purpose and the kind of submission
+func (r *slaRuleRepository) ExistsByPurpose(ctx context.Context, tx *gorm.DB, slaRule entity.SLARule) (bool, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	tx = tx.WithContext(ctx).Model(&entity.SLARule{}).
+		Where("package_id = ? AND resubmission = ? AND id <> ?", slaRule.PackageID, slaRule.Resubmission, slaRule.ID)
+	if slaRule.Purpose == nil {
+		tx = tx.Where("purpose IS NULL")
+	} else {
+		tx = tx.Where("purpose = ?", *slaRule.Purpose)
+	}
+
+	var count int64
+	if err := tx.Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *slaRuleRepository) Update(ctx context.Context, tx *gorm.DB, slaRule entity.SLARule) (entity.SLARule, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).
+		Omit("Package").
+		Save(&slaRule).Error; err != nil {
+		return entity.SLARule{}, err
+	}
+
+	return slaRule, nil
+}
+
+func (r *slaRuleRepository) Delete(ctx context.Context, tx *gorm.DB, slaRule entity.SLARule) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if slaRule.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.SLARule{}).
+			Where("id = ?", slaRule.ID).
+			Updates(map[string]interface{}{"deleted_by": slaRule.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Delete(&slaRule).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,18 +2,26 @@ package repository
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
 	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
 	"gorm.io/gorm"
 )
 
 type (
 	StatisticRepository interface {
-		GetAOCAndCommentChart(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticAOCAndCommentChart, error)
-		GetCommentCard(ctx context.Context, tx *gorm.DB, packageId string) (dto.StatisticAOCAndCommentCard, error)
-		GetCommentUserChart(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticCommentUsersChart, error)
-		GetCommentUserData(ctx context.Context, tx *gorm.DB, packageId string, metaReq meta.Meta) ([]dto.StatisticCommentUsersData, meta.Meta, error)
+		GetAOCAndCommentChart(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, filter dto.StatisticSeriesFilter) ([]dto.StatisticAOCAndCommentChart, error)
+		GetCommentChart(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, filter dto.StatisticCommentChartFilter) ([]dto.StatisticCommentChart, error)
+		GetCommentCard(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope) (dto.StatisticAOCAndCommentCard, error)
+		GetUserCard(ctx context.Context, tx *gorm.DB, packageId, userId string) (dto.StatisticUserCard, error)
+		GetCommentUserChart(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope) ([]dto.StatisticCommentUsersChart, error)
+		GetCommentUserData(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, metaReq meta.Meta) ([]dto.StatisticCommentUsersData, meta.Meta, error)
+		GetCommentClassChart(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, kind entity.CommentClassKind) ([]dto.StatisticCommentClassChart, error)
+		GetReviewOutcomeChart(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticReviewOutcomeChart, error)
+		GetSLADisciplineGroupResponses(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticSLAResponse, error)
+		GetSLAReviewerResponses(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticSLAResponse, error)
 	}
 
 	statisticRepository struct {
@@ -27,21 +35,27 @@ func NewStatistic(db *gorm.DB) StatisticRepository {
 	}
 }
 
-func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticAOCAndCommentChart, error) {
+// GetAOCAndCommentChart counts what was added to the package per period of
+// the filter, or everything added up to the end of each period for a
+// cumulative series. Without a start the chart starts at the first record.
+func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, filter dto.StatisticSeriesFilter) ([]dto.StatisticAOCAndCommentChart, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
-	query := `
+	query := fmt.Sprintf(`
 	WITH date_series AS (
 		SELECT generate_series(
-			LEAST(
-				(SELECT MIN(created_at)::date FROM discipline_list_documents WHERE package_id = ?),
-				(SELECT MIN(created_at)::date FROM documents WHERE package_id = ?)
-			),
-			NOW()::date,
-			'2 days'
-		) AS start_date
+			DATE_TRUNC(@unit, COALESCE(
+				CAST(@from AS date),
+				LEAST(
+					(SELECT MIN(GREATEST(created_at::date, CAST(@earliest AS date))) FROM discipline_list_documents WHERE package_id = @package_id),
+					(SELECT MIN(GREATEST(created_at::date, CAST(@earliest AS date))) FROM documents WHERE package_id = @package_id)
+				)
+			))::date,
+			COALESCE(CAST(@to AS date), NOW()::date),
+			CAST(@step AS interval)
+		)::date AS start_date
 	),
 	aoc_count AS (
 		SELECT
@@ -49,7 +63,7 @@ func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gor
 			COUNT(*) AS total_discipline_list_document
 		FROM discipline_list_documents
 		WHERE deleted_at IS NULL
-		AND package_id = ?
+		AND package_id = @package_id
 		GROUP BY 1
 	),
 	doc_count AS (
@@ -58,7 +72,7 @@ func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gor
 			COUNT(*) AS total_documents
 		FROM documents
 		WHERE deleted_at IS NULL
-		AND package_id = ?
+		AND package_id = @package_id
 		GROUP BY 1
 	),
 	comment_count AS (
@@ -68,9 +82,9 @@ func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gor
 			COUNT(*) FILTER (WHERE c.status = 'REJECT') AS total_comment_rejected
 		FROM comments c
 		JOIN discipline_list_documents a ON a.id = c.discipline_list_document_id
-		WHERE c.deleted_at IS NULL
+		WHERE %s
 		AND a.deleted_at IS NULL
-		AND a.package_id = ?
+		AND a.package_id = @package_id
 		GROUP BY 1
 	),
 	aoc_by_interval AS (
@@ -78,8 +92,8 @@ func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gor
 			ds.start_date,
 			COALESCE(SUM(a.total_discipline_list_document), 0) AS total_discipline_list_document
 		FROM date_series ds
-		LEFT JOIN aoc_count a ON a.created_date >= ds.start_date 
-			AND a.created_date < ds.start_date + INTERVAL '2 days'
+		LEFT JOIN aoc_count a ON (@cumulative OR a.created_date >= ds.start_date)
+			AND a.created_date < ds.start_date + CAST(@step AS interval)
 		GROUP BY ds.start_date
 	),
 	doc_by_interval AS (
@@ -87,8 +101,8 @@ func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gor
 			ds.start_date,
 			COALESCE(SUM(d.total_documents), 0) AS total_documents
 		FROM date_series ds
-		LEFT JOIN doc_count d ON d.created_date >= ds.start_date 
-			AND d.created_date < ds.start_date + INTERVAL '2 days'
+		LEFT JOIN doc_count d ON (@cumulative OR d.created_date >= ds.start_date)
+			AND d.created_date < ds.start_date + CAST(@step AS interval)
 		GROUP BY ds.start_date
 	),
 	comment_by_interval AS (
@@ -97,12 +111,13 @@ func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gor
 			COALESCE(SUM(c.total_comments), 0) AS total_comments,
 			COALESCE(SUM(c.total_comment_rejected), 0) AS total_comment_rejected
 		FROM date_series ds
-		LEFT JOIN comment_count c ON c.created_date >= ds.start_date 
-			AND c.created_date < ds.start_date + INTERVAL '2 days'
+		LEFT JOIN comment_count c ON (@cumulative OR c.created_date >= ds.start_date)
+			AND c.created_date < ds.start_date + CAST(@step AS interval)
 		GROUP BY ds.start_date
 	)
 	SELECT
-		TO_CHAR(ds.start_date, 'DD-Mon') AS name,
+		TO_CHAR(ds.start_date, @label) AS name,
+		TO_CHAR(ds.start_date, 'YYYY-MM-DD') AS date,
 		a.total_discipline_list_document,
 		d.total_documents,
 		c.total_comments,
@@ -112,10 +127,10 @@ func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gor
 	LEFT JOIN doc_by_interval d USING (start_date)
 	LEFT JOIN comment_by_interval c USING (start_date)
 	ORDER BY ds.start_date;
-	`
+	`, scope.condition("c"))
 
 	var stats []dto.StatisticAOCAndCommentChart
-	err := tx.Raw(query, packageId, packageId, packageId, packageId, packageId).Scan(&stats).Error
+	err := tx.Raw(query, seriesParams(packageId, filter)).Scan(&stats).Error
 
 	if err != nil {
 		return nil, err
@@ -124,32 +139,146 @@ func (r *statisticRepository) GetAOCAndCommentChart(ctx context.Context, tx *gor
 	return stats, nil
 }
 
-func (r *statisticRepository) GetCommentCard(ctx context.Context, tx *gorm.DB, packageId string) (dto.StatisticAOCAndCommentCard, error) {
+// commentChartGroups is what the comments of the comment chart are grouped
+// by, see dto.StatisticCommentChartFilter
+var commentChartGroups = map[string]string{
+	"":                 "'All'",
+	"discipline_group": "dg.review_focus",
+	"reviewer":         "u.name",
+	"document_type":    "COALESCE(NULLIF(d.document_type, ''), 'Unspecified')",
+	"category":         "COALESCE(cc.name, 'Unclassified')",
+}
+
+// GetCommentChart is the comment series of the package per group, every
+// group gets a row in every period
+func (r *statisticRepository) GetCommentChart(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, filter dto.StatisticCommentChartFilter) ([]dto.StatisticCommentChart, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	group, ok := commentChartGroups[filter.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown comment chart group %q", filter.GroupBy)
+	}
+
+	query := fmt.Sprintf(`
+	WITH package_comments AS (
+		SELECT
+			DATE_TRUNC('day', c.created_at)::date AS created_date,
+			%s AS group_name,
+			c.status
+		FROM comments c
+		JOIN discipline_list_documents a ON a.id = c.discipline_list_document_id
+		JOIN discipline_groups dg ON dg.id = a.discipline_group_id
+		JOIN documents d ON d.id = a.document_id
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN comment_classes cc ON cc.id = c.category_id
+		WHERE %s
+		AND c.comment_reply_id IS NULL
+		AND a.deleted_at IS NULL
+		AND a.package_id = @package_id
+	),
+	date_series AS (
+		SELECT generate_series(
+			DATE_TRUNC(@unit, COALESCE(
+				CAST(@from AS date),
+				(SELECT MIN(GREATEST(created_date, CAST(@earliest AS date))) FROM package_comments),
+				NOW()::date
+			))::date,
+			COALESCE(CAST(@to AS date), NOW()::date),
+			CAST(@step AS interval)
+		)::date AS start_date
+	),
+	groups AS (
+		SELECT DISTINCT group_name FROM package_comments
+		UNION
+		SELECT 'All' WHERE @group_by = ''
+	)
+	SELECT
+		TO_CHAR(ds.start_date, @label) AS name,
+		TO_CHAR(ds.start_date, 'YYYY-MM-DD') AS date,
+		g.group_name AS "group",
+		COUNT(pc.created_date) AS total_comments,
+		COUNT(pc.created_date) FILTER (WHERE pc.status = 'ACCEPTED' OR pc.status = 'REJECT') AS comment_closed,
+		COUNT(pc.created_date) FILTER (WHERE pc.status = 'REJECT') AS total_comment_rejected
+	FROM date_series ds
+	CROSS JOIN groups g
+	LEFT JOIN package_comments pc ON pc.group_name = g.group_name
+		AND (@cumulative OR pc.created_date >= ds.start_date)
+		AND pc.created_date < ds.start_date + CAST(@step AS interval)
+	GROUP BY ds.start_date, g.group_name
+	ORDER BY ds.start_date, g.group_name;
+	`, group, scope.condition("c"))
+
+	params := seriesParams(packageId, filter.StatisticSeriesFilter)
+	params["group_by"] = filter.GroupBy
+
+	var stats []dto.StatisticCommentChart
+	err := tx.Raw(query, params).Scan(&stats).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// seriesParams are the named params of a time series. Periods start at the
+// beginning of the day, week or month, and without a granularity they are
+// the 2 day periods the charts always had.
+func seriesParams(packageId string, filter dto.StatisticSeriesFilter) map[string]interface{} {
+	params := map[string]interface{}{
+		"package_id": packageId,
+		"from":       filter.From,
+		"to":         filter.To,
+		"earliest":   filter.Earliest,
+		"cumulative": filter.Series == dto.StatisticSeriesCumulative,
+		"unit":       "day",
+		"step":       "2 days",
+		"label":      "DD-Mon",
+	}
+
+	switch filter.Granularity {
+	case dto.StatisticGranularityDay:
+		params["step"] = "1 day"
+	case dto.StatisticGranularityWeek:
+		params["unit"] = "week"
+		params["step"] = "1 week"
+	case dto.StatisticGranularityMonth:
+		params["unit"] = "month"
+		params["step"] = "1 month"
+		params["label"] = "Mon-YY"
+	}
+
+	return params
+}
+
+func (r *statisticRepository) GetCommentCard(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope) (dto.StatisticAOCAndCommentCard, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	var stats dto.StatisticAOCAndCommentCard
-	err := tx.Raw(`
+	err := tx.Raw(fmt.Sprintf(`
 		SELECT
 		(SELECT COUNT(*) FROM discipline_groups a WHERE a.package_id = ? AND deleted_at is null) AS total_discipline_group,
 		(SELECT COUNT(*) FROM documents d WHERE d.package_id = ? AND deleted_at is null) AS total_documents,
 		(SELECT COUNT(*) FROM comments c
 			JOIN discipline_list_documents a ON a.id = c.discipline_list_document_id
-			WHERE a.package_id = ? AND c.comment_reply_id IS NULL AND c.deleted_at is null) AS total_comments,
+			WHERE a.package_id = ? AND c.comment_reply_id IS NULL AND %[1]s) AS total_comments,
 		(SELECT COUNT(*) FROM comments c
 			JOIN discipline_list_documents a ON a.id = c.discipline_list_document_id
-			WHERE a.package_id = ? AND c.status = 'REJECT' AND c.deleted_at is null) AS total_comment_rejected,
+			WHERE a.package_id = ? AND c.status = 'REJECT' AND %[1]s) AS total_comment_rejected,
 		(SELECT COUNT(*) FROM documents d WHERE d.package_id = ? AND d.deleted_at IS NULL
 			AND NOT EXISTS (
 				SELECT 1 FROM discipline_list_documents a 
 				JOIN comments c ON c.discipline_list_document_id = a.id
 				WHERE a.document_id = d.id 
 				AND c.comment_reply_id IS NULL 
-				AND c.deleted_at IS NULL
+				AND %[1]s
 				AND a.deleted_at IS NULL
 			)) AS total_documents_without_comment
-	`, packageId, packageId, packageId, packageId, packageId).Scan(&stats).Error
+	`, scope.condition("c")), packageId, packageId, packageId, packageId, packageId).Scan(&stats).Error
 	if err != nil {
 		return dto.StatisticAOCAndCommentCard{}, err
 	}
@@ -157,12 +286,51 @@ func (r *statisticRepository) GetCommentCard(ctx context.Context, tx *gorm.DB, p
 	return stats, nil
 }
 
-func (r *statisticRepository) GetCommentUserChart(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticCommentUsersChart, error) {
+// GetUserCard counts the comments the user wrote in the package, internal
+// ones included, and the documents they consolidate
+func (r *statisticRepository) GetUserCard(ctx context.Context, tx *gorm.DB, packageId, userId string) (dto.StatisticUserCard, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
-	query := `
+	var stats dto.StatisticUserCard
+	err := tx.Raw(`
+		WITH user_comments AS (
+			SELECT c.status, c.comment_reply_id
+			FROM comments c
+			JOIN discipline_list_documents a ON a.id = c.discipline_list_document_id
+			WHERE a.package_id = ?
+			AND a.deleted_at IS NULL
+			AND c.deleted_at IS NULL
+			AND c.user_id = ?
+		)
+		SELECT
+		(SELECT COUNT(*) FROM user_comments WHERE comment_reply_id IS NULL) AS total_comments,
+		(SELECT COUNT(*) FROM user_comments WHERE comment_reply_id IS NULL AND (status = 'ACCEPTED' OR status = 'REJECT')) AS comment_closed,
+		(SELECT COUNT(*) FROM user_comments WHERE comment_reply_id IS NULL AND status = 'REJECT') AS total_comment_rejected,
+		(SELECT COUNT(*) FROM user_comments WHERE comment_reply_id IS NOT NULL) AS total_replies,
+		(SELECT COUNT(DISTINCT dldc.discipline_list_document_id) FROM discipline_list_document_consolidators dldc
+			JOIN discipline_group_consolidators dgc ON dgc.id = dldc.discipline_group_consolidator_id
+			JOIN discipline_list_documents a ON a.id = dldc.discipline_list_document_id
+			WHERE a.package_id = ?
+			AND dgc.user_id = ?
+			AND a.deleted_at IS NULL
+			AND dldc.deleted_at IS NULL
+			AND dgc.deleted_at IS NULL) AS total_assigned_documents
+	`, packageId, userId, packageId, userId).Scan(&stats).Error
+	if err != nil {
+		return dto.StatisticUserCard{}, err
+	}
+
+	return stats, nil
+}
+
+func (r *statisticRepository) GetCommentUserChart(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope) ([]dto.StatisticCommentUsersChart, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	query := fmt.Sprintf(`
 	SELECT
 		u.id,
 		u.initial AS name,
@@ -170,13 +338,13 @@ func (r *statisticRepository) GetCommentUserChart(ctx context.Context, tx *gorm.
 		COALESCE(COUNT(c.id), 0) AS total_comment
 	FROM users u
 	LEFT JOIN comments c ON c.user_id = u.id
-		AND c.deleted_at IS NULL
 		AND c.comment_reply_id IS NULL
+		AND %s
 	WHERE u.deleted_at IS NULL
 		AND u.package_id = ?
 	GROUP BY u.id, u.initial
 	ORDER BY u.initial;
-	`
+	`, scope.condition("c"))
 
 	var stats []dto.StatisticCommentUsersChart
 	err := tx.Raw(query, packageId).Scan(&stats).Error
@@ -188,12 +356,12 @@ func (r *statisticRepository) GetCommentUserChart(ctx context.Context, tx *gorm.
 	return stats, nil
 }
 
-func (r *statisticRepository) GetCommentUserData(ctx context.Context, tx *gorm.DB, packageId string, metaReq meta.Meta) ([]dto.StatisticCommentUsersData, meta.Meta, error) {
+func (r *statisticRepository) GetCommentUserData(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, metaReq meta.Meta) ([]dto.StatisticCommentUsersData, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
-	cteSubQuery := tx.Raw(`
+	cteSubQuery := tx.Raw(fmt.Sprintf(`
 		SELECT
 			u.id,
 			u.name AS name, 
@@ -202,12 +370,12 @@ func (r *statisticRepository) GetCommentUserData(ctx context.Context, tx *gorm.D
 			COALESCE(COUNT(c.id) FILTER (WHERE c.status = 'ACCEPTED' OR c.status = 'REJECT'), 0) AS comment_closed
 		FROM users u
 		LEFT JOIN comments c ON c.user_id = u.id
-			AND c.deleted_at IS NULL
 			AND c.comment_reply_id IS NULL
+			AND %s
 		WHERE u.deleted_at IS NULL
 			AND u.package_id = ?
 		GROUP BY u.id, u.name, u.initial
-	`, packageId)
+	`, scope.condition("c")), packageId)
 
 	tx = tx.Table("(?) as data", cteSubQuery)
 
@@ -232,3 +400,208 @@ func (r *statisticRepository) GetCommentUserData(ctx context.Context, tx *gorm.D
 
 	return stats, metaReq, nil
 }
+
+// GetCommentClassChart counts the comments per category or severity of the
+// package, in rank order, followed by the comments without one
+func (r *statisticRepository) GetCommentClassChart(ctx context.Context, tx *gorm.DB, packageId string, scope CommentScope, kind entity.CommentClassKind) ([]dto.StatisticCommentClassChart, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	column := "c.category_id"
+	if kind == entity.CommentClassSeverity {
+		column = "c.severity_id"
+	}
+
+	query := fmt.Sprintf(`
+	WITH package_comments AS (
+		SELECT c.id, c.status, %s AS class_id
+		FROM comments c
+		JOIN discipline_list_documents a ON a.id = c.discipline_list_document_id
+		WHERE %s
+		AND c.comment_reply_id IS NULL
+		AND a.deleted_at IS NULL
+		AND a.package_id = ?
+	)
+	SELECT data.id, data.code, data.name, data.comment_closed, data.total_comment
+	FROM (
+		SELECT
+			cc.id,
+			cc.code,
+			cc.name,
+			COALESCE(COUNT(pc.id) FILTER (WHERE pc.status = 'ACCEPTED' OR pc.status = 'REJECT'), 0) AS comment_closed,
+			COALESCE(COUNT(pc.id), 0) AS total_comment,
+			cc.rank AS sort
+		FROM comment_classes cc
+		LEFT JOIN package_comments pc ON pc.class_id = cc.id
+		WHERE cc.deleted_at IS NULL
+		AND cc.package_id = ?
+		AND cc.kind = ?
+		GROUP BY cc.id, cc.code, cc.name, cc.rank
+		UNION ALL
+		SELECT
+			NULL,
+			'',
+			'Unclassified',
+			COUNT(pc.id) FILTER (WHERE pc.status = 'ACCEPTED' OR pc.status = 'REJECT'),
+			COUNT(pc.id),
+			2147483647
+		FROM package_comments pc
+		WHERE pc.class_id IS NULL
+	) data
+	ORDER BY data.sort, data.code;
+	`, column, scope.condition("c"))
+
+	var stats []dto.StatisticCommentClassChart
+	err := tx.Raw(query, packageId, packageId, kind).Scan(&stats).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetReviewOutcomeChart counts the documents by the outcome of their current
+// revision, documents still under review have an empty code
+func (r *statisticRepository) GetReviewOutcomeChart(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticReviewOutcomeChart, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	query := `
+	SELECT
+		COALESCE(ro.code, '') AS code,
+		COUNT(d.id) AS total_documents
+	FROM documents d
+	LEFT JOIN review_outcomes ro ON ro.document_id = d.id
+		AND ro.revision = d.revision
+		AND ro.deleted_at IS NULL
+	WHERE d.deleted_at IS NULL
+		AND d.package_id = ?
+	GROUP BY 1;
+	`
+
+	var stats []dto.StatisticReviewOutcomeChart
+	err := tx.Raw(query, packageId).Scan(&stats).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// slaRounds is the review of the current revision of every document with a
+// due date, per discipline group it is listed in
+const slaRounds = `
+	WITH rounds AS (
+		SELECT
+			dld.id AS discipline_list_document_id,
+			dld.discipline_group_id,
+			d.id AS document_id,
+			d.revision,
+			d.due_date,
+			COALESCE(d.issued_at, d.created_at) AS issued_at
+		FROM discipline_list_documents dld
+		JOIN documents d ON d.id = dld.document_id
+			AND d.deleted_at IS NULL
+		WHERE dld.deleted_at IS NULL
+			AND dld.package_id = ?
+			AND d.due_date IS NOT NULL
+	)`
+
+// GetSLADisciplineGroupResponses lists the review rounds of the discipline
+// groups, a group responds with its first top level comment of the round or
+// with the review outcome of the revision
+func (r *statisticRepository) GetSLADisciplineGroupResponses(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticSLAResponse, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	query := slaRounds + `
+	SELECT
+		dg.id::text AS id,
+		dg.review_focus AS name,
+		r.due_date,
+		LEAST(
+			(SELECT MIN(c.created_at) FROM comments c
+				WHERE c.discipline_list_document_id = r.discipline_list_document_id
+				AND c.comment_reply_id IS NULL
+				AND c.deleted_at IS NULL
+				AND c.created_at >= r.issued_at),
+			(SELECT ro.set_at FROM review_outcomes ro
+				WHERE ro.document_id = r.document_id
+				AND ro.revision = r.revision
+				AND ro.deleted_at IS NULL)
+		) AS responded_at
+	FROM rounds r
+	JOIN discipline_groups dg ON dg.id = r.discipline_group_id
+		AND dg.deleted_at IS NULL;
+	`
+
+	var stats []dto.StatisticSLAResponse
+	err := tx.Raw(query, packageId).Scan(&stats).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetSLAReviewerResponses lists the review rounds of the reviewers, that are
+// the consolidators of the document and whoever commented in the round
+func (r *statisticRepository) GetSLAReviewerResponses(ctx context.Context, tx *gorm.DB, packageId string) ([]dto.StatisticSLAResponse, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	query := slaRounds + `,
+	reviewers AS (
+		SELECT r.discipline_list_document_id, dgc.user_id
+		FROM rounds r
+		JOIN discipline_list_document_consolidators dldc ON dldc.discipline_list_document_id = r.discipline_list_document_id
+			AND dldc.deleted_at IS NULL
+		JOIN discipline_group_consolidators dgc ON dgc.id = dldc.discipline_group_consolidator_id
+			AND dgc.deleted_at IS NULL
+		UNION
+		SELECT r.discipline_list_document_id, c.user_id
+		FROM rounds r
+		JOIN comments c ON c.discipline_list_document_id = r.discipline_list_document_id
+			AND c.comment_reply_id IS NULL
+			AND c.deleted_at IS NULL
+			AND c.created_at >= r.issued_at
+	)
+	SELECT
+		u.id::text AS id,
+		u.name AS name,
+		r.due_date,
+		LEAST(
+			(SELECT MIN(c.created_at) FROM comments c
+				WHERE c.discipline_list_document_id = r.discipline_list_document_id
+				AND c.user_id = rv.user_id
+				AND c.comment_reply_id IS NULL
+				AND c.deleted_at IS NULL
+				AND c.created_at >= r.issued_at),
+			(SELECT ro.set_at FROM review_outcomes ro
+				WHERE ro.document_id = r.document_id
+				AND ro.revision = r.revision
+				AND ro.set_by_id = rv.user_id
+				AND ro.deleted_at IS NULL)
+		) AS responded_at
+	FROM reviewers rv
+	JOIN rounds r ON r.discipline_list_document_id = rv.discipline_list_document_id
+	JOIN users u ON u.id = rv.user_id
+		AND u.deleted_at IS NULL;
+	`
+
+	var stats []dto.StatisticSLAResponse
+	err := tx.Raw(query, packageId).Scan(&stats).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
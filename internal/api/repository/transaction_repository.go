@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transaction runs fn inside one database transaction. The transaction is
+// carried by the context passed to fn, so every repository call made with
+// that context and a nil tx joins it. It rolls back when fn returns an error
+// or panics. Calling Transaction again inside fn opens a savepoint.
+func Transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	return dbFromContext(ctx, db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// dbFromContext returns the transaction started by Transaction, or db when
+// the context is not inside one
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok && tx != nil {
+		return tx
+	}
+
+	return db
+}
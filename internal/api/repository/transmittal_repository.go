@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	TransmittalRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, transmittal entity.Transmittal) (entity.Transmittal, error)
+		GetByID(ctx context.Context, tx *gorm.DB, transmittalId string, preloads ...string) (entity.Transmittal, error)
+		GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, metaReq meta.Meta, preloads ...string) ([]entity.Transmittal, meta.Meta, error)
+		NextSequence(ctx context.Context, tx *gorm.DB, packageId uuid.UUID, direction entity.TransmittalDirection) (int, error)
+		ReplaceDocuments(ctx context.Context, tx *gorm.DB, transmittalId uuid.UUID, documents []entity.TransmittalDocument) error
+		Update(ctx context.Context, tx *gorm.DB, transmittal entity.Transmittal) (entity.Transmittal, error)
+		Delete(ctx context.Context, tx *gorm.DB, transmittal entity.Transmittal) error
+	}
+
+	transmittalRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTransmittal(db *gorm.DB) TransmittalRepository {
+	return &transmittalRepository{
+		db: db,
+	}
+}
+
+func (r *transmittalRepository) Create(ctx context.Context, tx *gorm.DB, transmittal entity.Transmittal) (entity.Transmittal, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Omit("Package", "ReplyTo", "Documents.Document").Create(&transmittal).Error; err != nil {
+		return entity.Transmittal{}, err
+	}
+
+	return transmittal, nil
+}
+
+func (r *transmittalRepository) GetByID(ctx context.Context, tx *gorm.DB, transmittalId string, preloads ...string) (entity.Transmittal, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	var transmittal entity.Transmittal
+	if err := tx.WithContext(ctx).Where("id = ?", transmittalId).First(&transmittal).Error; err != nil {
+		return entity.Transmittal{}, err
+	}
+
+	return transmittal, nil
+}
+
+func (r *transmittalRepository) GetAllByPackageID(ctx context.Context, tx *gorm.DB, packageId string, metaReq meta.Meta, preloads ...string) ([]entity.Transmittal, meta.Meta, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+
+	tx = tx.WithContext(ctx).Model(&entity.Transmittal{}).Where("package_id = ?", packageId)
+
+	filterMap := metaReq.SeparateFilter()
+	if find, ok := filterMap["search"]; ok {
+		tx = tx.Where("number ILIKE ? OR reference_number ILIKE ? OR subject ILIKE ?",
+			"%"+find+"%",
+			"%"+find+"%",
+			"%"+find+"%")
+	}
+
+	var transmittals []entity.Transmittal
+	if err := WithFilters(tx, &metaReq,
+		AddModels(entity.Transmittal{}),
+		AddCustomField("search", ""),
+		WithCursor(),
+	).Find(&transmittals).Error; err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	SetCursor(&metaReq, &transmittals)
+	return transmittals, metaReq, nil
+}
+
+// NextSequence is the next number of the direction in the package, deleted
+// transmittals keep their number. It must run in a transaction, the number
+// stays reserved until the transaction ends.
+func (r *transmittalRepository) NextSequence(ctx context.Context, tx *gorm.DB, packageId uuid.UUID, direction entity.TransmittalDirection) (int, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// concurrent transmittals of the same direction wait for each other here
+	// instead of reading the same MAX(sequence)
+	if err := tx.WithContext(ctx).
+		Exec("SELECT pg_advisory_xact_lock(hashtext(?))", fmt.Sprintf("transmittal:%s:%s", packageId, direction)).Error; err != nil {
+		return 0, err
+	}
+
+	var sequence int
+	if err := tx.WithContext(ctx).Unscoped().Model(&entity.Transmittal{}).
+		Where("package_id = ? AND direction = ?", packageId, direction).
+		Select("COALESCE(MAX(sequence), 0) + 1").
+		Scan(&sequence).Error; err != nil {
+		return 0, err
+	}
+
+	return sequence, nil
+}
+
+func (r *transmittalRepository) ReplaceDocuments(ctx context.Context, tx *gorm.DB, transmittalId uuid.UUID, documents []entity.TransmittalDocument) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).Where("transmittal_id = ?", transmittalId).Delete(&entity.TransmittalDocument{}).Error; err != nil {
+		return err
+	}
+
+	if len(documents) == 0 {
+		return nil
+	}
+
+	for i := range documents {
+		documents[i].TransmittalID = transmittalId
+	}
+
+	return tx.WithContext(ctx).Omit("Transmittal", "Document").Create(&documents).Error
+}
+
+func (r *transmittalRepository) Update(ctx context.Context, tx *gorm.DB, transmittal entity.Transmittal) (entity.Transmittal, error) {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	if err := tx.WithContext(ctx).
+		Omit("Package", "ReplyTo", "Documents").
+		Save(&transmittal).Error; err != nil {
+		return entity.Transmittal{}, err
+	}
+
+	return transmittal, nil
+}
+
+func (r *transmittalRepository) Delete(ctx context.Context, tx *gorm.DB, transmittal entity.Transmittal) error {
+	if tx == nil {
+		tx = dbFromContext(ctx, r.db)
+	}
+
+	// persist deleted_by if provided
+	if transmittal.DeletedBy != uuid.Nil {
+		if err := tx.WithContext(ctx).Model(&entity.Transmittal{}).
+			Where("id = ?", transmittal.ID).
+			Updates(map[string]interface{}{"deleted_by": transmittal.DeletedBy}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Where("transmittal_id = ?", transmittal.ID).Delete(&entity.TransmittalDocument{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Delete(&transmittal).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
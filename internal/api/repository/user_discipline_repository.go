@@ -29,7 +29,7 @@ func NewUserDiscipline(db *gorm.DB) UserDisciplineRepository {
 
 func (r *userDisciplineRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.UserDiscipline, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -48,7 +48,7 @@ func (r *userDisciplineRepository) GetAll(ctx context.Context, tx *gorm.DB, meta
 
 func (r *userDisciplineRepository) GetAllNotAdminAndContractor(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.UserDiscipline, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -68,7 +68,7 @@ func (r *userDisciplineRepository) GetAllNotAdminAndContractor(ctx context.Conte
 
 func (r *userDisciplineRepository) FindAll(ctx context.Context, tx *gorm.DB, preloads ...string) ([]entity.UserDiscipline, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -87,7 +87,7 @@ func (r *userDisciplineRepository) FindAll(ctx context.Context, tx *gorm.DB, pre
 
 func (r *userDisciplineRepository) GetByID(ctx context.Context, tx *gorm.DB, userDisciplineId string, preloads ...string) (entity.UserDiscipline, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -105,7 +105,7 @@ func (r *userDisciplineRepository) GetByID(ctx context.Context, tx *gorm.DB, use
 
 func (r *userDisciplineRepository) GetContractorDiscipline(ctx context.Context, tx *gorm.DB) (entity.UserDiscipline, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	var userDiscipline entity.UserDiscipline
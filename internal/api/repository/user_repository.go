@@ -31,7 +31,7 @@ func NewUser(db *gorm.DB) UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, tx *gorm.DB, user entity.User, preloads ...string) (entity.User, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -47,7 +47,7 @@ func (r *userRepository) Create(ctx context.Context, tx *gorm.DB, user entity.Us
 
 func (r *userRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.Meta, preloads ...string) ([]entity.User, meta.Meta, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -73,16 +73,18 @@ func (r *userRepository) GetAll(ctx context.Context, tx *gorm.DB, metaReq meta.M
 
 	if err := WithFilters(tx, &metaReq,
 		AddModels(entity.User{}),
-		AddCustomField("search", "")).Find(&users).Error; err != nil {
+		AddCustomField("search", ""),
+		WithCursor()).Find(&users).Error; err != nil {
 		return nil, metaReq, err
 	}
 
+	SetCursor(&metaReq, &users)
 	return users, metaReq, nil
 }
 
 func (r *userRepository) GetById(ctx context.Context, tx *gorm.DB, userId string, preloads ...string) (entity.User, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -99,7 +101,7 @@ func (r *userRepository) GetById(ctx context.Context, tx *gorm.DB, userId string
 
 func (r *userRepository) GetByEmail(ctx context.Context, tx *gorm.DB, email string, preloads ...string) (entity.User, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -116,7 +118,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, tx *gorm.DB, email stri
 
 func (r *userRepository) GetContractorByPackage(ctx context.Context, tx *gorm.DB, packageId string, preloads ...string) (entity.User, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -133,7 +135,7 @@ func (r *userRepository) GetContractorByPackage(ctx context.Context, tx *gorm.DB
 
 func (r *userRepository) Update(ctx context.Context, tx *gorm.DB, user entity.User, preloads ...string) (entity.User, error) {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	for _, preload := range preloads {
@@ -149,7 +151,7 @@ func (r *userRepository) Update(ctx context.Context, tx *gorm.DB, user entity.Us
 
 func (r *userRepository) Delete(ctx context.Context, tx *gorm.DB, user entity.User) error {
 	if tx == nil {
-		tx = r.db
+		tx = dbFromContext(ctx, r.db)
 	}
 
 	// persist deleted_by if provided
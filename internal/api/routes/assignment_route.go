@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func Assignment(app *gin.Engine, assignmentcontroller controller.AssignmentController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/discipline-group/:discipline_group_id/assignment")
+	{
+		routes.GET("/rule", middleware.Authenticate(), assignmentcontroller.GetAllRule)
+		routes.POST("/rule", middleware.Authenticate(), assignmentcontroller.CreateRule)
+		routes.PUT("/rule/:assignment_rule_id", middleware.Authenticate(), assignmentcontroller.UpdateRule)
+		routes.DELETE("/rule/:assignment_rule_id", middleware.Authenticate(), assignmentcontroller.DeleteRule)
+		routes.POST("/propose", middleware.Authenticate(), assignmentcontroller.Propose)
+		routes.GET("/matrix", middleware.Authenticate(), assignmentcontroller.GetMatrix)
+		routes.PUT("/matrix", middleware.Authenticate(), assignmentcontroller.Apply)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func CommentClass(app *gin.Engine, commentclasscontroller controller.CommentClassController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/package/:id/comment-class")
+	{
+		routes.GET("", middleware.Authenticate(), commentclasscontroller.GetAll)
+		routes.POST("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), commentclasscontroller.Create)
+		routes.PUT("/:comment_class_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), commentclasscontroller.Update)
+		routes.DELETE("/:comment_class_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), commentclasscontroller.Delete)
+	}
+}
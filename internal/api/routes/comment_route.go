@@ -10,14 +10,20 @@ import (
 func Comment(app *gin.Engine, commentcontroller controller.CommentController, middleware middleware.Middleware) {
 	routes := app.Group("/api/v1/discipline-group/:discipline_group_id/discipline-list-document/:discipline_list_document_id/comment")
 	{
-		routes.POST("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin), string(entity.RoleReviewer)), commentcontroller.Create)
-		routes.POST("/:comment_id/reply", middleware.Authenticate(), commentcontroller.ReplyId)
+		routes.POST("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin), string(entity.RoleReviewer)), middleware.ReviewWindow(), commentcontroller.Create)
+		routes.POST("/:comment_id/reply", middleware.Authenticate(), middleware.ReviewWindow(), commentcontroller.ReplyId)
+		routes.POST("/merge", middleware.Authenticate(), commentcontroller.Merge)
+		routes.POST("/:comment_id/reaction", middleware.Authenticate(), commentcontroller.React)
 
-		routes.GET("", middleware.Authenticate(), commentcontroller.GetAllByDisciplineListDocumentId)
+		routes.GET("", middleware.Authenticate(), middleware.ApplyView(entity.SavedViewComment), commentcontroller.GetAllByDisciplineListDocumentId)
+		routes.GET("/duplicate", middleware.Authenticate(), commentcontroller.GetDuplicates)
 		routes.GET("/:comment_id", middleware.Authenticate(), commentcontroller.GetById)
 		routes.GET("/:comment_id/reply", middleware.Authenticate(), commentcontroller.GetAllReplyByCommentId)
+		routes.GET("/:comment_id/revision", middleware.Authenticate(), commentcontroller.GetRevisions)
 
-		routes.PUT("/:comment_id", middleware.Authenticate(), commentcontroller.Update)
-		routes.DELETE("/:comment_id", middleware.Authenticate(), commentcontroller.Delete)
+		routes.PUT("/:comment_id", middleware.Authenticate(), middleware.ReviewWindow(), commentcontroller.Update)
+		routes.PUT("/:comment_id/consolidate", middleware.Authenticate(), commentcontroller.Consolidate)
+		routes.DELETE("/:comment_id", middleware.Authenticate(), middleware.ReviewWindow(), commentcontroller.Delete)
+		routes.DELETE("/:comment_id/reaction/:type", middleware.Authenticate(), commentcontroller.Unreact)
 	}
 }
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func Delegation(app *gin.Engine, delegationcontroller controller.DelegationController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/delegation")
+	{
+		routes.GET("", middleware.Authenticate(), delegationcontroller.GetAll)
+		routes.POST("", middleware.Authenticate(), delegationcontroller.Create)
+		routes.PUT("/:delegation_id", middleware.Authenticate(), delegationcontroller.Update)
+		routes.DELETE("/:delegation_id", middleware.Authenticate(), delegationcontroller.Delete)
+	}
+}
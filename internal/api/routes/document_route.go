@@ -12,7 +12,7 @@ func Document(app *gin.Engine, documentcontroller controller.DocumentController,
 	{
 		routes.POST("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleContractor), string(entity.RoleSuperAdmin)), documentcontroller.Create)
 		routes.POST("/bulk/:package_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleContractor), string(entity.RoleSuperAdmin)), documentcontroller.CreateBulk)
-		routes.GET("", middleware.Authenticate(), documentcontroller.GetAll)
+		routes.GET("", middleware.Authenticate(), middleware.ApplyView(entity.SavedViewDocument), documentcontroller.GetAll)
 		routes.GET("/:document_id", middleware.Authenticate(), documentcontroller.GetByID)
 		routes.PUT("/:document_id", middleware.Authenticate(), documentcontroller.Update)
 		routes.DELETE("/:document_id", middleware.Authenticate(), documentcontroller.Delete)
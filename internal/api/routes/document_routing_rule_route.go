@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func DocumentRoutingRule(app *gin.Engine, documentroutingrulecontroller controller.DocumentRoutingRuleController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/package/:id/routing-rule")
+	{
+		routes.GET("", middleware.Authenticate(), documentroutingrulecontroller.GetAll)
+		routes.POST("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), documentroutingrulecontroller.Create)
+		routes.PUT("/:routing_rule_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), documentroutingrulecontroller.Update)
+		routes.DELETE("/:routing_rule_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), documentroutingrulecontroller.Delete)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func Job(app *gin.Engine, jobcontroller controller.JobController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/job")
+	{
+		routes.POST("", middleware.Authenticate(), jobcontroller.Submit)
+		routes.GET("/:job_id", middleware.Authenticate(), jobcontroller.GetByID)
+		routes.GET("/:job_id/download", middleware.Authenticate(), jobcontroller.Download)
+	}
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func Notification(app *gin.Engine, notificationcontroller controller.NotificationController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/me/notification")
+	{
+		routes.GET("", middleware.Authenticate(), notificationcontroller.GetAll)
+		routes.PUT("/read", middleware.Authenticate(), notificationcontroller.Read)
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func Queue(app *gin.Engine, queuecontroller controller.QueueController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/me/queue")
+	{
+		routes.GET("", middleware.Authenticate(), queuecontroller.GetQueue)
+	}
+}
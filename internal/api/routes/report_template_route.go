@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func ReportTemplate(app *gin.Engine, reporttemplatecontroller controller.ReportTemplateController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/package/:id/report-template")
+	{
+		routes.GET("", middleware.Authenticate(), reporttemplatecontroller.GetByPackageID)
+		routes.PUT("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), reporttemplatecontroller.Upsert)
+		routes.DELETE("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), reporttemplatecontroller.Delete)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func ReviewOutcome(app *gin.Engine, reviewoutcomecontroller controller.ReviewOutcomeController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/document/:document_id/review-outcome")
+	{
+		routes.GET("", middleware.Authenticate(), reviewoutcomecontroller.Get)
+		routes.PUT("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleReviewer), string(entity.RoleSuperAdmin)), reviewoutcomecontroller.Set)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func ReviewWindow(app *gin.Engine, reviewwindowcontroller controller.ReviewWindowController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/package/:id/review-window")
+	{
+		routes.GET("", middleware.Authenticate(), reviewwindowcontroller.GetAll)
+		routes.POST("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), reviewwindowcontroller.Create)
+		routes.PUT("/:review_window_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), reviewwindowcontroller.Update)
+		routes.DELETE("/:review_window_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), reviewwindowcontroller.Delete)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SavedView(app *gin.Engine, savedviewcontroller controller.SavedViewController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/view")
+	{
+		routes.GET("", middleware.Authenticate(), savedviewcontroller.GetAll)
+		routes.GET("/:view_id", middleware.Authenticate(), savedviewcontroller.GetByID)
+		routes.POST("", middleware.Authenticate(), savedviewcontroller.Create)
+		routes.PUT("/:view_id", middleware.Authenticate(), savedviewcontroller.Update)
+		routes.DELETE("/:view_id", middleware.Authenticate(), savedviewcontroller.Delete)
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func Search(app *gin.Engine, searchcontroller controller.SearchController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/search")
+	{
+		routes.GET("", middleware.Authenticate(), searchcontroller.Search)
+	}
+}
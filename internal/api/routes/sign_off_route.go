@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SignOff(app *gin.Engine, signoffcontroller controller.SignOffController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/sign-off")
+	{
+		routes.POST("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), signoffcontroller.Create)
+		routes.POST("/verify", middleware.Authenticate(), signoffcontroller.Verify)
+		routes.GET("/:sign_off_id", middleware.Authenticate(), signoffcontroller.GetByID)
+		routes.POST("/:sign_off_id/approve", middleware.Authenticate(), signoffcontroller.Approve)
+		routes.POST("/:sign_off_id/reject", middleware.Authenticate(), signoffcontroller.Reject)
+		routes.GET("/:sign_off_id/download", middleware.Authenticate(), signoffcontroller.Download)
+	}
+}
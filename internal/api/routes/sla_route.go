@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SLA(app *gin.Engine, slacontroller controller.SLAController, middleware middleware.Middleware) {
+	rules := app.Group("/api/v1/package/:id/sla-rule")
+	{
+		rules.GET("", middleware.Authenticate(), slacontroller.GetAllRules)
+		rules.POST("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), slacontroller.CreateRule)
+		rules.PUT("/:sla_rule_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), slacontroller.UpdateRule)
+		rules.DELETE("/:sla_rule_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), slacontroller.DeleteRule)
+	}
+
+	holidays := app.Group("/api/v1/package/:id/holiday")
+	{
+		holidays.GET("", middleware.Authenticate(), slacontroller.GetAllHolidays)
+		holidays.POST("", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), slacontroller.CreateHoliday)
+		holidays.PUT("/:holiday_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), slacontroller.UpdateHoliday)
+		holidays.DELETE("/:holiday_id", middleware.Authenticate(), middleware.OnlyAllow(string(entity.RoleSuperAdmin)), slacontroller.DeleteHoliday)
+	}
+}
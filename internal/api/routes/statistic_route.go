@@ -9,9 +9,15 @@ import (
 func Statistic(app *gin.Engine, statisticcontroller controller.StatisticController, middleware middleware.Middleware) {
 	routes := app.Group("/api/v1/statistic")
 	{
-		routes.GET("/aoc-comment-chart/:package_id", statisticcontroller.GetAOCAndCommentChart)
-		routes.GET("/aoc-comment-card/:package_id", statisticcontroller.GetCommentCard)
-		routes.GET("/comment-user-chart/:package_id", statisticcontroller.GetCommentUserChart)
-		routes.GET("/comment-user-data/:package_id", statisticcontroller.GetCommentUserData)
+		routes.GET("/me", middleware.Authenticate(), statisticcontroller.GetMe)
+		routes.GET("/aoc-comment-chart/:package_id", middleware.Authenticate(), statisticcontroller.GetAOCAndCommentChart)
+		routes.GET("/comment-chart/:package_id", middleware.Authenticate(), statisticcontroller.GetCommentChart)
+		routes.GET("/aoc-comment-card/:package_id", middleware.Authenticate(), statisticcontroller.GetCommentCard)
+		routes.GET("/comment-user-chart/:package_id", middleware.Authenticate(), statisticcontroller.GetCommentUserChart)
+		routes.GET("/comment-user-data/:package_id", middleware.Authenticate(), statisticcontroller.GetCommentUserData)
+		routes.GET("/comment-category-chart/:package_id", middleware.Authenticate(), statisticcontroller.GetCommentCategoryChart)
+		routes.GET("/comment-severity-chart/:package_id", middleware.Authenticate(), statisticcontroller.GetCommentSeverityChart)
+		routes.GET("/review-outcome-chart/:package_id", middleware.Authenticate(), statisticcontroller.GetReviewOutcomeChart)
+		routes.GET("/sla-compliance/:package_id", middleware.Authenticate(), statisticcontroller.GetSLACompliance)
 	}
 }
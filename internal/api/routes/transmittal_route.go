@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/CRS-Project/crs-backend/internal/api/controller"
+	"github.com/CRS-Project/crs-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func Transmittal(app *gin.Engine, transmittalcontroller controller.TransmittalController, middleware middleware.Middleware) {
+	routes := app.Group("/api/v1/package/:id/transmittal")
+	{
+		routes.GET("", middleware.Authenticate(), transmittalcontroller.GetAll)
+		routes.GET("/:transmittal_id", middleware.Authenticate(), transmittalcontroller.GetByID)
+		routes.GET("/:transmittal_id/cover-sheet", middleware.Authenticate(), transmittalcontroller.GenerateCoverSheet)
+		routes.POST("", middleware.Authenticate(), transmittalcontroller.Create)
+		routes.PUT("/:transmittal_id", middleware.Authenticate(), transmittalcontroller.Update)
+		routes.DELETE("/:transmittal_id", middleware.Authenticate(), transmittalcontroller.Delete)
+	}
+}
@@ -0,0 +1,721 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	AssignmentService interface {
+		GetAllRule(ctx context.Context, userId, disciplineGroupId string) ([]dto.AssignmentRuleResponse, error)
+		CreateRule(ctx context.Context, req dto.AssignmentRuleRequest) (dto.AssignmentRuleResponse, error)
+		UpdateRule(ctx context.Context, req dto.AssignmentRuleRequest) (dto.AssignmentRuleResponse, error)
+		DeleteRule(ctx context.Context, userId, disciplineGroupId, assignmentRuleId string) error
+		Propose(ctx context.Context, req dto.AssignmentProposeRequest) (dto.AssignmentMatrixResponse, error)
+		GetMatrix(ctx context.Context, userId, disciplineGroupId string) (dto.AssignmentMatrixResponse, error)
+		Apply(ctx context.Context, req dto.AssignmentApplyRequest) (dto.AssignmentMatrixResponse, error)
+	}
+
+	assignmentService struct {
+		assignmentRuleRepository                     repository.AssignmentRuleRepository
+		disciplineGroupRepository                    repository.DisciplineGroupRepository
+		disciplineGroupConsolidatorRepository        repository.DisciplineGroupConsolidatorRepository
+		disciplineListDocumentRepository             repository.DisciplineListDocumentRepository
+		disciplineListDocumentConsolidatorRepository repository.DisciplineListDocumentConsolidatorRepository
+		documentRepository                           repository.DocumentRepository
+		userRepository                               repository.UserRepository
+		userDisciplineRepository                     repository.UserDisciplineRepository
+		delegationRepository                         repository.DelegationRepository
+		notificationService                          NotificationService
+		db                                           *gorm.DB
+	}
+)
+
+func NewAssignment(assignmentRuleRepository repository.AssignmentRuleRepository,
+	disciplineGroupRepository repository.DisciplineGroupRepository,
+	disciplineGroupConsolidatorRepository repository.DisciplineGroupConsolidatorRepository,
+	disciplineListDocumentRepository repository.DisciplineListDocumentRepository,
+	disciplineListDocumentConsolidatorRepository repository.DisciplineListDocumentConsolidatorRepository,
+	documentRepository repository.DocumentRepository,
+	userRepository repository.UserRepository,
+	userDisciplineRepository repository.UserDisciplineRepository,
+	delegationRepository repository.DelegationRepository,
+	notificationService NotificationService,
+	db *gorm.DB) AssignmentService {
+	return &assignmentService{
+		assignmentRuleRepository:                     assignmentRuleRepository,
+		disciplineGroupRepository:                    disciplineGroupRepository,
+		disciplineGroupConsolidatorRepository:        disciplineGroupConsolidatorRepository,
+		disciplineListDocumentRepository:             disciplineListDocumentRepository,
+		disciplineListDocumentConsolidatorRepository: disciplineListDocumentConsolidatorRepository,
+		documentRepository:                           documentRepository,
+		userRepository:                               userRepository,
+		userDisciplineRepository:                     userDisciplineRepository,
+		delegationRepository:                         delegationRepository,
+		notificationService:                          notificationService,
+		db:                                           db,
+	}
+}
+
+func (s *assignmentService) GetAllRule(ctx context.Context, userId, disciplineGroupId string) ([]dto.AssignmentRuleResponse, error) {
+	if _, err := s.getDisciplineGroup(ctx, userId, disciplineGroupId); err != nil {
+		return nil, err
+	}
+
+	assignmentRules, err := s.assignmentRuleRepository.GetAllByDisciplineGroupID(ctx, nil, disciplineGroupId, "UserDiscipline", "User")
+	if err != nil {
+		return nil, err
+	}
+
+	var res []dto.AssignmentRuleResponse
+	for _, assignmentRule := range assignmentRules {
+		res = append(res, assignmentRuleResponse(assignmentRule))
+	}
+
+	return res, nil
+}
+
+func (s *assignmentService) CreateRule(ctx context.Context, req dto.AssignmentRuleRequest) (dto.AssignmentRuleResponse, error) {
+	disciplineGroup, err := s.getDisciplineGroup(ctx, req.UserId, req.DisciplineGroupID)
+	if err != nil {
+		return dto.AssignmentRuleResponse{}, err
+	}
+
+	assignmentRule := entity.AssignmentRule{DisciplineGroupID: disciplineGroup.ID}
+	if err := s.fillRule(ctx, &assignmentRule, req); err != nil {
+		return dto.AssignmentRuleResponse{}, err
+	}
+
+	assignmentRule, err = s.assignmentRuleRepository.Create(ctx, nil, assignmentRule)
+	if err != nil {
+		return dto.AssignmentRuleResponse{}, err
+	}
+
+	return s.getRuleResponse(ctx, assignmentRule.ID.String())
+}
+
+func (s *assignmentService) UpdateRule(ctx context.Context, req dto.AssignmentRuleRequest) (dto.AssignmentRuleResponse, error) {
+	assignmentRule, err := s.getRule(ctx, req.UserId, req.DisciplineGroupID, req.ID)
+	if err != nil {
+		return dto.AssignmentRuleResponse{}, err
+	}
+
+	if err := s.fillRule(ctx, &assignmentRule, req); err != nil {
+		return dto.AssignmentRuleResponse{}, err
+	}
+
+	if _, err := s.assignmentRuleRepository.Update(ctx, nil, assignmentRule); err != nil {
+		return dto.AssignmentRuleResponse{}, err
+	}
+
+	return s.getRuleResponse(ctx, assignmentRule.ID.String())
+}
+
+func (s *assignmentService) DeleteRule(ctx context.Context, userId, disciplineGroupId, assignmentRuleId string) error {
+	assignmentRule, err := s.getRule(ctx, userId, disciplineGroupId, assignmentRuleId)
+	if err != nil {
+		return err
+	}
+
+	// mark who deleted
+	assignmentRule.DeletedBy = uuid.MustParse(userId)
+	if err := s.assignmentRuleRepository.Delete(ctx, nil, assignmentRule); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Propose picks consolidators for every document. The first matching rule
+// decides who is eligible and how many are needed; without a rule the
+// consolidators whose discipline matches the document are preferred. Among
+// the eligible ones the least loaded are picked, where load is the open
+// comments plus the documents already assigned, including the ones assigned
+// earlier in the same proposal. Consolidators who are away are only picked
+// when nobody else is eligible, their substitute takes the work.
+func (s *assignmentService) Propose(ctx context.Context, req dto.AssignmentProposeRequest) (dto.AssignmentMatrixResponse, error) {
+	disciplineGroup, err := s.getDisciplineGroup(ctx, req.UserId, req.DisciplineGroupID)
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	reviewers, consolidators, err := s.getReviewers(ctx, disciplineGroup.ID.String())
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	if len(reviewers) == 0 {
+		return dto.AssignmentMatrixResponse{}, myerror.New("discipline group has no consolidator yet", http.StatusBadRequest)
+	}
+
+	assignmentRules, err := s.assignmentRuleRepository.GetAllByDisciplineGroupID(ctx, nil, disciplineGroup.ID.String())
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	documents, err := s.getDocuments(ctx, disciplineGroup, req.DocumentIDs)
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	existing, err := s.getExisting(ctx, disciplineGroup.ID.String(), req.DocumentIDs)
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	reviewersPerDocument := req.ReviewersPerDocument
+	if reviewersPerDocument == 0 {
+		reviewersPerDocument = 1
+	}
+
+	load := make([]int, len(reviewers))
+	for i, r := range reviewers {
+		load[i] = r.OpenComment + r.AssignedDocument
+	}
+
+	res := dto.AssignmentMatrixResponse{Reviewers: reviewers}
+	for _, document := range documents {
+		count := reviewersPerDocument
+		var candidates []int
+		var pinned *uuid.UUID
+		var reason string
+
+		rule := matchAssignmentRule(assignmentRules, document)
+		if rule != nil {
+			reason = "rule " + rule.Name
+			if rule.ReviewerCount > 0 {
+				count = rule.ReviewerCount
+			}
+			pinned = rule.UserID
+
+			for i, c := range consolidators {
+				if rule.UserDisciplineID == nil || c.User.UserDisciplineID == *rule.UserDisciplineID {
+					candidates = append(candidates, i)
+				}
+			}
+		} else {
+			for i, c := range consolidators {
+				if matchDiscipline(c.User, document.Discipline) {
+					candidates = append(candidates, i)
+				}
+			}
+
+			reason = "discipline " + document.Discipline
+			if len(candidates) == 0 {
+				reason = "no rule or discipline match, balanced over all consolidators"
+				for i := range consolidators {
+					candidates = append(candidates, i)
+				}
+			}
+		}
+
+		var picks []int
+		if pinned != nil {
+			for i, c := range consolidators {
+				if c.UserID == *pinned {
+					picks = append(picks, i)
+				}
+			}
+		}
+
+		sort.SliceStable(candidates, func(a, b int) bool {
+			ra, rb := reviewers[candidates[a]], reviewers[candidates[b]]
+			if (ra.Substitute == nil) != (rb.Substitute == nil) {
+				return ra.Substitute == nil
+			}
+			if load[candidates[a]] != load[candidates[b]] {
+				return load[candidates[a]] < load[candidates[b]]
+			}
+			if ra.DisciplineNumber != rb.DisciplineNumber {
+				return ra.DisciplineNumber < rb.DisciplineNumber
+			}
+			return ra.Name < rb.Name
+		})
+
+		for _, i := range candidates {
+			if len(picks) >= count {
+				break
+			}
+			if pinned != nil && consolidators[i].UserID == *pinned {
+				continue
+			}
+			picks = append(picks, i)
+		}
+
+		if len(picks) < count {
+			reason += fmt.Sprintf(", only %d of %d reviewers available", len(picks), count)
+		}
+
+		item := assignmentItem(document, existing[document.ID])
+		item.Reason = reason
+		item.ConsolidatorIDs = []string{}
+		for _, i := range picks {
+			load[i]++
+			item.ConsolidatorIDs = append(item.ConsolidatorIDs, reviewers[i].ConsolidatorID)
+		}
+
+		res.Assignments = append(res.Assignments, item)
+	}
+
+	return res, nil
+}
+
+func (s *assignmentService) GetMatrix(ctx context.Context, userId, disciplineGroupId string) (dto.AssignmentMatrixResponse, error) {
+	disciplineGroup, err := s.getDisciplineGroup(ctx, userId, disciplineGroupId)
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	return s.getMatrix(ctx, disciplineGroup.ID.String())
+}
+
+// Apply sets the consolidators of every listed document in one transaction.
+// Documents that are not in the discipline group yet are added to it.
+func (s *assignmentService) Apply(ctx context.Context, req dto.AssignmentApplyRequest) (dto.AssignmentMatrixResponse, error) {
+	disciplineGroup, err := s.getDisciplineGroup(ctx, req.UserId, req.DisciplineGroupID)
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	consolidators, err := s.disciplineGroupConsolidatorRepository.GetAllConsolidator(ctx, nil, "", disciplineGroup.ID.String())
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	consolidatorMap := make(map[string]bool)
+	for _, c := range consolidators {
+		consolidatorMap[c.ID.String()] = true
+	}
+
+	var documentIds []string
+	for _, a := range req.Assignments {
+		for _, consolidatorId := range a.ConsolidatorIDs {
+			if !consolidatorMap[consolidatorId] {
+				return dto.AssignmentMatrixResponse{}, myerror.New("consolidator "+consolidatorId+" is not in this discipline group", http.StatusBadRequest)
+			}
+		}
+		documentIds = append(documentIds, a.DocumentID)
+	}
+
+	documents, err := s.getDocuments(ctx, disciplineGroup, documentIds)
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	existing, err := s.getExisting(ctx, disciplineGroup.ID.String(), documentIds)
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	documentMap := make(map[string]entity.Document)
+	for _, document := range documents {
+		documentMap[document.ID.String()] = document
+	}
+
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		for _, a := range req.Assignments {
+			document := documentMap[a.DocumentID]
+
+			wanted := make(map[string]bool)
+			for _, consolidatorId := range a.ConsolidatorIDs {
+				wanted[consolidatorId] = true
+			}
+
+			dld, ok := existing[document.ID]
+			if !ok {
+				var consolidatorsInput []entity.DisciplineListDocumentConsolidator
+				for consolidatorId := range wanted {
+					consolidatorsInput = append(consolidatorsInput, entity.DisciplineListDocumentConsolidator{
+						DisciplineGroupConsolidatorID: uuid.MustParse(consolidatorId),
+					})
+				}
+
+				dld, err := s.disciplineListDocumentRepository.Create(ctx, nil, entity.DisciplineListDocument{
+					DisciplineGroupID: disciplineGroup.ID,
+					DocumentID:        document.ID,
+					PackageID:         disciplineGroup.PackageID,
+					Consolidators:     consolidatorsInput,
+				})
+				if err != nil {
+					return err
+				}
+
+				notifyQuietly(ctx, s.db, func(ctx context.Context) error {
+					return s.notificationService.NotifyAssignment(ctx, document, dld.ID, a.ConsolidatorIDs)
+				})
+				continue
+			}
+
+			current := make(map[string]bool)
+			var toDelete []string
+			for _, c := range dld.Consolidators {
+				current[c.DisciplineGroupConsolidatorID.String()] = true
+				if !wanted[c.DisciplineGroupConsolidatorID.String()] {
+					toDelete = append(toDelete, c.ID.String())
+				}
+			}
+
+			var toCreate []entity.DisciplineListDocumentConsolidator
+			var added []string
+			for consolidatorId := range wanted {
+				if !current[consolidatorId] {
+					toCreate = append(toCreate, entity.DisciplineListDocumentConsolidator{
+						DisciplineListDocumentID:      dld.ID,
+						DisciplineGroupConsolidatorID: uuid.MustParse(consolidatorId),
+					})
+					added = append(added, consolidatorId)
+				}
+			}
+
+			if len(toDelete) > 0 {
+				if err := s.disciplineListDocumentConsolidatorRepository.DeleteBulk(ctx, nil, toDelete); err != nil {
+					return err
+				}
+			}
+
+			if len(toCreate) > 0 {
+				if err := s.disciplineListDocumentConsolidatorRepository.CreateBulk(ctx, nil, toCreate); err != nil {
+					return err
+				}
+
+				notifyQuietly(ctx, s.db, func(ctx context.Context) error {
+					return s.notificationService.NotifyAssignment(ctx, document, dld.ID, added)
+				})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	return s.getMatrix(ctx, disciplineGroup.ID.String())
+}
+
+func (s *assignmentService) getMatrix(ctx context.Context, disciplineGroupId string) (dto.AssignmentMatrixResponse, error) {
+	reviewers, _, err := s.getReviewers(ctx, disciplineGroupId)
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	dlds, err := s.disciplineListDocumentRepository.GetAllByDocumentIDs(ctx, nil, disciplineGroupId, nil, "Document", "Consolidators")
+	if err != nil {
+		return dto.AssignmentMatrixResponse{}, err
+	}
+
+	res := dto.AssignmentMatrixResponse{Reviewers: reviewers}
+	for _, dld := range dlds {
+		if dld.Document == nil {
+			continue
+		}
+
+		res.Assignments = append(res.Assignments, assignmentItem(*dld.Document, dld))
+	}
+
+	sort.SliceStable(res.Assignments, func(a, b int) bool {
+		return res.Assignments[a].CompanyDocumentNumber < res.Assignments[b].CompanyDocumentNumber
+	})
+
+	return res, nil
+}
+
+// getReviewers returns the consolidators of the group with their load, in the
+// same order as the consolidator entities
+func (s *assignmentService) getReviewers(ctx context.Context, disciplineGroupId string) ([]dto.AssignmentReviewer, []entity.DisciplineGroupConsolidator, error) {
+	consolidators, err := s.disciplineGroupConsolidatorRepository.GetAllConsolidator(ctx, nil, "", disciplineGroupId, "User.UserDiscipline")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// consolidators whose user was deleted have no user and can't review
+	var available []entity.DisciplineGroupConsolidator
+	var userIds []string
+	for _, c := range consolidators {
+		if c.User == nil {
+			continue
+		}
+		available = append(available, c)
+		userIds = append(userIds, c.UserID.String())
+	}
+
+	if len(available) == 0 {
+		return []dto.AssignmentReviewer{}, nil, nil
+	}
+
+	loads, err := s.disciplineGroupConsolidatorRepository.GetReviewerLoad(ctx, nil, userIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loadMap := make(map[string]dto.ReviewerLoad)
+	for _, l := range loads {
+		loadMap[l.UserID] = l
+	}
+
+	delegations, err := s.delegationRepository.GetActiveByUserIDs(ctx, nil, userIds, time.Now(), "Substitute")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	substituteMap := make(map[uuid.UUID]*dto.AssignmentSubstitute)
+	for _, d := range delegations {
+		if d.Substitute == nil {
+			continue
+		}
+		substituteMap[d.UserID] = &dto.AssignmentSubstitute{
+			UserID: d.SubstituteID.String(),
+			Name:   d.Substitute.Name,
+			Until:  d.EndAt,
+		}
+	}
+
+	var reviewers []dto.AssignmentReviewer
+	for _, c := range available {
+		reviewer := dto.AssignmentReviewer{
+			ConsolidatorID:   c.ID.String(),
+			UserID:           c.UserID.String(),
+			Name:             c.User.Name,
+			Initial:          c.User.Initial,
+			DisciplineNumber: c.User.DisciplineNumber,
+			OpenComment:      loadMap[c.UserID.String()].OpenComment,
+			AssignedDocument: loadMap[c.UserID.String()].AssignedDocument,
+			Substitute:       substituteMap[c.UserID],
+		}
+		if c.User.UserDiscipline != nil {
+			reviewer.UserDiscipline = c.User.UserDiscipline.Name
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+
+	return reviewers, available, nil
+}
+
+func (s *assignmentService) getDocuments(ctx context.Context, disciplineGroup entity.DisciplineGroup, documentIds []string) ([]entity.Document, error) {
+	seen := make(map[string]bool)
+	for _, documentId := range documentIds {
+		if _, err := uuid.Parse(documentId); err != nil {
+			return nil, myerror.New("invalid document_id: "+documentId, http.StatusBadRequest)
+		}
+		if seen[documentId] {
+			return nil, myerror.New("document "+documentId+" is listed more than once", http.StatusBadRequest)
+		}
+		seen[documentId] = true
+	}
+
+	documents, err := s.documentRepository.GetByIDs(ctx, nil, documentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(documents) != len(documentIds) {
+		return nil, myerror.New("some documents were not found", http.StatusNotFound)
+	}
+
+	for _, document := range documents {
+		if document.PackageID != disciplineGroup.PackageID {
+			return nil, myerror.New("document "+document.CompanyDocumentNumber+" is not in the package of this discipline group", http.StatusBadRequest)
+		}
+	}
+
+	return documents, nil
+}
+
+func (s *assignmentService) getExisting(ctx context.Context, disciplineGroupId string, documentIds []string) (map[uuid.UUID]entity.DisciplineListDocument, error) {
+	dlds, err := s.disciplineListDocumentRepository.GetAllByDocumentIDs(ctx, nil, disciplineGroupId, documentIds, "Consolidators")
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[uuid.UUID]entity.DisciplineListDocument)
+	for _, dld := range dlds {
+		existing[dld.DocumentID] = dld
+	}
+
+	return existing, nil
+}
+
+func (s *assignmentService) fillRule(ctx context.Context, assignmentRule *entity.AssignmentRule, req dto.AssignmentRuleRequest) error {
+	matchField := entity.AssignmentMatchField(req.MatchField)
+	valid := false
+	for _, f := range entity.AssignmentMatchFields {
+		valid = valid || f == matchField
+	}
+	if !valid {
+		return myerror.New("match_field must be one of discipline, sub_discipline, document_type, document_category, wbs", http.StatusBadRequest)
+	}
+
+	assignmentRule.Name = req.Name
+	assignmentRule.Priority = req.Priority
+	assignmentRule.MatchField = matchField
+	assignmentRule.MatchValue = strings.TrimSpace(req.MatchValue)
+	assignmentRule.ReviewerCount = req.ReviewerCount
+	assignmentRule.UserDisciplineID = nil
+	assignmentRule.UserID = nil
+	assignmentRule.UpdatedBy = uuid.MustParse(req.UserId)
+	if assignmentRule.ReviewerCount == 0 {
+		assignmentRule.ReviewerCount = 1
+	}
+
+	if req.UserDisciplineID != nil {
+		userDiscipline, err := s.userDisciplineRepository.GetByID(ctx, nil, *req.UserDisciplineID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return myerror.New("user discipline not found", http.StatusNotFound)
+			}
+			return err
+		}
+		assignmentRule.UserDisciplineID = &userDiscipline.ID
+	}
+
+	if req.UserID != nil {
+		consolidators, err := s.disciplineGroupConsolidatorRepository.GetAllConsolidator(ctx, nil, "", assignmentRule.DisciplineGroupID.String())
+		if err != nil {
+			return err
+		}
+
+		for _, c := range consolidators {
+			if c.UserID.String() == *req.UserID {
+				userId := c.UserID
+				assignmentRule.UserID = &userId
+			}
+		}
+
+		if assignmentRule.UserID == nil {
+			return myerror.New("user_id must be a consolidator of this discipline group", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+func (s *assignmentService) getRule(ctx context.Context, userId, disciplineGroupId, assignmentRuleId string) (entity.AssignmentRule, error) {
+	disciplineGroup, err := s.getDisciplineGroup(ctx, userId, disciplineGroupId)
+	if err != nil {
+		return entity.AssignmentRule{}, err
+	}
+
+	assignmentRule, err := s.assignmentRuleRepository.GetByID(ctx, nil, assignmentRuleId)
+	if err != nil {
+		return entity.AssignmentRule{}, err
+	}
+
+	if assignmentRule.DisciplineGroupID != disciplineGroup.ID {
+		return entity.AssignmentRule{}, myerror.New("assignment rule not found", http.StatusNotFound)
+	}
+
+	return assignmentRule, nil
+}
+
+func (s *assignmentService) getRuleResponse(ctx context.Context, assignmentRuleId string) (dto.AssignmentRuleResponse, error) {
+	assignmentRule, err := s.assignmentRuleRepository.GetByID(ctx, nil, assignmentRuleId, "UserDiscipline", "User")
+	if err != nil {
+		return dto.AssignmentRuleResponse{}, err
+	}
+
+	return assignmentRuleResponse(assignmentRule), nil
+}
+
+func (s *assignmentService) getDisciplineGroup(ctx context.Context, userId, disciplineGroupId string) (entity.DisciplineGroup, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return entity.DisciplineGroup{}, err
+	}
+
+	disciplineGroup, err := s.disciplineGroupRepository.GetByID(ctx, nil, disciplineGroupId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.DisciplineGroup{}, myerror.New("discipline group not found", http.StatusNotFound)
+		}
+		return entity.DisciplineGroup{}, err
+	}
+
+	if user.PackageID != nil && *user.PackageID != disciplineGroup.PackageID {
+		return entity.DisciplineGroup{}, myerror.New("you not allowed to this package", http.StatusUnauthorized)
+	}
+
+	return disciplineGroup, nil
+}
+
+func matchAssignmentRule(assignmentRules []entity.AssignmentRule, document entity.Document) *entity.AssignmentRule {
+	for i := range assignmentRules {
+		if assignmentRules[i].Matches(document) {
+			return &assignmentRules[i]
+		}
+	}
+
+	return nil
+}
+
+func matchDiscipline(user *entity.User, discipline string) bool {
+	if user.UserDiscipline == nil || discipline == "" {
+		return false
+	}
+
+	discipline = strings.TrimSpace(discipline)
+	return strings.EqualFold(user.UserDiscipline.Name, discipline) || strings.EqualFold(user.UserDiscipline.Initial, discipline)
+}
+
+func assignmentItem(document entity.Document, dld entity.DisciplineListDocument) dto.AssignmentItem {
+	item := dto.AssignmentItem{
+		DocumentID:            document.ID.String(),
+		CompanyDocumentNumber: document.CompanyDocumentNumber,
+		DocumentTitle:         document.DocumentTitle,
+		Discipline:            document.Discipline,
+		ConsolidatorIDs:       []string{},
+	}
+
+	if dld.ID != uuid.Nil {
+		dldId := dld.ID.String()
+		item.DisciplineListDocumentID = &dldId
+	}
+
+	for _, c := range dld.Consolidators {
+		item.ConsolidatorIDs = append(item.ConsolidatorIDs, c.DisciplineGroupConsolidatorID.String())
+	}
+
+	return item
+}
+
+func assignmentRuleResponse(assignmentRule entity.AssignmentRule) dto.AssignmentRuleResponse {
+	res := dto.AssignmentRuleResponse{
+		ID:            assignmentRule.ID.String(),
+		Name:          assignmentRule.Name,
+		Priority:      assignmentRule.Priority,
+		MatchField:    string(assignmentRule.MatchField),
+		MatchValue:    assignmentRule.MatchValue,
+		ReviewerCount: assignmentRule.ReviewerCount,
+	}
+
+	if assignmentRule.UserDisciplineID != nil {
+		userDisciplineId := assignmentRule.UserDisciplineID.String()
+		res.UserDisciplineID = &userDisciplineId
+	}
+
+	if assignmentRule.UserDiscipline != nil {
+		res.UserDiscipline = &assignmentRule.UserDiscipline.Name
+	}
+
+	if assignmentRule.UserID != nil {
+		userId := assignmentRule.UserID.String()
+		res.UserID = &userId
+	}
+
+	if assignmentRule.User != nil {
+		res.UserName = &assignmentRule.User.Name
+	}
+
+	return res
+}
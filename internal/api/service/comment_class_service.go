@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	CommentClassService interface {
+		GetAll(ctx context.Context, userId, packageId string) ([]dto.CommentClassResponse, error)
+		Create(ctx context.Context, req dto.CommentClassRequest) (dto.CommentClassResponse, error)
+		Update(ctx context.Context, req dto.CommentClassRequest) (dto.CommentClassResponse, error)
+		Delete(ctx context.Context, userId, packageId, commentClassId string) error
+	}
+
+	commentClassService struct {
+		commentClassRepository repository.CommentClassRepository
+		commentRepository      repository.CommentRepository
+		packageRepository      repository.PackageRepository
+		userRepository         repository.UserRepository
+		db                     *gorm.DB
+	}
+)
+
+func NewCommentClass(commentClassRepository repository.CommentClassRepository,
+	commentRepository repository.CommentRepository,
+	packageRepository repository.PackageRepository,
+	userRepository repository.UserRepository,
+	db *gorm.DB) CommentClassService {
+	return &commentClassService{
+		commentClassRepository: commentClassRepository,
+		commentRepository:      commentRepository,
+		packageRepository:      packageRepository,
+		userRepository:         userRepository,
+		db:                     db,
+	}
+}
+
+func (s *commentClassService) GetAll(ctx context.Context, userId, packageId string) ([]dto.CommentClassResponse, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.PackageID != nil && user.PackageID.String() != packageId {
+		return nil, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	commentClasses, err := s.commentClassRepository.GetAllByPackageID(ctx, nil, packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []dto.CommentClassResponse{}
+	for _, commentClass := range commentClasses {
+		res = append(res, commentClassResponse(commentClass))
+	}
+
+	return res, nil
+}
+
+func (s *commentClassService) Create(ctx context.Context, req dto.CommentClassRequest) (dto.CommentClassResponse, error) {
+	pkg, err := s.packageRepository.GetByID(ctx, nil, req.PackageID)
+	if err != nil {
+		return dto.CommentClassResponse{}, err
+	}
+
+	commentClass := entity.CommentClass{
+		Kind:      entity.CommentClassKind(req.Kind),
+		PackageID: pkg.ID,
+	}
+	if err := s.fill(ctx, &commentClass, req); err != nil {
+		return dto.CommentClassResponse{}, err
+	}
+
+	commentClass, err = s.commentClassRepository.Create(ctx, nil, commentClass)
+	if err != nil {
+		return dto.CommentClassResponse{}, err
+	}
+
+	return commentClassResponse(commentClass), nil
+}
+
+func (s *commentClassService) Update(ctx context.Context, req dto.CommentClassRequest) (dto.CommentClassResponse, error) {
+	commentClass, err := s.get(ctx, req.PackageID, req.ID)
+	if err != nil {
+		return dto.CommentClassResponse{}, err
+	}
+
+	// comments point to the class as a category or a severity
+	if string(commentClass.Kind) != req.Kind {
+		return dto.CommentClassResponse{}, myerror.New("kind of a comment class can't be changed", http.StatusBadRequest)
+	}
+
+	if err := s.fill(ctx, &commentClass, req); err != nil {
+		return dto.CommentClassResponse{}, err
+	}
+
+	commentClass, err = s.commentClassRepository.Update(ctx, nil, commentClass)
+	if err != nil {
+		return dto.CommentClassResponse{}, err
+	}
+
+	return commentClassResponse(commentClass), nil
+}
+
+func (s *commentClassService) Delete(ctx context.Context, userId, packageId, commentClassId string) error {
+	commentClass, err := s.get(ctx, packageId, commentClassId)
+	if err != nil {
+		return err
+	}
+
+	used, err := s.commentRepository.CountByClassID(ctx, nil, commentClass.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if used > 0 {
+		return myerror.New(fmt.Sprintf("comment class is used by %d comments", used), http.StatusBadRequest)
+	}
+
+	// mark who deleted
+	commentClass.DeletedBy = uuid.MustParse(userId)
+	if err := s.commentClassRepository.Delete(ctx, nil, commentClass); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *commentClassService) fill(ctx context.Context, commentClass *entity.CommentClass, req dto.CommentClassRequest) error {
+	commentClass.Code = strings.TrimSpace(req.Code)
+	commentClass.Name = strings.TrimSpace(req.Name)
+	commentClass.Description = req.Description
+	commentClass.Rank = req.Rank
+	commentClass.ReviewOutcome = (*entity.ReviewOutcomeCode)(req.ReviewOutcome)
+	commentClass.UpdatedBy = uuid.MustParse(req.UserId)
+
+	if commentClass.Code == "" || commentClass.Name == "" {
+		return myerror.New("code and name are required", http.StatusBadRequest)
+	}
+
+	if commentClass.ReviewOutcome != nil && commentClass.Kind != entity.CommentClassSeverity {
+		return myerror.New("only severities lead to a review outcome", http.StatusBadRequest)
+	}
+
+	exists, err := s.commentClassRepository.ExistsByCode(ctx, nil, *commentClass)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return myerror.New(fmt.Sprintf("code %s is already used in this package", commentClass.Code), http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (s *commentClassService) get(ctx context.Context, packageId, commentClassId string) (entity.CommentClass, error) {
+	commentClass, err := s.commentClassRepository.GetByID(ctx, nil, commentClassId)
+	if err != nil {
+		return entity.CommentClass{}, err
+	}
+
+	if commentClass.PackageID.String() != packageId {
+		return entity.CommentClass{}, myerror.New("comment class not found", http.StatusNotFound)
+	}
+
+	return commentClass, nil
+}
+
+func commentClassResponse(commentClass entity.CommentClass) dto.CommentClassResponse {
+	return dto.CommentClassResponse{
+		ID:            commentClass.ID.String(),
+		Kind:          string(commentClass.Kind),
+		Code:          commentClass.Code,
+		Name:          commentClass.Name,
+		Description:   commentClass.Description,
+		Rank:          commentClass.Rank,
+		ReviewOutcome: (*string)(commentClass.ReviewOutcome),
+		PackageID:     commentClass.PackageID.String(),
+	}
+}
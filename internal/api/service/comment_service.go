@@ -2,7 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/CRS-Project/crs-backend/internal/api/repository"
@@ -10,46 +17,95 @@ import (
 	"github.com/CRS-Project/crs-backend/internal/entity"
 	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
 	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/CRS-Project/crs-backend/internal/pkg/similarity"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+const (
+	maxAnchorQuoteLength = 1000
+
+	// comments scoring at least this much against each other are flagged as
+	// likely duplicates, see COMMENT_SIMILARITY_THRESHOLD
+	defaultCommentSimilarityThreshold = 0.5
+	maxSimilarComments                = 5
+
+	// how deep replies may nest below a top level comment, see
+	// COMMENT_MAX_THREAD_DEPTH
+	defaultCommentMaxThreadDepth = 3
+)
+
 type (
 	CommentService interface {
 		Create(ctx context.Context, req dto.CommentRequest) (dto.CommentResponse, error)
 		Reply(ctx context.Context, req dto.CommentRequest) (dto.CommentResponse, error)
-		GetById(ctx context.Context, id string) (dto.CommentResponse, error)
+		GetById(ctx context.Context, userId, id string) (dto.CommentResponse, error)
 		GetAllByDisciplineListDocumentId(ctx context.Context, userId, disciplineListDocumentId string, metaReq meta.Meta) ([]dto.CommentResponse, meta.Meta, error)
 		GetAllByReplyId(ctx context.Context, userId, disciplineListDocumentId, replyId string, metaReq meta.Meta) ([]dto.CommentResponse, meta.Meta, error)
 		Update(ctx context.Context, req dto.UpdateCommentRequest) error
 		Delete(ctx context.Context, userId, disciplineListDocumentId, commentId string) error
+		GetDuplicates(ctx context.Context, userId, disciplineListDocumentId string) ([]dto.CommentCluster, error)
+		Merge(ctx context.Context, req dto.MergeCommentRequest) (dto.CommentResponse, error)
+		Consolidate(ctx context.Context, req dto.ConsolidateCommentRequest) (dto.CommentResponse, error)
+		React(ctx context.Context, req dto.CommentReactionRequest) ([]dto.CommentReaction, error)
+		Unreact(ctx context.Context, req dto.CommentReactionRequest) ([]dto.CommentReaction, error)
+		GetRevisions(ctx context.Context, userId, disciplineListDocumentId, commentId string) ([]dto.CommentRevisionResponse, error)
 	}
 
 	commentService struct {
 		commentRepository                repository.CommentRepository
+		commentClassRepository           repository.CommentClassRepository
 		documentRepository               repository.DocumentRepository
 		disciplineListDocumentRepository repository.DisciplineListDocumentRepository
 		userRepository                   repository.UserRepository
+		delegationRepository             repository.DelegationRepository
+		notificationService              NotificationService
 		db                               *gorm.DB
+
+		similarityThreshold float64
+		maxThreadDepth      int
 	}
 )
 
 func NewComment(commentRepository repository.CommentRepository,
+	commentClassRepository repository.CommentClassRepository,
 	documentRepository repository.DocumentRepository,
 	disciplineListDocumentRepository repository.DisciplineListDocumentRepository,
 	userRepository repository.UserRepository,
+	delegationRepository repository.DelegationRepository,
+	notificationService NotificationService,
 	db *gorm.DB) CommentService {
+	threshold := defaultCommentSimilarityThreshold
+	if v, err := strconv.ParseFloat(os.Getenv("COMMENT_SIMILARITY_THRESHOLD"), 64); err == nil && v > 0 && v <= 1 {
+		threshold = v
+	}
+
+	maxThreadDepth := defaultCommentMaxThreadDepth
+	if v, err := strconv.Atoi(os.Getenv("COMMENT_MAX_THREAD_DEPTH")); err == nil && v > 0 {
+		maxThreadDepth = v
+	}
+
 	return &commentService{
 		commentRepository:                commentRepository,
+		commentClassRepository:           commentClassRepository,
 		documentRepository:               documentRepository,
 		disciplineListDocumentRepository: disciplineListDocumentRepository,
 		userRepository:                   userRepository,
+		delegationRepository:             delegationRepository,
+		notificationService:              notificationService,
 		db:                               db,
+		similarityThreshold:              threshold,
+		maxThreadDepth:                   maxThreadDepth,
 	}
 }
 
 func (s *commentService) Create(ctx context.Context, req dto.CommentRequest) (dto.CommentResponse, error) {
-	disciplineListDocument, _, err := s.checkPackagePermission(ctx, req.DisciplineListDocumentId, req.UserId)
+	disciplineListDocument, user, err := s.checkPackagePermission(ctx, req.DisciplineListDocumentId, req.UserId)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	onBehalfOfId, err := resolveOnBehalfOf(ctx, s.delegationRepository, user.ID, req.OnBehalfOfId)
 	if err != nil {
 		return dto.CommentResponse{}, err
 	}
@@ -71,7 +127,16 @@ func (s *commentService) Create(ctx context.Context, req dto.CommentRequest) (dt
 		)
 	}
 
-	commentResult, err := s.commentRepository.Create(ctx, nil, entity.Comment{
+	if err := validateCommentAnchor(req.Anchor, disciplineListDocument.Document); err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	visibility, err := commentVisibility(user, nil, req.Visibility)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	comment := entity.Comment{
 		Section:                  req.Section,
 		Comment:                  req.Comment,
 		Baseline:                 req.Baseline,
@@ -79,6 +144,60 @@ func (s *commentService) Create(ctx context.Context, req dto.CommentRequest) (dt
 		IsCloseOutComment:        req.IsCloseOutComment,
 		AttachFileUrl:            req.AttachFileUrl,
 		UserID:                   uuid.MustParse(req.UserId),
+		OnBehalfOfID:             onBehalfOfId,
+		Visibility:               visibility,
+	}
+	comment.SetAnchor(req.Anchor)
+
+	if err := s.classify(ctx, &comment, disciplineListDocument.PackageID, &req.CategoryId, &req.SeverityId); err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	// reviewers write drafts, a consolidator's own comments are official
+	// right away
+	consolidator, err := s.isConsolidator(ctx, user, req.DisciplineListDocumentId)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	comment.Stage = entity.CommentStageDraft
+	if consolidator {
+		now := time.Now()
+		comment.Stage = entity.CommentStageOfficial
+		comment.ConsolidatedByID = &user.ID
+		comment.ConsolidatedAt = &now
+	}
+
+	mentioned, err := s.mentionedUsers(ctx, disciplineListDocument, comment, visibility, req.Mentions)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+	comment.Mentions = commentMentions(mentioned)
+
+	// flag the comment when another reviewer already raised the same point,
+	// the consolidator decides whether to merge them. The similar comments
+	// come back to the writer so they stay within what they may read.
+	open, err := s.commentRepository.GetOpenByDisciplineListDocumentID(ctx, nil, disciplineListDocument.ID.String(), commentScope(user), "User")
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	similar := s.similarComments(comment.Comment, open)
+	if len(similar) > 0 {
+		duplicateOfId := uuid.MustParse(similar[0].ID)
+		comment.DuplicateOfID = &duplicateOfId
+		comment.SimilarityScore = &similar[0].Score
+	}
+
+	var commentResult entity.Comment
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		commentResult, err = s.commentRepository.Create(ctx, nil, comment)
+		if err != nil {
+			return err
+		}
+
+		s.notifyMentions(ctx, user, disciplineListDocument, commentResult, mentioned)
+		return nil
 	})
 	if err != nil {
 		return dto.CommentResponse{}, err
@@ -90,9 +209,18 @@ func (s *commentService) Create(ctx context.Context, req dto.CommentRequest) (dt
 		Comment:               commentResult.Comment,
 		Baseline:              commentResult.Baseline,
 		Status:                (*string)(commentResult.Status),
+		Visibility:            string(commentResult.Visibility),
+		Category:              commentClassCode(commentResult.Category),
+		Severity:              commentClassCode(commentResult.Severity),
+		Stage:                 string(commentResult.Stage),
 		AttachFileUrl:         commentResult.AttachFileUrl,
+		Anchor:                commentResult.ToAnchor(),
 		CommentAt:             commentResult.CreatedAt.Format("15.04 • 02 Jan 2006"),
 		CompanyDocumentNumber: disciplineListDocument.Document.CompanyDocumentNumber,
+		DuplicateOfID:         uuidString(commentResult.DuplicateOfID),
+		SimilarityScore:       commentResult.SimilarityScore,
+		SimilarComments:       similar,
+		Mentions:              userComments(mentioned),
 	}, nil
 }
 
@@ -102,15 +230,51 @@ func (s *commentService) Reply(ctx context.Context, req dto.CommentRequest) (dto
 		return dto.CommentResponse{}, err
 	}
 
+	onBehalfOfId, err := resolveOnBehalfOf(ctx, s.delegationRepository, user.ID, req.OnBehalfOfId)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
 	commentReplied, err := s.commentRepository.GetByID(ctx, nil, req.ReplyId)
 	if err != nil {
 		return dto.CommentResponse{}, err
 	}
 
-	if commentReplied.Status != nil {
+	thread, err := s.threadOf(ctx, commentReplied)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	if thread.Status != nil {
 		return dto.CommentResponse{}, myerror.New("this comment already has a status", http.StatusUnauthorized)
 	}
 
+	if commentReplied.Depth >= s.maxThreadDepth {
+		return dto.CommentResponse{}, myerror.New(fmt.Sprintf("replies can't be nested more than %d levels deep", s.maxThreadDepth), http.StatusBadRequest)
+	}
+
+	if !visibleTo(user, thread, commentReplied) {
+		return dto.CommentResponse{}, myerror.New("comment not found", http.StatusNotFound)
+	}
+
+	if req.IsCloseOutComment && thread.Stage != entity.CommentStageOfficial {
+		return dto.CommentResponse{}, myerror.New("only official comments can be closed out", http.StatusBadRequest)
+	}
+
+	visibility, err := commentVisibility(user, &thread, req.Visibility)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	if req.IsCloseOutComment && visibility == entity.CommentVisibilityInternal {
+		return dto.CommentResponse{}, myerror.New("close out comments can't be internal", http.StatusBadRequest)
+	}
+
+	mentioned, err := s.mentionedUsers(ctx, disciplineListDocument, thread, visibility, req.Mentions)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
 	if disciplineListDocument.Document == nil {
 		return dto.CommentResponse{}, myerror.New("document not found", http.StatusNotFound)
 	}
@@ -124,20 +288,18 @@ func (s *commentService) Reply(ctx context.Context, req dto.CommentRequest) (dto
 		)
 	}
 
-	if req.IsCloseOutComment {
-		cs := entity.CommentStatusReject
-		commentReplied.Status = &cs
-		if err = s.commentRepository.Update(ctx, nil, commentReplied); err != nil {
-			return dto.CommentResponse{}, err
-		}
+	if err := validateCommentAnchor(req.Anchor, disciplineListDocument.Document); err != nil {
+		return dto.CommentResponse{}, err
 	}
 
+	// the reply hangs under the top level comment, nested replies also keep
+	// the reply they answer
+	var parentReplyId *uuid.UUID
 	if commentReplied.CommentReplyID != nil {
-		req.ReplyId = commentReplied.CommentReplyID.String()
+		parentReplyId = &commentReplied.ID
 	}
 
-	replyId := commentReplied.ID
-	commentResult, err := s.commentRepository.Create(ctx, nil, entity.Comment{
+	reply := entity.Comment{
 		Section:                  req.Section,
 		Comment:                  req.Comment,
 		Baseline:                 req.Baseline,
@@ -145,7 +307,64 @@ func (s *commentService) Reply(ctx context.Context, req dto.CommentRequest) (dto
 		IsCloseOutComment:        req.IsCloseOutComment,
 		DisciplineListDocumentID: disciplineListDocument.ID,
 		AttachFileUrl:            req.AttachFileUrl,
-		CommentReplyID:           &replyId,
+		CommentReplyID:           &thread.ID,
+		ParentReplyID:            parentReplyId,
+		Depth:                    commentReplied.Depth + 1,
+		OnBehalfOfID:             onBehalfOfId,
+		Visibility:               visibility,
+		Mentions:                 commentMentions(mentioned),
+	}
+	reply.SetAnchor(req.Anchor)
+
+	// the author of the comment replied to hears about the reply, unless they
+	// wrote it or can't read it
+	author := commentReplied.UserID
+	if commentReplied.OnBehalfOfID != nil {
+		author = *commentReplied.OnBehalfOfID
+	}
+
+	notifyAuthor := author != user.ID && (onBehalfOfId == nil || author != *onBehalfOfId)
+	if notifyAuthor {
+		recipient, err := s.userRepository.GetById(ctx, nil, author.String())
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.CommentResponse{}, err
+		}
+
+		notifyAuthor = err == nil && visibleTo(recipient, thread, reply)
+	}
+
+	// closing the comment and saving the close out reply go together
+	var commentResult entity.Comment
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if req.IsCloseOutComment {
+			cs := entity.CommentStatusReject
+			thread.Status = &cs
+			if err := s.commentRepository.Update(ctx, nil, thread); err != nil {
+				return err
+			}
+		}
+
+		commentResult, err = s.commentRepository.Create(ctx, nil, reply)
+		if err != nil {
+			return err
+		}
+
+		s.notifyMentions(ctx, user, disciplineListDocument, commentResult, mentioned)
+
+		if !notifyAuthor {
+			return nil
+		}
+
+		notifyQuietly(ctx, s.db, func(ctx context.Context) error {
+			return s.notificationService.Notify(ctx, []uuid.UUID{author}, entity.Notification{
+				Type:                     entity.NotificationCommentReplied,
+				Title:                    "New reply",
+				Message:                  fmt.Sprintf("%s replied to your comment on %s", user.Name, disciplineListDocument.Document.CompanyDocumentNumber),
+				DisciplineListDocumentID: &disciplineListDocument.ID,
+				CommentID:                &commentReplied.ID,
+			})
+		})
+		return nil
 	})
 	if err != nil {
 		return dto.CommentResponse{}, err
@@ -157,35 +376,66 @@ func (s *commentService) Reply(ctx context.Context, req dto.CommentRequest) (dto
 		Comment:               commentResult.Comment,
 		Baseline:              commentResult.Baseline,
 		Status:                (*string)(commentResult.Status),
+		Visibility:            string(commentResult.Visibility),
+		ParentReplyID:         uuidString(commentResult.ParentReplyID),
+		Depth:                 commentResult.Depth,
 		AttachFileUrl:         commentResult.AttachFileUrl,
+		Anchor:                commentResult.ToAnchor(),
 		CommentAt:             commentResult.CreatedAt.Format("15.04 • 02 Jan 2006"),
 		CompanyDocumentNumber: disciplineListDocument.Document.CompanyDocumentNumber,
+		Mentions:              userComments(mentioned),
 	}, nil
 }
 
-func (s *commentService) GetById(ctx context.Context, id string) (dto.CommentResponse, error) {
-	comment, err := s.commentRepository.GetByID(ctx, nil, id, "User", "DisciplineListDocument.Document")
+func (s *commentService) GetById(ctx context.Context, userId, id string) (dto.CommentResponse, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	comment, err := s.commentRepository.GetByID(ctx, nil, id, "User", "OnBehalfOf", "ConsolidatedBy", "DisciplineListDocument.Document", "Mentions.User", "Reactions.User", "Category", "Severity",
+		"CommentReplies.User", "CommentReplies.OnBehalfOf", "CommentReplies.Mentions.User", "CommentReplies.Reactions.User")
 	if err != nil {
 		return dto.CommentResponse{}, err
 	}
 
+	thread, err := s.threadOf(ctx, comment)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	if !visibleTo(user, thread, comment) {
+		return dto.CommentResponse{}, myerror.New("comment not found", http.StatusNotFound)
+	}
+
 	var replies []dto.CommentResponse
 	if len(comment.CommentReplies) > 0 {
 		for _, reply := range comment.CommentReplies {
+			if !visibleTo(user, reply) {
+				continue
+			}
+
 			replies = append(replies, dto.CommentResponse{
 				ID:                    reply.ID.String(),
 				Section:               reply.Section,
 				Comment:               reply.Comment,
 				Baseline:              reply.Baseline,
 				Status:                (*string)(reply.Status),
+				Visibility:            string(reply.Visibility),
+				ParentReplyID:         uuidString(reply.ParentReplyID),
+				Depth:                 reply.Depth,
+				Anchor:                reply.ToAnchor(),
 				CommentAt:             reply.CreatedAt.Format("15.04 • 02 Jan 2006"),
 				CompanyDocumentNumber: comment.DisciplineListDocument.Document.CompanyDocumentNumber,
 				UserComment: &dto.UserComment{
-					ID:           comment.User.ID.String(),
-					Name:         comment.User.Name,
-					PhotoProfile: comment.User.PhotoProfile,
-					Role:         string(comment.User.Role),
+					ID:           reply.User.ID.String(),
+					Name:         reply.User.Name,
+					PhotoProfile: reply.User.PhotoProfile,
+					Role:         string(reply.User.Role),
 				},
+				OnBehalfOf: onBehalfOf(reply),
+				Mentions:   mentions(reply),
+				Reactions:  reactions(reply),
 			})
 		}
 	}
@@ -196,26 +446,39 @@ func (s *commentService) GetById(ctx context.Context, id string) (dto.CommentRes
 		Comment:               comment.Comment,
 		Baseline:              comment.Baseline,
 		Status:                (*string)(comment.Status),
+		Visibility:            string(comment.Visibility),
+		Category:              commentClassCode(comment.Category),
+		Severity:              commentClassCode(comment.Severity),
+		Stage:                 string(comment.Stage),
 		DocumentID:            comment.DisciplineListDocument.Document.ID.String(),
 		CommentAt:             comment.CreatedAt.Format("15.04 • 02 Jan 2006"),
 		CompanyDocumentNumber: comment.DisciplineListDocument.Document.CompanyDocumentNumber,
 		AttachFileUrl:         comment.AttachFileUrl,
+		Anchor:                comment.ToAnchor(),
 		UserComment: &dto.UserComment{
 			Name:         comment.User.Name,
 			PhotoProfile: comment.User.PhotoProfile,
 			Role:         string(comment.User.Role),
 		},
+		ParentReplyID:  uuidString(comment.ParentReplyID),
+		Depth:          comment.Depth,
+		OnBehalfOf:     onBehalfOf(comment),
+		ConsolidatedBy: consolidatedBy(comment),
+		Mentions:       mentions(comment),
+		Reactions:      reactions(comment),
 		CommentReplies: replies,
 	}, nil
 }
 
 func (s *commentService) GetAllByDisciplineListDocumentId(ctx context.Context, userId, disciplineListDocumentId string, metaReq meta.Meta) ([]dto.CommentResponse, meta.Meta, error) {
-	disciplineListDocument, _, err := s.checkPackagePermission(ctx, disciplineListDocumentId, userId)
+	disciplineListDocument, user, err := s.checkPackagePermission(ctx, disciplineListDocumentId, userId)
 	if err != nil {
 		return nil, meta.Meta{}, err
 	}
 
-	comments, metaRes, err := s.commentRepository.GetAllByDisciplineListDocumentID(ctx, nil, disciplineListDocumentId, metaReq, "User", "CommentReplies.User", "CommentReplies")
+	preloads := append([]string{"User", "OnBehalfOf", "ConsolidatedBy", "CommentReplies.User", "CommentReplies.OnBehalfOf", "CommentReplies",
+		"Mentions.User", "Reactions.User", "CommentReplies.Mentions.User", "CommentReplies.Reactions.User", "Category", "Severity"}, mergedCommentsPreload(user)...)
+	comments, metaRes, err := s.commentRepository.GetAllByDisciplineListDocumentID(ctx, nil, disciplineListDocumentId, commentScope(user), metaReq, preloads...)
 	if err != nil {
 		return nil, meta.Meta{}, err
 	}
@@ -231,10 +494,14 @@ func (s *commentService) GetAllByDisciplineListDocumentId(ctx context.Context, u
 					Comment:               reply.Comment,
 					Baseline:              reply.Baseline,
 					Status:                (*string)(reply.Status),
+					Visibility:            string(reply.Visibility),
+					ParentReplyID:         uuidString(reply.ParentReplyID),
+					Depth:                 reply.Depth,
 					CommentAt:             reply.CreatedAt.Format("15.04 • 02 Jan 2006"),
 					DocumentID:            disciplineListDocument.Document.ID.String(),
 					IsCloseOutComment:     reply.IsCloseOutComment,
 					AttachFileUrl:         reply.AttachFileUrl,
+					Anchor:                reply.ToAnchor(),
 					CompanyDocumentNumber: disciplineListDocument.Document.CompanyDocumentNumber,
 					UserComment: &dto.UserComment{
 						ID:           reply.User.ID.String(),
@@ -242,6 +509,9 @@ func (s *commentService) GetAllByDisciplineListDocumentId(ctx context.Context, u
 						PhotoProfile: reply.User.PhotoProfile,
 						Role:         string(reply.User.Role),
 					},
+					OnBehalfOf: onBehalfOf(reply),
+					Mentions:   mentions(reply),
+					Reactions:  reactions(reply),
 				})
 			}
 		}
@@ -252,10 +522,15 @@ func (s *commentService) GetAllByDisciplineListDocumentId(ctx context.Context, u
 			Comment:               comment.Comment,
 			Baseline:              comment.Baseline,
 			Status:                (*string)(comment.Status),
+			Visibility:            string(comment.Visibility),
+			Category:              commentClassCode(comment.Category),
+			Severity:              commentClassCode(comment.Severity),
+			Stage:                 string(comment.Stage),
 			CommentAt:             comment.CreatedAt.Format("15.04 • 02 Jan 2006"),
 			DocumentID:            disciplineListDocument.Document.ID.String(),
 			IsCloseOutComment:     comment.IsCloseOutComment,
 			AttachFileUrl:         comment.AttachFileUrl,
+			Anchor:                comment.ToAnchor(),
 			CompanyDocumentNumber: disciplineListDocument.Document.CompanyDocumentNumber,
 			UserComment: &dto.UserComment{
 				ID:           comment.User.ID.String(),
@@ -263,7 +538,14 @@ func (s *commentService) GetAllByDisciplineListDocumentId(ctx context.Context, u
 				PhotoProfile: comment.User.PhotoProfile,
 				Role:         string(comment.User.Role),
 			},
-			CommentReplies: replies,
+			OnBehalfOf:      onBehalfOf(comment),
+			ConsolidatedBy:  consolidatedBy(comment),
+			DuplicateOfID:   uuidString(comment.DuplicateOfID),
+			SimilarityScore: comment.SimilarityScore,
+			MergedComments:  mergedComments(comment),
+			Mentions:        mentions(comment),
+			Reactions:       reactions(comment),
+			CommentReplies:  replies,
 		})
 	}
 
@@ -271,12 +553,23 @@ func (s *commentService) GetAllByDisciplineListDocumentId(ctx context.Context, u
 }
 
 func (s *commentService) GetAllByReplyId(ctx context.Context, userId, disciplineListDocumentId, replyId string, metaReq meta.Meta) ([]dto.CommentResponse, meta.Meta, error) {
-	_, _, err := s.checkPackagePermission(ctx, disciplineListDocumentId, userId)
+	_, user, err := s.checkPackagePermission(ctx, disciplineListDocumentId, userId)
 	if err != nil {
 		return nil, meta.Meta{}, err
 	}
 
-	comments, metaRes, err := s.commentRepository.GetAllByReplyID(ctx, nil, replyId, metaReq, "User")
+	if user.Role == entity.RoleContractor {
+		parent, err := s.commentRepository.GetByID(ctx, nil, replyId)
+		if err != nil {
+			return nil, meta.Meta{}, err
+		}
+
+		if !parent.InOfficialSet() {
+			return nil, meta.Meta{}, myerror.New("comment not found", http.StatusNotFound)
+		}
+	}
+
+	comments, metaRes, err := s.commentRepository.GetAllByReplyID(ctx, nil, replyId, commentScope(user), metaReq, "User", "OnBehalfOf", "Mentions.User", "Reactions.User")
 	if err != nil {
 		return nil, meta.Meta{}, err
 	}
@@ -284,16 +577,23 @@ func (s *commentService) GetAllByReplyId(ctx context.Context, userId, discipline
 	var commentResponse []dto.CommentResponse
 	for _, comment := range comments {
 		commentResponse = append(commentResponse, dto.CommentResponse{
-			ID:        comment.ID.String(),
-			Section:   comment.Section,
-			Comment:   comment.Comment,
-			Baseline:  comment.Baseline,
-			Status:    (*string)(comment.Status),
-			CommentAt: comment.CreatedAt.Format("15.04 • 02 Jan 2006"),
+			ID:            comment.ID.String(),
+			Section:       comment.Section,
+			Comment:       comment.Comment,
+			Baseline:      comment.Baseline,
+			Status:        (*string)(comment.Status),
+			Visibility:    string(comment.Visibility),
+			ParentReplyID: uuidString(comment.ParentReplyID),
+			Depth:         comment.Depth,
+			Anchor:        comment.ToAnchor(),
+			CommentAt:     comment.CreatedAt.Format("15.04 • 02 Jan 2006"),
 			UserComment: &dto.UserComment{
 				Name: comment.User.Name,
 				Role: string(comment.User.Role),
 			},
+			OnBehalfOf: onBehalfOf(comment),
+			Mentions:   mentions(comment),
+			Reactions:  reactions(comment),
 		})
 	}
 
@@ -301,7 +601,7 @@ func (s *commentService) GetAllByReplyId(ctx context.Context, userId, discipline
 }
 
 func (s *commentService) Update(ctx context.Context, req dto.UpdateCommentRequest) error {
-	_, user, err := s.checkPackagePermission(ctx, req.DisciplineListDocumentId, req.UserId)
+	disciplineListDocument, user, err := s.checkPackagePermission(ctx, req.DisciplineListDocumentId, req.UserId)
 	if err != nil {
 		return err
 	}
@@ -311,7 +611,12 @@ func (s *commentService) Update(ctx context.Context, req dto.UpdateCommentReques
 		return err
 	}
 
-	if user.PackageID != nil && comment.UserID != user.ID {
+	allowed, err := s.canModify(ctx, user, comment)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
 		return myerror.New("you dont have permission in this comment", http.StatusUnauthorized)
 	}
 
@@ -323,18 +628,140 @@ func (s *commentService) Update(ctx context.Context, req dto.UpdateCommentReques
 		return myerror.New("this comment already has a status", http.StatusUnauthorized)
 	}
 
+	if comment.CommentReplyID == nil {
+		consolidator, err := s.isConsolidator(ctx, user, req.DisciplineListDocumentId)
+		if err != nil {
+			return err
+		}
+
+		if !consolidator && comment.Stage != entity.CommentStageDraft {
+			return myerror.New("this comment is already consolidated", http.StatusUnauthorized)
+		}
+
+		if req.Status != nil && comment.Stage != entity.CommentStageOfficial {
+			return myerror.New("only official comments can get a status", http.StatusBadRequest)
+		}
+	}
+
+	if req.Anchor != nil && req.ClearAnchor {
+		return myerror.New("anchor and clear_anchor can't be sent together", http.StatusBadRequest)
+	}
+
+	if err := validateCommentAnchor(req.Anchor, disciplineListDocument.Document); err != nil {
+		return err
+	}
+
+	// classes left out keep their current value
+	if comment.CommentReplyID == nil {
+		if err := s.classify(ctx, &comment, disciplineListDocument.PackageID,
+			sentClass(req.CategoryId), sentClass(req.SeverityId)); err != nil {
+			return err
+		}
+	}
+
+	thread, err := s.threadOf(ctx, comment)
+	if err != nil {
+		return err
+	}
+
+	if req.Visibility != "" {
+		var parent *entity.Comment
+		if comment.CommentReplyID != nil {
+			parent = &thread
+		}
+
+		visibility, err := commentVisibility(user, parent, req.Visibility)
+		if err != nil {
+			return err
+		}
+
+		if comment.IsCloseOutComment && visibility == entity.CommentVisibilityInternal {
+			return myerror.New("close out comments can't be internal", http.StatusBadRequest)
+		}
+
+		comment.Visibility = visibility
+	}
+
+	// only the users that were not mentioned yet get notified
+	var mentioned, newlyMentioned []entity.User
+	if req.Mentions != nil {
+		// a top level comment is its own thread, as updated above
+		if comment.CommentReplyID == nil {
+			thread = comment
+		}
+
+		mentioned, err = s.mentionedUsers(ctx, disciplineListDocument, thread, comment.Visibility, req.Mentions)
+		if err != nil {
+			return err
+		}
+
+		current, err := s.commentRepository.GetByID(ctx, nil, req.ID, "Mentions")
+		if err != nil {
+			return err
+		}
+
+		already := map[uuid.UUID]bool{}
+		for _, mention := range current.Mentions {
+			already[mention.UserID] = true
+		}
+
+		for _, u := range mentioned {
+			if !already[u.ID] {
+				newlyMentioned = append(newlyMentioned, u)
+			}
+		}
+	}
+
+	// the text before the edit is kept in the history of the comment
+	var revision *entity.CommentRevision
+	if comment.Section != req.Section || comment.Comment != req.Comment || comment.Baseline != req.Baseline {
+		revision = &entity.CommentRevision{
+			Section:   comment.Section,
+			Comment:   comment.Comment,
+			Baseline:  comment.Baseline,
+			CommentID: comment.ID,
+			EditedBy:  user.ID,
+		}
+	}
+
 	comment.Comment = req.Comment
 	comment.Baseline = req.Baseline
 	comment.Section = req.Section
 	comment.Status = (*entity.CommentStatus)(req.Status)
 	comment.AttachFileUrl = req.AttachFileUrl
+	// the anchor left out stays where it is, clear_anchor removes it
+	if req.Anchor != nil || req.ClearAnchor {
+		comment.SetAnchor(req.Anchor)
+	}
 	comment.UpdatedBy = uuid.MustParse(req.UserId)
 
-	if err = s.commentRepository.Update(ctx, nil, comment); err != nil {
-		return err
-	}
+	return repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if revision != nil {
+			if err := s.commentRepository.CreateRevision(ctx, nil, *revision); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		if err := s.commentRepository.Update(ctx, nil, comment); err != nil {
+			return err
+		}
+
+		if req.Mentions == nil {
+			return nil
+		}
+
+		userIds := make([]uuid.UUID, len(mentioned))
+		for i, u := range mentioned {
+			userIds[i] = u.ID
+		}
+
+		if err := s.commentRepository.ReplaceMentions(ctx, nil, comment.ID, userIds); err != nil {
+			return err
+		}
+
+		s.notifyMentions(ctx, user, disciplineListDocument, comment, newlyMentioned)
+		return nil
+	})
 }
 
 func (s *commentService) Delete(ctx context.Context, userId, disciplineListDocumentId, commentId string) error {
@@ -348,10 +775,26 @@ func (s *commentService) Delete(ctx context.Context, userId, disciplineListDocum
 		return err
 	}
 
-	if user.PackageID != nil && comment.UserID != user.ID {
+	allowed, err := s.canModify(ctx, user, comment)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
 		return myerror.New("you don't have permission for this comment", http.StatusUnauthorized)
 	}
 
+	if comment.CommentReplyID == nil && comment.Stage != entity.CommentStageDraft {
+		consolidator, err := s.isConsolidator(ctx, user, disciplineListDocumentId)
+		if err != nil {
+			return err
+		}
+
+		if !consolidator {
+			return myerror.New("this comment is already consolidated", http.StatusUnauthorized)
+		}
+	}
+
 	comment.DeletedBy = uuid.MustParse(userId)
 	if err = s.commentRepository.Delete(ctx, nil, comment); err != nil {
 		return err
@@ -360,20 +803,941 @@ func (s *commentService) Delete(ctx context.Context, userId, disciplineListDocum
 	return nil
 }
 
-func (s *commentService) checkPackagePermission(ctx context.Context, disciplineListDocumentId, userId string) (entity.DisciplineListDocument, entity.User, error) {
-	disciplineListDocument, err := s.disciplineListDocumentRepository.GetByID(ctx, nil, disciplineListDocumentId, "Document")
+// GetDuplicates groups the open comments of the discipline list document
+// that read alike, best matches first. Contractors only get the comments
+// they may list.
+func (s *commentService) GetDuplicates(ctx context.Context, userId, disciplineListDocumentId string) ([]dto.CommentCluster, error) {
+	_, user, err := s.checkPackagePermission(ctx, disciplineListDocumentId, userId)
 	if err != nil {
-		return entity.DisciplineListDocument{}, entity.User{}, err
+		return nil, err
 	}
 
-	user, err := s.userRepository.GetById(ctx, nil, userId)
+	comments, err := s.commentRepository.GetOpenByDisciplineListDocumentID(ctx, nil, disciplineListDocumentId, commentScope(user), "User")
 	if err != nil {
-		return entity.DisciplineListDocument{}, entity.User{}, err
+		return nil, err
 	}
 
-	if user.PackageID != nil && disciplineListDocument.PackageID != *user.PackageID {
-		return entity.DisciplineListDocument{}, entity.User{}, myerror.New("you dont have permission in this package", http.StatusUnauthorized)
+	texts := make([]string, len(comments))
+	for i, comment := range comments {
+		texts[i] = comment.Comment
 	}
 
-	return disciplineListDocument, user, nil
+	groups, scores := similarity.Clusters(texts, s.similarityThreshold)
+
+	clusters := []dto.CommentCluster{}
+	for i, group := range groups {
+		cluster := dto.CommentCluster{Score: roundScore(scores[i])}
+		for _, index := range group {
+			cluster.Comments = append(cluster.Comments, similarComment(comments[index], 0))
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Score > clusters[j].Score
+	})
+
+	return clusters, nil
+}
+
+// Merge folds comments into a target comment, either one of the open
+// comments or a new one written by the consolidator. The merged comments
+// stay linked to the target and drop out of the listing and the CRS.
+func (s *commentService) Merge(ctx context.Context, req dto.MergeCommentRequest) (dto.CommentResponse, error) {
+	disciplineListDocument, user, err := s.checkPackagePermission(ctx, req.DisciplineListDocumentId, req.UserId)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	allowed, err := s.isConsolidator(ctx, user, req.DisciplineListDocumentId)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	if !allowed {
+		return dto.CommentResponse{}, myerror.New("only consolidators of this document can merge comments", http.StatusUnauthorized)
+	}
+
+	var commentIds []string
+	seen := map[string]bool{}
+	for _, id := range req.CommentIDs {
+		if seen[id] || (req.TargetID != nil && id == *req.TargetID) {
+			continue
+		}
+		seen[id] = true
+		commentIds = append(commentIds, id)
+	}
+
+	if len(commentIds) == 0 || (req.TargetID == nil && len(commentIds) < 2) {
+		return dto.CommentResponse{}, myerror.New("select at least two comments to merge", http.StatusBadRequest)
+	}
+
+	checkIds := commentIds
+	if req.TargetID != nil {
+		checkIds = append([]string{*req.TargetID}, commentIds...)
+	}
+
+	comments, err := s.commentRepository.GetByIDs(ctx, nil, checkIds)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	if len(comments) != len(checkIds) {
+		return dto.CommentResponse{}, myerror.New("comment not found", http.StatusNotFound)
+	}
+
+	for _, comment := range comments {
+		if comment.DisciplineListDocumentID != disciplineListDocument.ID {
+			return dto.CommentResponse{}, myerror.New("comments must belong to this document", http.StatusBadRequest)
+		}
+
+		if comment.CommentReplyID != nil || comment.MergedIntoID != nil || comment.Status != nil ||
+			comment.Stage == entity.CommentStageDiscarded {
+			return dto.CommentResponse{}, myerror.New("only open top level comments can be merged", http.StatusBadRequest)
+		}
+
+		if comment.Visibility != comments[0].Visibility {
+			return dto.CommentResponse{}, myerror.New("internal and public comments can't be merged together", http.StatusBadRequest)
+		}
+	}
+
+	var target entity.Comment
+	if req.TargetID == nil {
+		if strings.TrimSpace(req.Comment) == "" {
+			return dto.CommentResponse{}, myerror.New("comment is required when merging into a new comment", http.StatusBadRequest)
+		}
+
+		target = entity.Comment{
+			Section:                  req.Section,
+			Comment:                  req.Comment,
+			Baseline:                 req.Baseline,
+			DisciplineListDocumentID: disciplineListDocument.ID,
+			UserID:                   user.ID,
+			Visibility:               comments[0].Visibility,
+			CategoryID:               comments[0].CategoryID,
+			SeverityID:               comments[0].SeverityID,
+		}
+	}
+
+	// the merged comment is what goes into the CRS
+	now := time.Now()
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if req.TargetID == nil {
+			target.Stage = entity.CommentStageOfficial
+			target.ConsolidatedByID = &user.ID
+			target.ConsolidatedAt = &now
+			if target, err = s.commentRepository.Create(ctx, nil, target); err != nil {
+				return err
+			}
+		} else {
+			for _, comment := range comments {
+				if comment.ID.String() == *req.TargetID {
+					target = comment
+				}
+			}
+
+			target.Stage = entity.CommentStageOfficial
+			target.ConsolidatedByID = &user.ID
+			target.ConsolidatedAt = &now
+			if err := s.commentRepository.Update(ctx, nil, target); err != nil {
+				return err
+			}
+		}
+
+		if err := s.commentRepository.MergeInto(ctx, nil, commentIds, target.ID, user.ID); err != nil {
+			return err
+		}
+
+		var authors []uuid.UUID
+		for _, comment := range comments {
+			author := comment.UserID
+			if comment.OnBehalfOfID != nil {
+				author = *comment.OnBehalfOfID
+			}
+
+			if comment.ID != target.ID && author != user.ID {
+				authors = append(authors, author)
+			}
+		}
+
+		notifyQuietly(ctx, s.db, func(ctx context.Context) error {
+			return s.notificationService.Notify(ctx, authors, entity.Notification{
+				Type:                     entity.NotificationCommentMerged,
+				Title:                    "Comment merged",
+				Message:                  fmt.Sprintf("%s merged your comment on %s with similar ones", user.Name, disciplineListDocument.Document.CompanyDocumentNumber),
+				DisciplineListDocumentID: &disciplineListDocument.ID,
+				CommentID:                &target.ID,
+			})
+		})
+		return nil
+	})
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	preloads := append([]string{"User", "OnBehalfOf", "ConsolidatedBy", "Category", "Severity"}, mergedCommentsPreload(user)...)
+	target, err = s.commentRepository.GetByID(ctx, nil, target.ID.String(), preloads...)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	return dto.CommentResponse{
+		ID:                    target.ID.String(),
+		Section:               target.Section,
+		Comment:               target.Comment,
+		Baseline:              target.Baseline,
+		Status:                (*string)(target.Status),
+		Visibility:            string(target.Visibility),
+		Category:              commentClassCode(target.Category),
+		Severity:              commentClassCode(target.Severity),
+		Stage:                 string(target.Stage),
+		DocumentID:            disciplineListDocument.DocumentID.String(),
+		CommentAt:             target.CreatedAt.Format("15.04 • 02 Jan 2006"),
+		CompanyDocumentNumber: disciplineListDocument.Document.CompanyDocumentNumber,
+		AttachFileUrl:         target.AttachFileUrl,
+		Anchor:                target.ToAnchor(),
+		UserComment:           userComment(target.User),
+		OnBehalfOf:            onBehalfOf(target),
+		ConsolidatedBy:        consolidatedBy(target),
+		MergedComments:        mergedComments(target),
+	}, nil
+}
+
+// Consolidate accepts, discards or sends back a reviewer's comment. Only
+// comments accepted here or through a merge reach the contractor and the CRS.
+func (s *commentService) Consolidate(ctx context.Context, req dto.ConsolidateCommentRequest) (dto.CommentResponse, error) {
+	disciplineListDocument, user, err := s.checkPackagePermission(ctx, req.DisciplineListDocumentId, req.UserId)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	allowed, err := s.isConsolidator(ctx, user, req.DisciplineListDocumentId)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	if !allowed {
+		return dto.CommentResponse{}, myerror.New("only consolidators of this document can consolidate comments", http.StatusUnauthorized)
+	}
+
+	comment, err := s.commentRepository.GetByID(ctx, nil, req.ID)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	if comment.DisciplineListDocumentID != disciplineListDocument.ID {
+		return dto.CommentResponse{}, myerror.New("comment not found", http.StatusNotFound)
+	}
+
+	if comment.CommentReplyID != nil || comment.MergedIntoID != nil {
+		return dto.CommentResponse{}, myerror.New("only top level comments can be consolidated", http.StatusBadRequest)
+	}
+
+	if comment.Status != nil {
+		return dto.CommentResponse{}, myerror.New("this comment already has a status", http.StatusUnauthorized)
+	}
+
+	var title, verb string
+	switch req.Action {
+	case dto.ConsolidateDiscard:
+		comment.Stage, title, verb = entity.CommentStageDiscarded, "Comment discarded", "discarded"
+	case dto.ConsolidateDraft:
+		comment.Stage, title, verb = entity.CommentStageDraft, "Comment sent back", "sent back"
+	case dto.ConsolidateAccept:
+		if req.Section != nil {
+			comment.Section = *req.Section
+		}
+		if req.Comment != nil {
+			if strings.TrimSpace(*req.Comment) == "" {
+				return dto.CommentResponse{}, myerror.New("comment can't be empty", http.StatusBadRequest)
+			}
+			comment.Comment = *req.Comment
+		}
+		if req.Baseline != nil {
+			comment.Baseline = *req.Baseline
+		}
+
+		if err := s.classify(ctx, &comment, disciplineListDocument.PackageID, req.CategoryId, req.SeverityId); err != nil {
+			return dto.CommentResponse{}, err
+		}
+		comment.Stage, title, verb = entity.CommentStageOfficial, "Comment accepted", "accepted"
+	default:
+		return dto.CommentResponse{}, myerror.New(fmt.Sprintf("unknown consolidation action %q", req.Action), http.StatusBadRequest)
+	}
+
+	now := time.Now()
+	comment.ConsolidatedByID = &user.ID
+	comment.ConsolidatedAt = &now
+	comment.UpdatedBy = user.ID
+
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if err := s.commentRepository.Update(ctx, nil, comment); err != nil {
+			return err
+		}
+
+		author := comment.UserID
+		if comment.OnBehalfOfID != nil {
+			author = *comment.OnBehalfOfID
+		}
+
+		if author == user.ID {
+			return nil
+		}
+
+		notifyQuietly(ctx, s.db, func(ctx context.Context) error {
+			return s.notificationService.Notify(ctx, []uuid.UUID{author}, entity.Notification{
+				Type:                     entity.NotificationCommentConsolidated,
+				Title:                    title,
+				Message:                  fmt.Sprintf("%s %s your comment on %s", user.Name, verb, disciplineListDocument.Document.CompanyDocumentNumber),
+				DisciplineListDocumentID: &disciplineListDocument.ID,
+				CommentID:                &comment.ID,
+			})
+		})
+		return nil
+	})
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	comment, err = s.commentRepository.GetByID(ctx, nil, comment.ID.String(), "User", "OnBehalfOf", "ConsolidatedBy", "Category", "Severity")
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	return dto.CommentResponse{
+		ID:                    comment.ID.String(),
+		Section:               comment.Section,
+		Comment:               comment.Comment,
+		Baseline:              comment.Baseline,
+		Status:                (*string)(comment.Status),
+		Visibility:            string(comment.Visibility),
+		Category:              commentClassCode(comment.Category),
+		Severity:              commentClassCode(comment.Severity),
+		Stage:                 string(comment.Stage),
+		DocumentID:            disciplineListDocument.DocumentID.String(),
+		CommentAt:             comment.CreatedAt.Format("15.04 • 02 Jan 2006"),
+		CompanyDocumentNumber: disciplineListDocument.Document.CompanyDocumentNumber,
+		AttachFileUrl:         comment.AttachFileUrl,
+		Anchor:                comment.ToAnchor(),
+		UserComment:           userComment(comment.User),
+		OnBehalfOf:            onBehalfOf(comment),
+		ConsolidatedBy:        consolidatedBy(comment),
+	}, nil
+}
+
+// React acknowledges a comment or reply, giving the same reaction twice
+// keeps one
+func (s *commentService) React(ctx context.Context, req dto.CommentReactionRequest) ([]dto.CommentReaction, error) {
+	comment, user, err := s.reactable(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepository.AddReaction(ctx, nil, entity.CommentReaction{
+		Type:      entity.CommentReactionType(req.Type),
+		CommentID: comment.ID,
+		UserID:    user.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.reactionsOf(ctx, comment.ID.String())
+}
+
+func (s *commentService) Unreact(ctx context.Context, req dto.CommentReactionRequest) ([]dto.CommentReaction, error) {
+	valid := false
+	for _, reactionType := range entity.CommentReactionTypes {
+		valid = valid || string(reactionType) == req.Type
+	}
+
+	if !valid {
+		return nil, myerror.New("reaction type must be SEEN or AGREE", http.StatusBadRequest)
+	}
+
+	comment, user, err := s.reactable(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepository.RemoveReaction(ctx, nil, comment.ID.String(), user.ID.String(), entity.CommentReactionType(req.Type)); err != nil {
+		return nil, err
+	}
+
+	return s.reactionsOf(ctx, comment.ID.String())
+}
+
+// GetRevisions lists the earlier versions of a comment or reply, latest first
+func (s *commentService) GetRevisions(ctx context.Context, userId, disciplineListDocumentId, commentId string) ([]dto.CommentRevisionResponse, error) {
+	disciplineListDocument, user, err := s.checkPackagePermission(ctx, disciplineListDocumentId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.visibleComment(ctx, user, disciplineListDocument, commentId); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.commentRepository.GetRevisions(ctx, nil, commentId, "Editor")
+	if err != nil {
+		return nil, err
+	}
+
+	res := []dto.CommentRevisionResponse{}
+	for _, revision := range revisions {
+		item := dto.CommentRevisionResponse{
+			ID:       revision.ID.String(),
+			Section:  revision.Section,
+			Comment:  revision.Comment,
+			Baseline: revision.Baseline,
+			EditedAt: revision.CreatedAt.Format("15.04 • 02 Jan 2006"),
+		}
+
+		if revision.Editor != nil {
+			item.EditedBy = userComment(revision.Editor)
+		}
+
+		res = append(res, item)
+	}
+
+	return res, nil
+}
+
+func (s *commentService) reactable(ctx context.Context, req dto.CommentReactionRequest) (entity.Comment, entity.User, error) {
+	disciplineListDocument, user, err := s.checkPackagePermission(ctx, req.DisciplineListDocumentId, req.UserId)
+	if err != nil {
+		return entity.Comment{}, entity.User{}, err
+	}
+
+	comment, err := s.visibleComment(ctx, user, disciplineListDocument, req.ID)
+	if err != nil {
+		return entity.Comment{}, entity.User{}, err
+	}
+
+	return comment, user, nil
+}
+
+func (s *commentService) reactionsOf(ctx context.Context, commentId string) ([]dto.CommentReaction, error) {
+	comment, err := s.commentRepository.GetByID(ctx, nil, commentId, "Reactions.User")
+	if err != nil {
+		return nil, err
+	}
+
+	res := reactions(comment)
+	if res == nil {
+		res = []dto.CommentReaction{}
+	}
+
+	return res, nil
+}
+
+// visibleComment loads a comment of the discipline list document the user
+// may read
+func (s *commentService) visibleComment(ctx context.Context, user entity.User, disciplineListDocument entity.DisciplineListDocument, commentId string) (entity.Comment, error) {
+	comment, err := s.commentRepository.GetByID(ctx, nil, commentId)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	thread, err := s.threadOf(ctx, comment)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	if comment.DisciplineListDocumentID != disciplineListDocument.ID || !visibleTo(user, thread, comment) {
+		return entity.Comment{}, myerror.New("comment not found", http.StatusNotFound)
+	}
+
+	return comment, nil
+}
+
+// mentionedUsers loads the users mentioned in a comment of the thread, they
+// must belong to the package of the document. The contractor can't be
+// mentioned where they would never see it, in an internal comment or in a
+// thread outside the official set.
+func (s *commentService) mentionedUsers(ctx context.Context, disciplineListDocument entity.DisciplineListDocument, thread entity.Comment, visibility entity.CommentVisibility, ids []string) ([]entity.User, error) {
+	var users []entity.User
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		u, err := s.userRepository.GetById(ctx, nil, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, myerror.New("mentioned user not found", http.StatusNotFound)
+			}
+			return nil, err
+		}
+
+		if u.PackageID != nil && *u.PackageID != disciplineListDocument.PackageID {
+			return nil, myerror.New(fmt.Sprintf("%s is not a member of this package", u.Name), http.StatusBadRequest)
+		}
+
+		if u.Role == entity.RoleContractor && visibility == entity.CommentVisibilityInternal {
+			return nil, myerror.New("the contractor can't be mentioned in an internal comment", http.StatusBadRequest)
+		}
+
+		if u.Role == entity.RoleContractor && !thread.InOfficialSet() {
+			return nil, myerror.New("the contractor can't be mentioned before the comment is official", http.StatusBadRequest)
+		}
+
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+// notifyMentions tells the mentioned users, except the writer, about the
+// comment
+func (s *commentService) notifyMentions(ctx context.Context, user entity.User, disciplineListDocument entity.DisciplineListDocument, comment entity.Comment, mentioned []entity.User) {
+	var userIds []uuid.UUID
+	for _, u := range mentioned {
+		if u.ID != user.ID {
+			userIds = append(userIds, u.ID)
+		}
+	}
+
+	if len(userIds) == 0 {
+		return
+	}
+
+	notifyQuietly(ctx, s.db, func(ctx context.Context) error {
+		return s.notificationService.Notify(ctx, userIds, entity.Notification{
+			Type:                     entity.NotificationCommentMentioned,
+			Title:                    "You were mentioned",
+			Message:                  fmt.Sprintf("%s mentioned you in a comment on %s", user.Name, disciplineListDocument.Document.CompanyDocumentNumber),
+			DisciplineListDocumentID: &disciplineListDocument.ID,
+			CommentID:                &comment.ID,
+		})
+	})
+}
+
+// isConsolidator tells whether the user consolidates the discipline list
+// document, or stands in for one of its consolidators
+func (s *commentService) isConsolidator(ctx context.Context, user entity.User, disciplineListDocumentId string) (bool, error) {
+	if user.PackageID == nil {
+		return true, nil
+	}
+
+	disciplineListDocument, err := s.disciplineListDocumentRepository.GetByID(ctx, nil, disciplineListDocumentId, "Consolidators.DisciplineGroupConsolidator")
+	if err != nil {
+		return false, err
+	}
+
+	for _, consolidator := range disciplineListDocument.Consolidators {
+		if consolidator.DisciplineGroupConsolidator == nil {
+			continue
+		}
+
+		ok, err := actsFor(ctx, s.delegationRepository, user.ID, consolidator.DisciplineGroupConsolidator.UserID)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+// similarComments scores the text against the comments, best first
+func (s *commentService) similarComments(text string, comments []entity.Comment) []dto.SimilarComment {
+	trigrams := similarity.Trigrams(text)
+
+	var similar []dto.SimilarComment
+	for _, comment := range comments {
+		score := similarity.Score(trigrams, similarity.Trigrams(comment.Comment))
+		if score >= s.similarityThreshold {
+			similar = append(similar, similarComment(comment, score))
+		}
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		return similar[i].Score > similar[j].Score
+	})
+
+	if len(similar) > maxSimilarComments {
+		similar = similar[:maxSimilarComments]
+	}
+
+	return similar
+}
+
+func similarComment(comment entity.Comment, score float64) dto.SimilarComment {
+	res := dto.SimilarComment{
+		ID:      comment.ID.String(),
+		Section: comment.Section,
+		Comment: comment.Comment,
+		Score:   roundScore(score),
+	}
+
+	if comment.User != nil {
+		res.UserComment = userComment(comment.User)
+	}
+
+	return res
+}
+
+func mergedComments(comment entity.Comment) []dto.CommentResponse {
+	var merged []dto.CommentResponse
+	for _, c := range comment.MergedComments {
+		res := dto.CommentResponse{
+			ID:        c.ID.String(),
+			Section:   c.Section,
+			Comment:   c.Comment,
+			Baseline:  c.Baseline,
+			Anchor:    c.ToAnchor(),
+			CommentAt: c.CreatedAt.Format("15.04 • 02 Jan 2006"),
+		}
+
+		if c.User != nil {
+			res.UserComment = userComment(c.User)
+		}
+
+		merged = append(merged, res)
+	}
+
+	return merged
+}
+
+// mergedCommentsPreload loads the comments merged into a consolidated one,
+// they keep the reviewers' own wording so contractors only get the
+// consolidated text
+func mergedCommentsPreload(user entity.User) []string {
+	if user.Role == entity.RoleContractor {
+		return nil
+	}
+
+	return []string{"MergedComments.User"}
+}
+
+func commentMentions(users []entity.User) []entity.CommentMention {
+	var res []entity.CommentMention
+	for _, u := range users {
+		res = append(res, entity.CommentMention{UserID: u.ID})
+	}
+
+	return res
+}
+
+func userComments(users []entity.User) []dto.UserComment {
+	var res []dto.UserComment
+	for i := range users {
+		res = append(res, *userComment(&users[i]))
+	}
+
+	return res
+}
+
+func mentions(comment entity.Comment) []dto.UserComment {
+	var res []dto.UserComment
+	for _, mention := range comment.Mentions {
+		if mention.User != nil {
+			res = append(res, *userComment(mention.User))
+		}
+	}
+
+	return res
+}
+
+// reactions groups the reactions of a comment by type
+func reactions(comment entity.Comment) []dto.CommentReaction {
+	var res []dto.CommentReaction
+	for _, reactionType := range entity.CommentReactionTypes {
+		reaction := dto.CommentReaction{Type: string(reactionType)}
+		for _, r := range comment.Reactions {
+			if r.Type == reactionType && r.User != nil {
+				reaction.Users = append(reaction.Users, *userComment(r.User))
+			}
+		}
+
+		if reaction.Count = len(reaction.Users); reaction.Count > 0 {
+			res = append(res, reaction)
+		}
+	}
+
+	return res
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
+
+func uuidString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+
+	s := id.String()
+	return &s
+}
+
+func (s *commentService) checkPackagePermission(ctx context.Context, disciplineListDocumentId, userId string) (entity.DisciplineListDocument, entity.User, error) {
+	disciplineListDocument, err := s.disciplineListDocumentRepository.GetByID(ctx, nil, disciplineListDocumentId, "Document")
+	if err != nil {
+		return entity.DisciplineListDocument{}, entity.User{}, err
+	}
+
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return entity.DisciplineListDocument{}, entity.User{}, err
+	}
+
+	if user.PackageID != nil && disciplineListDocument.PackageID != *user.PackageID {
+		return entity.DisciplineListDocument{}, entity.User{}, myerror.New("you dont have permission in this package", http.StatusUnauthorized)
+	}
+
+	return disciplineListDocument, user, nil
+}
+
+// threadOf returns the top level comment of the thread the comment is in
+func (s *commentService) threadOf(ctx context.Context, comment entity.Comment) (entity.Comment, error) {
+	if comment.CommentReplyID == nil {
+		return comment, nil
+	}
+
+	return s.commentRepository.GetByID(ctx, nil, comment.CommentReplyID.String())
+}
+
+// visibleTo hides from contractors the threads outside the official set and
+// internal replies, a reply is passed along with its thread
+func visibleTo(user entity.User, comments ...entity.Comment) bool {
+	if user.Role != entity.RoleContractor {
+		return true
+	}
+
+	for _, comment := range comments {
+		if comment.IsInternal() || (comment.CommentReplyID == nil && !comment.InOfficialSet()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// commentScope is what the user may list, contractors only get the public
+// comments of the consolidated CRS
+func commentScope(user entity.User) repository.CommentScope {
+	if user.Role != entity.RoleContractor {
+		return repository.CommentScope{}
+	}
+
+	return repository.CommentScope{
+		Stages:     []entity.CommentStage{entity.CommentStageOfficial},
+		PublicOnly: true,
+	}
+}
+
+// commentVisibility resolves the visibility of a comment, public unless
+// asked otherwise. Contractors only write public comments and replies in an
+// internal thread stay internal.
+func commentVisibility(user entity.User, thread *entity.Comment, requested string) (entity.CommentVisibility, error) {
+	visibility := entity.CommentVisibility(requested)
+	if thread != nil && thread.IsInternal() {
+		if visibility == entity.CommentVisibilityPublic {
+			return "", myerror.New("replies to an internal comment are internal too", http.StatusBadRequest)
+		}
+		visibility = entity.CommentVisibilityInternal
+	}
+
+	if visibility == "" {
+		visibility = entity.CommentVisibilityPublic
+	}
+
+	if visibility == entity.CommentVisibilityInternal && user.Role == entity.RoleContractor {
+		return "", myerror.New("contractors can't write internal comments", http.StatusUnauthorized)
+	}
+
+	return visibility, nil
+}
+
+// classify sets the category and severity of a top level comment, a nil id
+// leaves the class as it is. Once a package defines classes of a kind, a
+// class of it is required when the comment is created or its class is set.
+func (s *commentService) classify(ctx context.Context, comment *entity.Comment, packageId uuid.UUID, categoryId, severityId *string) error {
+	if categoryId == nil && severityId == nil {
+		return nil
+	}
+
+	classes, err := s.commentClassRepository.GetAllByPackageID(ctx, nil, packageId.String())
+	if err != nil {
+		return err
+	}
+
+	pick := func(kind entity.CommentClassKind, id string) (*entity.CommentClass, error) {
+		defined := false
+		for i := range classes {
+			if classes[i].Kind != kind {
+				continue
+			}
+
+			defined = true
+			if classes[i].ID.String() == id {
+				return &classes[i], nil
+			}
+		}
+
+		label := strings.ToLower(string(kind))
+		if id != "" {
+			return nil, myerror.New(fmt.Sprintf("%s not found in this package", label), http.StatusBadRequest)
+		}
+
+		if defined {
+			return nil, myerror.New(fmt.Sprintf("%s is required", label), http.StatusBadRequest)
+		}
+
+		return nil, nil
+	}
+
+	if categoryId != nil {
+		category, err := pick(entity.CommentClassCategory, *categoryId)
+		if err != nil {
+			return err
+		}
+
+		comment.CategoryID, comment.Category = nil, category
+		if category != nil {
+			comment.CategoryID = &category.ID
+		}
+	}
+
+	if severityId != nil {
+		severity, err := pick(entity.CommentClassSeverity, *severityId)
+		if err != nil {
+			return err
+		}
+
+		comment.SeverityID, comment.Severity = nil, severity
+		if severity != nil {
+			comment.SeverityID = &severity.ID
+		}
+	}
+
+	return nil
+}
+
+// sentClass is the class id sent with an update, nil when it was left out
+func sentClass(requested string) *string {
+	if requested == "" {
+		return nil
+	}
+
+	return &requested
+}
+
+func commentClassCode(commentClass *entity.CommentClass) *dto.CommentClassCode {
+	if commentClass == nil {
+		return nil
+	}
+
+	return &dto.CommentClassCode{
+		ID:   commentClass.ID.String(),
+		Code: commentClass.Code,
+		Name: commentClass.Name,
+	}
+}
+
+func consolidatedBy(comment entity.Comment) *dto.UserComment {
+	if comment.ConsolidatedBy == nil {
+		return nil
+	}
+
+	return userComment(comment.ConsolidatedBy)
+}
+
+func onBehalfOf(comment entity.Comment) *dto.UserComment {
+	if comment.OnBehalfOf == nil {
+		return nil
+	}
+
+	return userComment(comment.OnBehalfOf)
+}
+
+// canModify allows super admins, the author, the user the comment was
+// written for and whoever currently stands in for one of them
+func (s *commentService) canModify(ctx context.Context, user entity.User, comment entity.Comment) (bool, error) {
+	if user.PackageID == nil {
+		return true, nil
+	}
+
+	owners := []uuid.UUID{comment.UserID}
+	if comment.OnBehalfOfID != nil {
+		owners = append(owners, *comment.OnBehalfOfID)
+	}
+
+	for _, owner := range owners {
+		ok, err := actsFor(ctx, s.delegationRepository, user.ID, owner)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+// validateCommentAnchor checks the anchor against the referenced document
+// revision. An empty revision on the anchor is pinned to the current one.
+func validateCommentAnchor(anchor *dto.CommentAnchor, document *entity.Document) error {
+	if anchor == nil {
+		return nil
+	}
+
+	if document == nil {
+		return myerror.New("document not found", http.StatusNotFound)
+	}
+
+	if anchor.Revision == "" {
+		anchor.Revision = document.Revision
+	} else if document.Revision != "" && anchor.Revision != document.Revision {
+		return myerror.New(fmt.Sprintf("anchor refers to revision %s but the document is at revision %s", anchor.Revision, document.Revision), http.StatusBadRequest)
+	}
+
+	if anchor.Page < 1 {
+		return myerror.New("anchor page must be greater than or equal to 1", http.StatusBadRequest)
+	}
+
+	if document.PageCount != nil && anchor.Page > *document.PageCount {
+		return myerror.New(fmt.Sprintf("anchor page %d exceeds the document page count (%d)", anchor.Page, *document.PageCount), http.StatusBadRequest)
+	}
+
+	box := []*float64{anchor.X, anchor.Y, anchor.Width, anchor.Height}
+	provided := 0
+	for _, v := range box {
+		if v == nil {
+			continue
+		}
+
+		provided++
+		if *v < 0 || *v > 1 {
+			return myerror.New("anchor bounding box values must be between 0 and 1", http.StatusBadRequest)
+		}
+	}
+
+	if provided != 0 && provided != len(box) {
+		return myerror.New("anchor bounding box needs x, y, width and height", http.StatusBadRequest)
+	}
+
+	if provided == len(box) {
+		if *anchor.Width <= 0 || *anchor.Height <= 0 {
+			return myerror.New("anchor bounding box must have a positive width and height", http.StatusBadRequest)
+		}
+
+		if *anchor.X+*anchor.Width > 1 || *anchor.Y+*anchor.Height > 1 {
+			return myerror.New("anchor bounding box falls outside the page", http.StatusBadRequest)
+		}
+	}
+
+	if anchor.Quote != nil {
+		quote := strings.TrimSpace(*anchor.Quote)
+		if len(quote) > maxAnchorQuoteLength {
+			return myerror.New(fmt.Sprintf("anchor quote must not exceed %d characters", maxAnchorQuoteLength), http.StatusBadRequest)
+		}
+
+		anchor.Quote = &quote
+		if quote == "" {
+			anchor.Quote = nil
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/google/uuid"
+)
+
+func TestCommentServiceReply(t *testing.T) {
+	// the contractor closes out the comment of the reviewer
+	reply := func(f *fakeFixture) error {
+		_, err := f.commentService().Reply(context.Background(), dto.CommentRequest{
+			Comment:                  "Clearance added on sheet 2",
+			IsCloseOutComment:        true,
+			DisciplineListDocumentId: f.disciplineListDocument.ID.String(),
+			UserId:                   f.contractor.ID.String(),
+			ReplyId:                  f.comment.ID.String(),
+		})
+		return err
+	}
+
+	t.Run("rolls back", func(t *testing.T) {
+		f := newFakeFixture(t)
+		f.db.fail("CommentRepository.Create", 1)
+
+		if err := reply(f); !errors.Is(err, errFakeWrite) {
+			t.Fatalf("Reply() error = %v, want %v", err, errFakeWrite)
+		}
+		f.assertWrites(t)
+	})
+
+	t.Run("commits", func(t *testing.T) {
+		f := newFakeFixture(t)
+
+		if err := reply(f); err != nil {
+			t.Fatalf("Reply() error = %v", err)
+		}
+		f.assertWrites(t, "CommentRepository.Update", "CommentRepository.Create", "NotificationService.Notify")
+	})
+
+	t.Run("keeps the reply when the notification fails", func(t *testing.T) {
+		f := newFakeFixture(t)
+		f.db.fail("NotificationService.Notify", 1)
+
+		if err := reply(f); err != nil {
+			t.Fatalf("Reply() error = %v", err)
+		}
+		f.assertWrites(t, "CommentRepository.Update", "CommentRepository.Create")
+	})
+}
+
+func TestCommentServiceConsolidate(t *testing.T) {
+	for action, want := range map[string]entity.CommentStage{
+		dto.ConsolidateAccept:  entity.CommentStageOfficial,
+		dto.ConsolidateDiscard: entity.CommentStageDiscarded,
+		dto.ConsolidateDraft:   entity.CommentStageDraft,
+	} {
+		t.Run(action, func(t *testing.T) {
+			f := newFakeFixture(t)
+
+			_, err := f.commentService().Consolidate(context.Background(), dto.ConsolidateCommentRequest{
+				ID:                       f.comment.ID.String(),
+				Action:                   action,
+				DisciplineListDocumentId: f.disciplineListDocument.ID.String(),
+				UserId:                   f.superAdmin.ID.String(),
+			})
+			if err != nil {
+				t.Fatalf("Consolidate() error = %v", err)
+			}
+
+			updates := f.db.written("CommentRepository.Update")
+			if len(updates) != 1 || updates[0].(entity.Comment).Stage != want {
+				t.Errorf("updates = %+v, want the comment %s", updates, want)
+			}
+		})
+	}
+}
+
+func TestCommentServiceConsolidateClasses(t *testing.T) {
+	// the package defines categories after the comment was written
+	newFixture := func(t *testing.T) *fakeFixture {
+		f := newFakeFixture(t)
+		f.commentClasses = []entity.CommentClass{{ID: uuid.New(), Kind: entity.CommentClassCategory, Code: "A", PackageID: f.pkg.ID}}
+		return f
+	}
+
+	t.Run("left out", func(t *testing.T) {
+		f := newFixture(t)
+
+		_, err := f.commentService().Consolidate(context.Background(), dto.ConsolidateCommentRequest{
+			ID:                       f.comment.ID.String(),
+			Action:                   dto.ConsolidateAccept,
+			DisciplineListDocumentId: f.disciplineListDocument.ID.String(),
+			UserId:                   f.superAdmin.ID.String(),
+		})
+		if err != nil {
+			t.Fatalf("Consolidate() error = %v", err)
+		}
+	})
+
+	t.Run("cleared", func(t *testing.T) {
+		f := newFixture(t)
+
+		cleared := ""
+		_, err := f.commentService().Consolidate(context.Background(), dto.ConsolidateCommentRequest{
+			ID:                       f.comment.ID.String(),
+			Action:                   dto.ConsolidateAccept,
+			CategoryId:               &cleared,
+			DisciplineListDocumentId: f.disciplineListDocument.ID.String(),
+			UserId:                   f.superAdmin.ID.String(),
+		})
+		assertStatusCode(t, err, http.StatusBadRequest)
+		f.assertWrites(t)
+	})
+}
+
+func TestCommentServiceReplyNotifiesAuthor(t *testing.T) {
+	for visibility, want := range map[entity.CommentVisibility][]string{
+		entity.CommentVisibilityPublic:   {"CommentRepository.Create", "NotificationService.Notify"},
+		entity.CommentVisibilityInternal: {"CommentRepository.Create"},
+	} {
+		t.Run(string(visibility), func(t *testing.T) {
+			// the contractor wrote the comment, an internal reply stays
+			// hidden from them
+			f := newFakeFixture(t)
+			f.comment.UserID = f.contractor.ID
+
+			_, err := f.commentService().Reply(context.Background(), dto.CommentRequest{
+				Comment:                  "Checked with the piping lead",
+				Visibility:               string(visibility),
+				DisciplineListDocumentId: f.disciplineListDocument.ID.String(),
+				UserId:                   f.reviewer.ID.String(),
+				ReplyId:                  f.comment.ID.String(),
+			})
+			if err != nil {
+				t.Fatalf("Reply() error = %v", err)
+			}
+			f.assertWrites(t, want...)
+		})
+	}
+}
+
+func TestCommentServiceMentionsContractorOnlyInOfficialThreads(t *testing.T) {
+	t.Run("draft comment", func(t *testing.T) {
+		f := newFakeFixture(t)
+
+		_, err := f.commentService().Create(context.Background(), dto.CommentRequest{
+			Comment:                  "Pipe support spacing is missing",
+			Mentions:                 []string{f.contractor.ID.String()},
+			DisciplineListDocumentId: f.disciplineListDocument.ID.String(),
+			UserId:                   f.reviewer.ID.String(),
+		})
+		assertStatusCode(t, err, http.StatusBadRequest)
+		f.assertWrites(t)
+	})
+
+	t.Run("reply on a draft thread", func(t *testing.T) {
+		f := newFakeFixture(t)
+		f.comment.Stage = entity.CommentStageDraft
+
+		_, err := f.commentService().Reply(context.Background(), dto.CommentRequest{
+			Comment:                  "See the support drawing",
+			Mentions:                 []string{f.contractor.ID.String()},
+			DisciplineListDocumentId: f.disciplineListDocument.ID.String(),
+			UserId:                   f.superAdmin.ID.String(),
+			ReplyId:                  f.comment.ID.String(),
+		})
+		assertStatusCode(t, err, http.StatusBadRequest)
+		f.assertWrites(t)
+	})
+
+	t.Run("reply on an official thread", func(t *testing.T) {
+		f := newFakeFixture(t)
+
+		_, err := f.commentService().Reply(context.Background(), dto.CommentRequest{
+			Comment:                  "See the support drawing",
+			Mentions:                 []string{f.contractor.ID.String()},
+			DisciplineListDocumentId: f.disciplineListDocument.ID.String(),
+			UserId:                   f.superAdmin.ID.String(),
+			ReplyId:                  f.comment.ID.String(),
+		})
+		if err != nil {
+			t.Fatalf("Reply() error = %v", err)
+		}
+		f.assertWrites(t, "CommentRepository.Create", "NotificationService.Notify", "NotificationService.Notify")
+	})
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	DelegationService interface {
+		Create(ctx context.Context, req dto.DelegationRequest) (dto.DelegationResponse, error)
+		GetAll(ctx context.Context, userId string) ([]dto.DelegationResponse, error)
+		Update(ctx context.Context, req dto.DelegationRequest) (dto.DelegationResponse, error)
+		Delete(ctx context.Context, userId, delegationId string) error
+	}
+
+	delegationService struct {
+		delegationRepository repository.DelegationRepository
+		userRepository       repository.UserRepository
+		db                   *gorm.DB
+	}
+)
+
+func NewDelegation(delegationRepository repository.DelegationRepository,
+	userRepository repository.UserRepository,
+	db *gorm.DB) DelegationService {
+	return &delegationService{
+		delegationRepository: delegationRepository,
+		userRepository:       userRepository,
+		db:                   db,
+	}
+}
+
+func (s *delegationService) Create(ctx context.Context, req dto.DelegationRequest) (dto.DelegationResponse, error) {
+	user, err := s.userRepository.GetById(ctx, nil, req.UserId)
+	if err != nil {
+		return dto.DelegationResponse{}, err
+	}
+
+	delegator := user
+	if req.OnBehalfOfID != nil && *req.OnBehalfOfID != user.ID.String() {
+		if user.PackageID != nil {
+			return dto.DelegationResponse{}, myerror.New("only super admin can delegate for another user", http.StatusUnauthorized)
+		}
+
+		delegator, err = s.userRepository.GetById(ctx, nil, *req.OnBehalfOfID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.DelegationResponse{}, myerror.New("user not found", http.StatusNotFound)
+			}
+			return dto.DelegationResponse{}, err
+		}
+	}
+
+	delegation := entity.Delegation{
+		UserID: delegator.ID,
+	}
+	if err := s.fill(ctx, &delegation, delegator, req); err != nil {
+		return dto.DelegationResponse{}, err
+	}
+
+	delegation, err = s.delegationRepository.Create(ctx, nil, delegation)
+	if err != nil {
+		return dto.DelegationResponse{}, err
+	}
+
+	return s.getResponse(ctx, delegation.ID.String())
+}
+
+func (s *delegationService) GetAll(ctx context.Context, userId string) ([]dto.DelegationResponse, error) {
+	delegations, err := s.delegationRepository.GetAllByUserID(ctx, nil, userId, "User", "Substitute")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	res := []dto.DelegationResponse{}
+	for _, delegation := range delegations {
+		res = append(res, delegationResponse(delegation, now))
+	}
+
+	return res, nil
+}
+
+func (s *delegationService) Update(ctx context.Context, req dto.DelegationRequest) (dto.DelegationResponse, error) {
+	delegation, err := s.getOwned(ctx, req.UserId, req.ID)
+	if err != nil {
+		return dto.DelegationResponse{}, err
+	}
+
+	if !delegation.EndAt.After(time.Now()) {
+		return dto.DelegationResponse{}, myerror.New("delegation already ended", http.StatusBadRequest)
+	}
+
+	delegator, err := s.userRepository.GetById(ctx, nil, delegation.UserID.String())
+	if err != nil {
+		return dto.DelegationResponse{}, err
+	}
+
+	if err := s.fill(ctx, &delegation, delegator, req); err != nil {
+		return dto.DelegationResponse{}, err
+	}
+
+	delegation.UpdatedBy = uuid.MustParse(req.UserId)
+	if _, err := s.delegationRepository.Update(ctx, nil, delegation); err != nil {
+		return dto.DelegationResponse{}, err
+	}
+
+	return s.getResponse(ctx, delegation.ID.String())
+}
+
+// Delete withdraws the delegation. One that already started is ended now
+// instead, so what the substitute did under it stays traceable.
+func (s *delegationService) Delete(ctx context.Context, userId, delegationId string) error {
+	delegation, err := s.getOwned(ctx, userId, delegationId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !delegation.EndAt.After(now) {
+		return myerror.New("delegation already ended", http.StatusBadRequest)
+	}
+
+	if delegation.StartAt.Before(now) {
+		delegation.EndAt = now
+		delegation.UpdatedBy = uuid.MustParse(userId)
+		_, err := s.delegationRepository.Update(ctx, nil, delegation)
+		return err
+	}
+
+	delegation.DeletedBy = uuid.MustParse(userId)
+	return s.delegationRepository.Delete(ctx, nil, delegation)
+}
+
+// fill validates the request against the delegator and copies it into the
+// delegation
+func (s *delegationService) fill(ctx context.Context, delegation *entity.Delegation, delegator entity.User, req dto.DelegationRequest) error {
+	if !req.EndAt.After(req.StartAt) {
+		return myerror.New("end_at must be after start_at", http.StatusBadRequest)
+	}
+
+	if !req.EndAt.After(time.Now()) {
+		return myerror.New("end_at must be in the future", http.StatusBadRequest)
+	}
+
+	if req.SubstituteID == delegator.ID.String() {
+		return myerror.New("you can't delegate to yourself", http.StatusBadRequest)
+	}
+
+	substitute, err := s.userRepository.GetById(ctx, nil, req.SubstituteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return myerror.New("substitute not found", http.StatusNotFound)
+		}
+		return err
+	}
+
+	// the substitute works on the same documents, so they must be able to
+	// see the package and review the same way
+	if delegator.PackageID != nil && (substitute.PackageID == nil || *substitute.PackageID != *delegator.PackageID) {
+		return myerror.New("substitute must be in the same package", http.StatusBadRequest)
+	}
+
+	if substitute.Role != delegator.Role {
+		return myerror.New("substitute must have the same role", http.StatusBadRequest)
+	}
+
+	overlapping, err := s.delegationRepository.GetOverlapping(ctx, nil, delegator.ID.String(), req.StartAt, req.EndAt, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(overlapping) > 0 {
+		return myerror.New("there is already a delegation in this period", http.StatusConflict)
+	}
+
+	// delegations are not chained, a substitute who is away can't stand in
+	away, err := s.delegationRepository.GetOverlapping(ctx, nil, substitute.ID.String(), req.StartAt, req.EndAt, "")
+	if err != nil {
+		return err
+	}
+
+	if len(away) > 0 {
+		return myerror.New("substitute is away during this period", http.StatusBadRequest)
+	}
+
+	delegation.SubstituteID = substitute.ID
+	delegation.StartAt = req.StartAt
+	delegation.EndAt = req.EndAt
+	delegation.Reason = req.Reason
+
+	return nil
+}
+
+// getOwned returns the delegation if the user gave it or is a super admin
+func (s *delegationService) getOwned(ctx context.Context, userId, delegationId string) (entity.Delegation, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return entity.Delegation{}, err
+	}
+
+	delegation, err := s.delegationRepository.GetByID(ctx, nil, delegationId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Delegation{}, myerror.New("delegation not found", http.StatusNotFound)
+		}
+		return entity.Delegation{}, err
+	}
+
+	if user.PackageID != nil && delegation.UserID != user.ID {
+		return entity.Delegation{}, myerror.New("you don't have permission for this delegation", http.StatusUnauthorized)
+	}
+
+	return delegation, nil
+}
+
+func (s *delegationService) getResponse(ctx context.Context, delegationId string) (dto.DelegationResponse, error) {
+	delegation, err := s.delegationRepository.GetByID(ctx, nil, delegationId, "User", "Substitute")
+	if err != nil {
+		return dto.DelegationResponse{}, err
+	}
+
+	return delegationResponse(delegation, time.Now()), nil
+}
+
+func delegationResponse(delegation entity.Delegation, now time.Time) dto.DelegationResponse {
+	res := dto.DelegationResponse{
+		ID:       delegation.ID.String(),
+		StartAt:  delegation.StartAt,
+		EndAt:    delegation.EndAt,
+		Reason:   delegation.Reason,
+		IsActive: delegation.IsActive(now),
+	}
+
+	if delegation.User != nil {
+		res.User = userComment(delegation.User)
+	}
+
+	if delegation.Substitute != nil {
+		res.Substitute = userComment(delegation.Substitute)
+	}
+
+	return res
+}
+
+func userComment(user *entity.User) *dto.UserComment {
+	return &dto.UserComment{
+		ID:           user.ID.String(),
+		Name:         user.Name,
+		PhotoProfile: user.PhotoProfile,
+		Role:         string(user.Role),
+	}
+}
+
+// actsFor tells whether the actor is the owner or stands in for them under
+// an active delegation
+func actsFor(ctx context.Context, delegationRepository repository.DelegationRepository, actorId, ownerId uuid.UUID) (bool, error) {
+	if actorId == ownerId {
+		return true, nil
+	}
+
+	delegations, err := delegationRepository.GetActiveBySubstituteID(ctx, nil, actorId.String(), time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	for _, delegation := range delegations {
+		if delegation.UserID == ownerId {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// resolveOnBehalfOf checks the user the actor claims to act for. It returns
+// nil when the actor acts for themselves.
+func resolveOnBehalfOf(ctx context.Context, delegationRepository repository.DelegationRepository, actorId uuid.UUID, onBehalfOfId *string) (*uuid.UUID, error) {
+	if onBehalfOfId == nil || *onBehalfOfId == "" || *onBehalfOfId == actorId.String() {
+		return nil, nil
+	}
+
+	ownerId, err := uuid.Parse(*onBehalfOfId)
+	if err != nil {
+		return nil, myerror.New("invalid on_behalf_of_id", http.StatusBadRequest)
+	}
+
+	ok, err := actsFor(ctx, delegationRepository, actorId, ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, myerror.New("you have no active delegation from this user", http.StatusUnauthorized)
+	}
+
+	return &ownerId, nil
+}
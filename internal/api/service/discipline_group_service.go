@@ -27,7 +27,8 @@ type (
 		Delete(ctx context.Context, userId, disciplineGroupId string) error
 		GeneratePDF(ctx context.Context, userId, disciplineGroupId string) (*bytes.Buffer, string, error)
 		GenerateExcel(ctx context.Context, userId, disciplineGroupId string) (*bytes.Buffer, string, error)
-		GetStatistic(ctx context.Context, packageId string) (dto.DisciplineGroupStatistic, error)
+		BuildReport(ctx context.Context, disciplineGroupId string) ([]mypdf.GenerateRequestData, mypdf.Template, entity.DisciplineGroup, error)
+		GetStatistic(ctx context.Context, userId, packageId string) (dto.DisciplineGroupStatistic, error)
 		ConstructGeneratePDF(disciplineGroup entity.DisciplineGroup, contractor entity.User) []mypdf.GenerateRequestData
 	}
 
@@ -40,6 +41,7 @@ type (
 		commentRepository                            repository.CommentRepository
 		userRepository                               repository.UserRepository
 		userDisciplineRepository                     repository.UserDisciplineRepository
+		reportTemplateRepository                     repository.ReportTemplateRepository
 		db                                           *gorm.DB
 	}
 )
@@ -52,6 +54,7 @@ func NewDisciplineGroup(disciplineGroupRepository repository.DisciplineGroupRepo
 	commentRepository repository.CommentRepository,
 	userRepository repository.UserRepository,
 	userDisciplineRepository repository.UserDisciplineRepository,
+	reportTemplateRepository repository.ReportTemplateRepository,
 	db *gorm.DB) DisciplineGroupService {
 	return &disciplineGroupService{
 		disciplineGroupRepository:                    disciplineGroupRepository,
@@ -62,6 +65,7 @@ func NewDisciplineGroup(disciplineGroupRepository repository.DisciplineGroupRepo
 		commentRepository:                            commentRepository,
 		userRepository:                               userRepository,
 		userDisciplineRepository:                     userDisciplineRepository,
+		reportTemplateRepository:                     reportTemplateRepository,
 		db:                                           db,
 	}
 }
@@ -265,28 +269,27 @@ func (s *disciplineGroupService) Update(ctx context.Context, req dto.DisciplineG
 		}
 	}
 
-	for _, consID := range toDelete {
-		if err := s.disciplineListDocumentConsolidatorRepository.DeleteByDisciplineGroupConsolidatorID(ctx, nil, []string{consID.String()}); err != nil {
-			return err
-		}
+	// consolidators and the group are saved together or not at all
+	return repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		for _, consID := range toDelete {
+			if err := s.disciplineListDocumentConsolidatorRepository.DeleteByDisciplineGroupConsolidatorID(ctx, nil, []string{consID.String()}); err != nil {
+				return err
+			}
 
-		if err := s.disciplineGroupConsolidatorRepository.DeleteByID(ctx, nil, consID.String()); err != nil {
-			return err
+			if err := s.disciplineGroupConsolidatorRepository.DeleteByID(ctx, nil, consID.String()); err != nil {
+				return err
+			}
 		}
-	}
 
-	if len(toCreate) > 0 {
-		if err := s.disciplineGroupConsolidatorRepository.CreateBulk(ctx, nil, toCreate); err != nil {
-			return err
+		if len(toCreate) > 0 {
+			if err := s.disciplineGroupConsolidatorRepository.CreateBulk(ctx, nil, toCreate); err != nil {
+				return err
+			}
 		}
-	}
-
-	// Update disciplineGroup record
-	if err := s.disciplineGroupRepository.Update(ctx, nil, disciplineGroup); err != nil {
-		return err
-	}
 
-	return nil
+		// Update disciplineGroup record
+		return s.disciplineGroupRepository.Update(ctx, nil, disciplineGroup)
+	})
 }
 
 func (s *disciplineGroupService) Delete(ctx context.Context, userId, disciplineGroupId string) error {
@@ -309,71 +312,87 @@ func (s *disciplineGroupService) Delete(ctx context.Context, userId, disciplineG
 		disciplineListDocumentIDs = append(disciplineListDocumentIDs, dld.ID.String())
 	}
 
-	if len(disciplineListDocumentIDs) > 0 {
-		if err := s.commentRepository.DeleteByDisciplineListDocumentID(ctx, nil, disciplineListDocumentIDs); err != nil {
-			return err
+	return repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if len(disciplineListDocumentIDs) > 0 {
+			if err := s.commentRepository.DeleteByDisciplineListDocumentID(ctx, nil, disciplineListDocumentIDs); err != nil {
+				return err
+			}
+
+			if err := s.disciplineListDocumentConsolidatorRepository.DeleteByDisciplineListDocumentID(ctx, nil, disciplineListDocumentIDs); err != nil {
+				return err
+			}
 		}
 
-		if err := s.disciplineListDocumentConsolidatorRepository.DeleteByDisciplineListDocumentID(ctx, nil, disciplineListDocumentIDs); err != nil {
+		if err := s.disciplineListDocumentRepository.DeleteByDisciplineGroupID(ctx, nil, disciplineGroup.ID.String()); err != nil {
 			return err
 		}
-	}
-
-	if err := s.disciplineListDocumentRepository.DeleteByDisciplineGroupID(ctx, nil, disciplineGroup.ID.String()); err != nil {
-		return err
-	}
-
-	// mark who deleted
-	disciplineGroup.DeletedBy = uuid.MustParse(userId)
-	if err = s.disciplineGroupRepository.Delete(ctx, nil, disciplineGroup); err != nil {
-		return err
-	}
 
-	return nil
+		// mark who deleted
+		disciplineGroup.DeletedBy = uuid.MustParse(userId)
+		return s.disciplineGroupRepository.Delete(ctx, nil, disciplineGroup)
+	})
 }
 
 func (s *disciplineGroupService) GeneratePDF(ctx context.Context, userId, disciplineGroupId string) (*bytes.Buffer, string, error) {
-	data, err := s.disciplineGroupRepository.GetByID(ctx, nil, disciplineGroupId, "DisciplineGroupConsolidators.User", "DisciplineListDocuments.Comments.CommentReplies", "DisciplineListDocuments.Document", "DisciplineListDocuments.Comments.User", "Package")
+	requestData, tpl, _, err := s.BuildReport(ctx, disciplineGroupId)
 	if err != nil {
 		return nil, "", err
 	}
 
-	contractor, err := s.userRepository.GetContractorByPackage(ctx, nil, data.PackageID.String(), "Package")
+	pdfBuffer, filename, err := mypdf.Generate(requestData, tpl)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return pdfBuffer, filename, nil
+}
+
+func (s *disciplineGroupService) GenerateExcel(ctx context.Context, userId, disciplineGroupId string) (*bytes.Buffer, string, error) {
+	requestData, tpl, _, err := s.BuildReport(ctx, disciplineGroupId)
 	if err != nil {
 		return nil, "", err
 	}
 
-	requestData := s.ConstructGeneratePDF(data, contractor)
-	pdfBuffer, filename, err := mypdf.Generate(requestData)
+	excelBuffer, filename, err := mypdf.GenerateExcel(requestData, tpl)
 	if err != nil {
 		return nil, "", err
 	}
 
-	return pdfBuffer, filename, nil
+	return excelBuffer, filename, nil
 }
 
-func (s *disciplineGroupService) GenerateExcel(ctx context.Context, userId, disciplineGroupId string) (*bytes.Buffer, string, error) {
-	data, err := s.disciplineGroupRepository.GetByID(ctx, nil, disciplineGroupId, "DisciplineGroupConsolidators.User", "DisciplineListDocuments.Comments.CommentReplies", "DisciplineListDocuments.Document", "DisciplineListDocuments.Comments.User", "Package")
+// BuildReport loads everything the CRS of a discipline group needs, together
+// with the report template of its package
+func (s *disciplineGroupService) BuildReport(ctx context.Context, disciplineGroupId string) ([]mypdf.GenerateRequestData, mypdf.Template, entity.DisciplineGroup, error) {
+	data, err := s.disciplineGroupRepository.GetByID(ctx, nil, disciplineGroupId, "DisciplineGroupConsolidators.User", "DisciplineListDocuments.Comments.CommentReplies", "DisciplineListDocuments.Document.ReviewOutcomes", "DisciplineListDocuments.Comments.User", "DisciplineListDocuments.Comments.Category", "DisciplineListDocuments.Comments.Severity", "Package")
 	if err != nil {
-		return nil, "", err
+		return nil, mypdf.Template{}, entity.DisciplineGroup{}, err
 	}
 
 	contractor, err := s.userRepository.GetContractorByPackage(ctx, nil, data.PackageID.String(), "Package")
 	if err != nil {
-		return nil, "", err
+		return nil, mypdf.Template{}, entity.DisciplineGroup{}, err
 	}
 
-	requestData := s.ConstructGeneratePDF(data, contractor)
-	excelBuffer, filename, err := mypdf.GenerateExcel(requestData)
+	tpl, err := loadReportTemplate(ctx, s.reportTemplateRepository, data.PackageID.String())
 	if err != nil {
-		return nil, "", err
+		return nil, mypdf.Template{}, entity.DisciplineGroup{}, err
 	}
 
-	return excelBuffer, filename, nil
+	return s.ConstructGeneratePDF(data, contractor), tpl, data, nil
 }
 
-func (s *disciplineGroupService) GetStatistic(ctx context.Context, packageId string) (dto.DisciplineGroupStatistic, error) {
-	return s.disciplineGroupRepository.Statistic(ctx, nil, packageId)
+func (s *disciplineGroupService) GetStatistic(ctx context.Context, userId, packageId string) (dto.DisciplineGroupStatistic, error) {
+	pkg, user, err := s.getPackagePermission(ctx, userId)
+	if err != nil {
+		return dto.DisciplineGroupStatistic{}, err
+	}
+
+	if pkg != nil && pkg.ID.String() != packageId {
+		return dto.DisciplineGroupStatistic{}, myerror.New("you not allowed to this package", http.StatusUnauthorized)
+	}
+
+	return s.disciplineGroupRepository.Statistic(ctx, nil, packageId, statisticCommentScope(user))
 }
 
 func (s *disciplineGroupService) ConstructGeneratePDF(disciplineGroup entity.DisciplineGroup, contractor entity.User) []mypdf.GenerateRequestData {
@@ -393,8 +412,8 @@ func (s *disciplineGroupService) ConstructGeneratePDF(disciplineGroup entity.Dis
 
 	for _, dld := range disciplineGroup.DisciplineListDocuments {
 		var comments []mypdf.CommentRow
-		for i, c := range dld.Comments {
-			if c.CommentReplyID != nil {
+		for _, c := range dld.Comments {
+			if !c.InOfficialSet() {
 				continue
 			}
 
@@ -405,7 +424,7 @@ func (s *disciplineGroupService) ConstructGeneratePDF(disciplineGroup entity.Dis
 
 			closeOutComments := "N/A"
 			for _, cr := range c.CommentReplies {
-				if cr.IsCloseOutComment {
+				if cr.IsCloseOutComment && !cr.IsInternal() {
 					closeOutComments = cr.Comment
 					break
 				}
@@ -417,8 +436,8 @@ func (s *disciplineGroupService) ConstructGeneratePDF(disciplineGroup entity.Dis
 				docStatus = string(dld.Document.Status)
 			}
 			comments = append(comments, mypdf.CommentRow{
-				No:              fmt.Sprintf("%d", i+1),
-				Page:            c.Section,
+				No:              fmt.Sprintf("%d", len(comments)+1),
+				Page:            c.PageLabel(),
 				SMEInitial:      c.User.Name,
 				SMEComment:      c.Comment,
 				RefDocNo:        refDocNo,
@@ -426,6 +445,8 @@ func (s *disciplineGroupService) ConstructGeneratePDF(disciplineGroup entity.Dis
 				DocStatus:       string(docStatus),
 				Status:          status,
 				SMECloseComment: closeOutComments,
+				Category:        c.Category.NameOf(),
+				Severity:        c.Severity.CodeOf(),
 			})
 		}
 
@@ -433,6 +454,7 @@ func (s *disciplineGroupService) ConstructGeneratePDF(disciplineGroup entity.Dis
 			PackageInfoData: mypdf.PackageInfoData{
 				Package:           contractor.Package.Name,
 				ContractorInitial: contractor.Name,
+				ReviewOutcome:     reviewOutcomeLabel(dld.Document),
 			},
 			DisciplineSectionData: mypdf.DisciplineSectionData{
 				Discipline: disciplineGroup.UserDiscipline,
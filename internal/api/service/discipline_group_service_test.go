@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/CRS-Project/crs-backend/internal/dto"
+)
+
+func TestDisciplineGroupServiceUpdate(t *testing.T) {
+	// the reviewer is swapped for the contractor as consolidator
+	update := func(f *fakeFixture) error {
+		return f.disciplineGroupService().Update(context.Background(), dto.DisciplineGroupRequest{
+			ID:             f.disciplineGroup.ID.String(),
+			ReviewFocus:    "Structure",
+			UserDiscipline: "Civil",
+			PackageID:      f.pkg.ID.String(),
+			DisciplineGroupConsolidators: []dto.DisciplineGroupConsolidatorRequest{
+				{UserID: f.contractor.ID.String()},
+			},
+			UserId: f.superAdmin.ID.String(),
+		})
+	}
+
+	t.Run("rolls back", func(t *testing.T) {
+		f := newFakeFixture(t)
+		f.db.fail("DisciplineGroupRepository.Update", 1)
+
+		if err := update(f); !errors.Is(err, errFakeWrite) {
+			t.Fatalf("Update() error = %v, want %v", err, errFakeWrite)
+		}
+		f.assertWrites(t)
+	})
+
+	t.Run("commits", func(t *testing.T) {
+		f := newFakeFixture(t)
+
+		if err := update(f); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		f.assertWrites(t,
+			"DisciplineListDocumentConsolidatorRepository.DeleteByDisciplineGroupConsolidatorID",
+			"DisciplineGroupConsolidatorRepository.DeleteByID",
+			"DisciplineGroupConsolidatorRepository.CreateBulk",
+			"DisciplineGroupRepository.Update")
+	})
+}
+
+func TestDisciplineGroupServiceDelete(t *testing.T) {
+	t.Run("rolls back", func(t *testing.T) {
+		f := newFakeFixture(t)
+		f.db.fail("DisciplineGroupRepository.Delete", 1)
+
+		err := f.disciplineGroupService().Delete(context.Background(), f.superAdmin.ID.String(), f.disciplineGroup.ID.String())
+		if !errors.Is(err, errFakeWrite) {
+			t.Fatalf("Delete() error = %v, want %v", err, errFakeWrite)
+		}
+		f.assertWrites(t)
+	})
+
+	t.Run("commits", func(t *testing.T) {
+		f := newFakeFixture(t)
+
+		err := f.disciplineGroupService().Delete(context.Background(), f.superAdmin.ID.String(), f.disciplineGroup.ID.String())
+		if err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		f.assertWrites(t,
+			"CommentRepository.DeleteByDisciplineListDocumentID",
+			"DisciplineListDocumentConsolidatorRepository.DeleteByDisciplineListDocumentID",
+			"DisciplineListDocumentRepository.DeleteByDisciplineGroupID",
+			"DisciplineGroupRepository.Delete")
+	})
+}
+
+func TestDisciplineGroupServiceGetStatistic(t *testing.T) {
+	f := newFakeFixture(t)
+
+	_, err := f.disciplineGroupService().GetStatistic(context.Background(), f.reviewer.ID.String(), f.otherPkg.ID.String())
+	assertStatusCode(t, err, http.StatusUnauthorized)
+
+	if _, err := f.disciplineGroupService().GetStatistic(context.Background(), f.reviewer.ID.String(), f.pkg.ID.String()); err != nil {
+		t.Errorf("GetStatistic() on own package error = %v", err)
+	}
+
+	if _, err := f.disciplineGroupService().GetStatistic(context.Background(), f.superAdmin.ID.String(), f.otherPkg.ID.String()); err != nil {
+		t.Errorf("GetStatistic() as super admin error = %v", err)
+	}
+}
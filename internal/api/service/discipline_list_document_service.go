@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/CRS-Project/crs-backend/internal/api/repository"
@@ -15,6 +16,7 @@ import (
 	mylog "github.com/CRS-Project/crs-backend/internal/pkg/logger"
 	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
 	mypdf "github.com/CRS-Project/crs-backend/internal/pkg/pdf"
+	"github.com/CRS-Project/crs-backend/internal/utils"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -22,11 +24,13 @@ import (
 type (
 	DisciplineListDocumentService interface {
 		Create(ctx context.Context, req dto.DisciplineListDocumentRequest) (dto.DisciplineListDocumentResponse, error)
+		CreateBulk(ctx context.Context, req dto.BulkDisciplineListDocumentRequest) (dto.BulkDisciplineListDocumentResponse, error)
 		GetById(ctx context.Context, disciplineListDocumentId string) (dto.DisciplineListDocumentResponse, error)
 		GetAll(ctx context.Context, disciplineGroupId, userId string, metaReq meta.Meta) ([]dto.DisciplineListDocumentResponse, meta.Meta, error)
 		Update(ctx context.Context, req dto.UpdateDisciplineListDocumentRequest) error
 		Delete(ctx context.Context, userId, disciplineListDocumentId string) error
 		GenerateExcel(ctx context.Context, userId, disciplineListDocumentId string) (*bytes.Buffer, string, error)
+		GenerateMarkupPDF(ctx context.Context, userId, disciplineListDocumentId string) (*bytes.Buffer, string, error)
 	}
 
 	disciplineListDocumentService struct {
@@ -38,6 +42,8 @@ type (
 		documentRepository                           repository.DocumentRepository
 		userRepository                               repository.UserRepository
 		userDisciplineRepository                     repository.UserDisciplineRepository
+		reportTemplateRepository                     repository.ReportTemplateRepository
+		notificationService                          NotificationService
 		db                                           *gorm.DB
 	}
 )
@@ -50,6 +56,8 @@ func NewDisciplineListDocument(disciplineListDocumentRepository repository.Disci
 	documentRepository repository.DocumentRepository,
 	userRepository repository.UserRepository,
 	userDisciplineRepository repository.UserDisciplineRepository,
+	reportTemplateRepository repository.ReportTemplateRepository,
+	notificationService NotificationService,
 	db *gorm.DB) DisciplineListDocumentService {
 	return &disciplineListDocumentService{
 		disciplineListDocumentRepository:             disciplineListDocumentRepository,
@@ -60,6 +68,8 @@ func NewDisciplineListDocument(disciplineListDocumentRepository repository.Disci
 		documentRepository:                           documentRepository,
 		userRepository:                               userRepository,
 		userDisciplineRepository:                     userDisciplineRepository,
+		reportTemplateRepository:                     reportTemplateRepository,
+		notificationService:                          notificationService,
 		db:                                           db,
 	}
 }
@@ -83,10 +93,12 @@ func (s *disciplineListDocumentService) Create(ctx context.Context, req dto.Disc
 	}
 
 	var consolidatorsInput []entity.DisciplineListDocumentConsolidator
+	var consolidatorIds []string
 	for _, consolidator := range req.Consolidators {
 		consolidatorsInput = append(consolidatorsInput, entity.DisciplineListDocumentConsolidator{
 			DisciplineGroupConsolidatorID: uuid.MustParse(consolidator.DisciplineGroupConsolidatorID),
 		})
+		consolidatorIds = append(consolidatorIds, consolidator.DisciplineGroupConsolidatorID)
 	}
 
 	disciplinegroup, err := s.disciplineGroupRepository.GetByID(ctx, nil, req.DisciplineGroupID)
@@ -102,11 +114,22 @@ func (s *disciplineListDocumentService) Create(ctx context.Context, req dto.Disc
 		return dto.DisciplineListDocumentResponse{}, err
 	}
 
-	disciplineListDocumentResult, err := s.disciplineListDocumentRepository.Create(ctx, nil, entity.DisciplineListDocument{
-		DisciplineGroupID: disciplinegroup.ID,
-		DocumentID:        document.ID,
-		PackageID:         pkg.ID,
-		Consolidators:     consolidatorsInput,
+	var disciplineListDocumentResult entity.DisciplineListDocument
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		disciplineListDocumentResult, err = s.disciplineListDocumentRepository.Create(ctx, nil, entity.DisciplineListDocument{
+			DisciplineGroupID: disciplinegroup.ID,
+			DocumentID:        document.ID,
+			PackageID:         pkg.ID,
+			Consolidators:     consolidatorsInput,
+		})
+		if err != nil {
+			return err
+		}
+
+		notifyQuietly(ctx, s.db, func(ctx context.Context) error {
+			return s.notificationService.NotifyAssignment(ctx, document, disciplineListDocumentResult.ID, consolidatorIds)
+		})
+		return nil
 	})
 	if err != nil {
 		return dto.DisciplineListDocumentResponse{}, err
@@ -120,6 +143,150 @@ func (s *disciplineListDocumentService) Create(ctx context.Context, req dto.Disc
 	}, nil
 }
 
+// CreateBulk adds every document picked by the request to the discipline
+// group in one transaction. Documents already in the group are skipped.
+func (s *disciplineListDocumentService) CreateBulk(ctx context.Context, req dto.BulkDisciplineListDocumentRequest) (dto.BulkDisciplineListDocumentResponse, error) {
+	pkg, _, err := s.getPackagePermission(ctx, req.UserId)
+	if err != nil {
+		return dto.BulkDisciplineListDocumentResponse{}, err
+	}
+
+	if len(req.DocumentIDs) == 0 && req.Filter == nil {
+		return dto.BulkDisciplineListDocumentResponse{}, myerror.New("fill document_ids or filter", http.StatusBadRequest)
+	}
+
+	disciplineGroup, err := s.disciplineGroupRepository.GetByID(ctx, nil, req.DisciplineGroupID, "DisciplineGroupConsolidators")
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.BulkDisciplineListDocumentResponse{}, myerror.New("discipline group not found", http.StatusNotFound)
+		}
+		return dto.BulkDisciplineListDocumentResponse{}, err
+	}
+
+	if pkg != nil && pkg.ID != disciplineGroup.PackageID {
+		return dto.BulkDisciplineListDocumentResponse{}, myerror.New("you not allowed to this package", http.StatusUnauthorized)
+	}
+
+	consolidatorMap := make(map[string]bool)
+	for _, c := range disciplineGroup.DisciplineGroupConsolidators {
+		consolidatorMap[c.ID.String()] = true
+	}
+
+	for _, c := range req.Consolidators {
+		if !consolidatorMap[c.DisciplineGroupConsolidatorID] {
+			return dto.BulkDisciplineListDocumentResponse{}, myerror.New("consolidator "+c.DisciplineGroupConsolidatorID+" is not in this discipline group", http.StatusBadRequest)
+		}
+	}
+
+	for _, documentId := range req.DocumentIDs {
+		if _, err := uuid.Parse(documentId); err != nil {
+			return dto.BulkDisciplineListDocumentResponse{}, myerror.New("invalid document_id: "+documentId, http.StatusBadRequest)
+		}
+	}
+
+	var filter dto.DocumentFilter
+	if req.Filter != nil {
+		filter = *req.Filter
+	}
+	filter.DocumentIDs = req.DocumentIDs
+
+	documents, err := s.documentRepository.GetAllByFilter(ctx, nil, disciplineGroup.PackageID.String(), filter)
+	if err != nil {
+		return dto.BulkDisciplineListDocumentResponse{}, err
+	}
+
+	res := dto.BulkDisciplineListDocumentResponse{
+		Matched: len(documents),
+		Created: []dto.BulkDisciplineListDocumentItem{},
+		Skipped: []dto.BulkDisciplineListDocumentItem{},
+	}
+
+	matched := make(map[string]bool)
+	var documentIds []string
+	for _, document := range documents {
+		matched[document.ID.String()] = true
+		documentIds = append(documentIds, document.ID.String())
+	}
+
+	for _, documentId := range req.DocumentIDs {
+		if !matched[documentId] {
+			res.Skipped = append(res.Skipped, dto.BulkDisciplineListDocumentItem{
+				DocumentID: documentId,
+				Reason:     "document is not in this package or does not match the filter",
+			})
+		}
+	}
+
+	if len(documents) == 0 {
+		return res, nil
+	}
+
+	existingDlds, err := s.disciplineListDocumentRepository.GetAllByDocumentIDs(ctx, nil, disciplineGroup.ID.String(), documentIds)
+	if err != nil {
+		return dto.BulkDisciplineListDocumentResponse{}, err
+	}
+
+	existing := make(map[uuid.UUID]uuid.UUID)
+	for _, dld := range existingDlds {
+		existing[dld.DocumentID] = dld.ID
+	}
+
+	var consolidatorIds []string
+	for _, c := range req.Consolidators {
+		consolidatorIds = append(consolidatorIds, c.DisciplineGroupConsolidatorID)
+	}
+
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		for _, document := range documents {
+			if dldId, ok := existing[document.ID]; ok {
+				id := dldId.String()
+				res.Skipped = append(res.Skipped, dto.BulkDisciplineListDocumentItem{
+					DocumentID:               document.ID.String(),
+					CompanyDocumentNumber:    document.CompanyDocumentNumber,
+					DisciplineListDocumentID: &id,
+					Reason:                   "document is already in this discipline group",
+				})
+				continue
+			}
+
+			var consolidatorsInput []entity.DisciplineListDocumentConsolidator
+			for _, c := range req.Consolidators {
+				consolidatorsInput = append(consolidatorsInput, entity.DisciplineListDocumentConsolidator{
+					DisciplineGroupConsolidatorID: uuid.MustParse(c.DisciplineGroupConsolidatorID),
+				})
+			}
+
+			dld, err := s.disciplineListDocumentRepository.Create(ctx, nil, entity.DisciplineListDocument{
+				DisciplineGroupID: disciplineGroup.ID,
+				DocumentID:        document.ID,
+				PackageID:         disciplineGroup.PackageID,
+				Consolidators:     consolidatorsInput,
+			})
+			if err != nil {
+				return err
+			}
+
+			notifyQuietly(ctx, s.db, func(ctx context.Context) error {
+				return s.notificationService.NotifyAssignment(ctx, document, dld.ID, consolidatorIds)
+			})
+
+			id := dld.ID.String()
+			res.Created = append(res.Created, dto.BulkDisciplineListDocumentItem{
+				DocumentID:               document.ID.String(),
+				CompanyDocumentNumber:    document.CompanyDocumentNumber,
+				DisciplineListDocumentID: &id,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.BulkDisciplineListDocumentResponse{}, err
+	}
+
+	return res, nil
+}
+
 func (s *disciplineListDocumentService) GetById(ctx context.Context, id string) (dto.DisciplineListDocumentResponse, error) {
 	disciplineListDocument, err := s.disciplineListDocumentRepository.GetByID(ctx, nil, id, "Package", "Document", "Consolidators.DisciplineGroupConsolidator.User")
 	if err != nil {
@@ -148,6 +315,8 @@ func (s *disciplineListDocumentService) GetById(ctx context.Context, id string)
 			CompanyDocumentNumber:    disciplineListDocument.Document.CompanyDocumentNumber,
 			ContractorDocumentNumber: disciplineListDocument.Document.ContractorDocumentNumber,
 			DocumentTitle:            disciplineListDocument.Document.DocumentTitle,
+			Revision:                 disciplineListDocument.Document.Revision,
+			PageCount:                disciplineListDocument.Document.PageCount,
 			Discipline:               disciplineListDocument.Document.Discipline,
 			SubDiscipline:            disciplineListDocument.Document.SubDiscipline,
 			DocumentType:             disciplineListDocument.Document.DocumentType,
@@ -196,6 +365,8 @@ func (s *disciplineListDocumentService) GetAll(ctx context.Context, disciplineGr
 				CompanyDocumentNumber:    disciplineListDocument.Document.CompanyDocumentNumber,
 				ContractorDocumentNumber: disciplineListDocument.Document.ContractorDocumentNumber,
 				DocumentTitle:            disciplineListDocument.Document.DocumentTitle,
+				Revision:                 disciplineListDocument.Document.Revision,
+				PageCount:                disciplineListDocument.Document.PageCount,
 				Discipline:               disciplineListDocument.Document.Discipline,
 				SubDiscipline:            disciplineListDocument.Document.SubDiscipline,
 				DocumentType:             disciplineListDocument.Document.DocumentType,
@@ -251,32 +422,38 @@ func (s *disciplineListDocumentService) Update(ctx context.Context, req dto.Upda
 		}
 	}
 
-	if err := s.disciplineListDocumentConsolidatorRepository.DeleteBulk(ctx, nil, deletedConsolidators); err != nil {
-		return err
-	}
-
 	var newConsolidator []entity.DisciplineListDocumentConsolidator
+	var newConsolidatorIds []string
 	for _, c := range req.Consolidators {
 		if _, ok := consolidatorMap[c.DisciplineGroupConsolidatorID]; !ok {
 			newConsolidator = append(newConsolidator, entity.DisciplineListDocumentConsolidator{
 				DisciplineListDocumentID:      disciplineListDocument.ID,
 				DisciplineGroupConsolidatorID: uuid.MustParse(c.DisciplineGroupConsolidatorID),
 			})
+			newConsolidatorIds = append(newConsolidatorIds, c.DisciplineGroupConsolidatorID)
 		}
 	}
 
-	if err := s.disciplineListDocumentConsolidatorRepository.CreateBulk(ctx, nil, newConsolidator); err != nil {
-		return err
-	}
-
 	// set updated_by and updated_at
 	disciplineListDocument.UpdatedBy = uuid.MustParse(req.UserId)
 
-	if err := s.disciplineListDocumentRepository.Update(ctx, nil, disciplineListDocument); err != nil {
-		return err
-	}
+	return repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if err := s.disciplineListDocumentConsolidatorRepository.DeleteBulk(ctx, nil, deletedConsolidators); err != nil {
+			return err
+		}
+
+		if len(newConsolidator) > 0 {
+			if err := s.disciplineListDocumentConsolidatorRepository.CreateBulk(ctx, nil, newConsolidator); err != nil {
+				return err
+			}
+
+			notifyQuietly(ctx, s.db, func(ctx context.Context) error {
+				return s.notificationService.NotifyAssignment(ctx, document, disciplineListDocument.ID, newConsolidatorIds)
+			})
+		}
 
-	return nil
+		return s.disciplineListDocumentRepository.Update(ctx, nil, disciplineListDocument)
+	})
 }
 
 func (s *disciplineListDocumentService) Delete(ctx context.Context, userId, disciplineListDocumentId string) error {
@@ -294,17 +471,15 @@ func (s *disciplineListDocumentService) Delete(ctx context.Context, userId, disc
 		return myerror.New("you not allowed to this package", http.StatusUnauthorized)
 	}
 
-	if err := s.commentRepository.DeleteByDisciplineListDocumentID(ctx, nil, []string{disciplineListDocument.ID.String()}); err != nil {
-		return err
-	}
-
-	// mark who deleted
-	disciplineListDocument.DeletedBy = uuid.MustParse(userId)
-	if err = s.disciplineListDocumentRepository.Delete(ctx, nil, disciplineListDocument); err != nil {
-		return err
-	}
+	return repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if err := s.commentRepository.DeleteByDisciplineListDocumentID(ctx, nil, []string{disciplineListDocument.ID.String()}); err != nil {
+			return err
+		}
 
-	return nil
+		// mark who deleted
+		disciplineListDocument.DeletedBy = uuid.MustParse(userId)
+		return s.disciplineListDocumentRepository.Delete(ctx, nil, disciplineListDocument)
+	})
 }
 
 func (s *disciplineListDocumentService) GenerateExcel(ctx context.Context, userId, disciplineListDocumentId string) (*bytes.Buffer, string, error) {
@@ -314,7 +489,9 @@ func (s *disciplineListDocumentService) GenerateExcel(ctx context.Context, userI
 		"Consolidators.DisciplineGroupConsolidator.User",
 		"Comments.CommentReplies",
 		"Comments.User",
-		"Document")
+		"Comments.Category",
+		"Comments.Severity",
+		"Document.ReviewOutcomes")
 	if err != nil {
 		return nil, "", err
 	}
@@ -326,8 +503,8 @@ func (s *disciplineListDocumentService) GenerateExcel(ctx context.Context, userI
 
 	// Prepare data for Excel
 	var comments []mypdf.CommentRow
-	for i, c := range dld.Comments {
-		if c.CommentReplyID != nil {
+	for _, c := range dld.Comments {
+		if !c.InOfficialSet() {
 			continue
 		}
 
@@ -338,7 +515,7 @@ func (s *disciplineListDocumentService) GenerateExcel(ctx context.Context, userI
 
 		closeOutComments := "N/A"
 		for _, cr := range c.CommentReplies {
-			if cr.IsCloseOutComment {
+			if cr.IsCloseOutComment && !cr.IsInternal() {
 				closeOutComments = cr.Comment
 				break
 			}
@@ -350,8 +527,8 @@ func (s *disciplineListDocumentService) GenerateExcel(ctx context.Context, userI
 			docStatus = string(dld.Document.Status)
 		}
 		comments = append(comments, mypdf.CommentRow{
-			No:              fmt.Sprintf("%d", i+1),
-			Page:            c.Section,
+			No:              fmt.Sprintf("%d", len(comments)+1),
+			Page:            c.PageLabel(),
 			SMEInitial:      c.User.Name,
 			SMEComment:      c.Comment,
 			RefDocNo:        refDocNo,
@@ -359,6 +536,8 @@ func (s *disciplineListDocumentService) GenerateExcel(ctx context.Context, userI
 			DocStatus:       string(docStatus),
 			Status:          status,
 			SMECloseComment: closeOutComments,
+			Category:        c.Category.NameOf(),
+			Severity:        c.Severity.CodeOf(),
 		})
 	}
 
@@ -380,6 +559,7 @@ func (s *disciplineListDocumentService) GenerateExcel(ctx context.Context, userI
 			PackageInfoData: mypdf.PackageInfoData{
 				Package:           contractor.Package.Name,
 				ContractorInitial: contractor.Name,
+				ReviewOutcome:     reviewOutcomeLabel(dld.Document),
 			},
 			DisciplineSectionData: mypdf.DisciplineSectionData{
 				Discipline:   dld.DisciplineGroup.UserDiscipline,
@@ -389,7 +569,12 @@ func (s *disciplineListDocumentService) GenerateExcel(ctx context.Context, userI
 		},
 	}
 
-	excelBuffer, filename, err := mypdf.GenerateExcel(reqData)
+	tpl, err := loadReportTemplate(ctx, s.reportTemplateRepository, dld.PackageID.String())
+	if err != nil {
+		return nil, "", err
+	}
+
+	excelBuffer, filename, err := mypdf.GenerateExcel(reqData, tpl)
 	if err != nil {
 		return nil, "", err
 	}
@@ -397,6 +582,88 @@ func (s *disciplineListDocumentService) GenerateExcel(ctx context.Context, userI
 	return excelBuffer, filename, nil
 }
 
+func (s *disciplineListDocumentService) GenerateMarkupPDF(ctx context.Context, userId, disciplineListDocumentId string) (*bytes.Buffer, string, error) {
+	pkg, _, err := s.getPackagePermission(ctx, userId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dld, err := s.disciplineListDocumentRepository.GetByID(ctx, nil, disciplineListDocumentId,
+		"Comments.CommentReplies",
+		"Comments.User",
+		"Document")
+	if err != nil {
+		return nil, "", err
+	}
+
+	if pkg != nil && dld.PackageID != pkg.ID {
+		return nil, "", myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	if dld.Document == nil || dld.Document.DocumentUrl == nil {
+		return nil, "", myerror.New("document has no file to mark up", http.StatusBadRequest)
+	}
+
+	path, ok := utils.GetUploadedFilePath(*dld.Document.DocumentUrl)
+	if !ok {
+		return nil, "", myerror.New("document file is not stored on this server", http.StatusBadRequest)
+	}
+
+	source, err := os.Open(path)
+	if err != nil {
+		return nil, "", myerror.New("document file not found", http.StatusNotFound)
+	}
+	defer source.Close()
+
+	// numbering follows the comment resolution sheet so callouts match its rows
+	var callouts []mypdf.MarkupCallout
+	for _, c := range dld.Comments {
+		if !c.InOfficialSet() {
+			continue
+		}
+
+		status := "N/A"
+		if c.Status != nil {
+			status = string(*c.Status)
+		}
+
+		closeOutComments := ""
+		for _, cr := range c.CommentReplies {
+			if cr.IsCloseOutComment && !cr.IsInternal() {
+				closeOutComments = cr.Comment
+				break
+			}
+		}
+
+		author := "deleted user"
+		if c.User != nil {
+			author = c.User.Name
+		}
+
+		callout := mypdf.MarkupCallout{
+			No:              fmt.Sprintf("%d", len(callouts)+1),
+			Author:          author,
+			Comment:         c.Comment,
+			Status:          status,
+			CloseOutComment: closeOutComments,
+			Closed:          closeOutComments != "",
+		}
+		if anchor := c.ToAnchor(); anchor != nil {
+			callout.Page = anchor.Page
+			callout.X, callout.Y = anchor.X, anchor.Y
+			callout.Width, callout.Height = anchor.Width, anchor.Height
+		}
+
+		callouts = append(callouts, callout)
+	}
+
+	return mypdf.GenerateMarkup(mypdf.MarkupRequestData{
+		Title:    fmt.Sprintf("%s - %s", dld.Document.CompanyDocumentNumber, dld.Document.DocumentTitle),
+		Source:   source,
+		Callouts: callouts,
+	})
+}
+
 func (s *disciplineListDocumentService) getPackagePermission(ctx context.Context, userId string) (*entity.Package, entity.User, error) {
 	user, err := s.userRepository.GetById(ctx, nil, userId)
 	if err != nil {
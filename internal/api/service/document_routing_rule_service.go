@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	DocumentRoutingRuleService interface {
+		GetAll(ctx context.Context, userId, packageId string) ([]dto.DocumentRoutingRuleResponse, error)
+		Create(ctx context.Context, req dto.DocumentRoutingRuleRequest) (dto.DocumentRoutingRuleResponse, error)
+		Update(ctx context.Context, req dto.DocumentRoutingRuleRequest) (dto.DocumentRoutingRuleResponse, error)
+		Delete(ctx context.Context, userId, packageId, documentRoutingRuleId string) error
+	}
+
+	documentRoutingRuleService struct {
+		documentRoutingRuleRepository repository.DocumentRoutingRuleRepository
+		disciplineGroupRepository     repository.DisciplineGroupRepository
+		packageRepository             repository.PackageRepository
+		userRepository                repository.UserRepository
+		db                            *gorm.DB
+	}
+)
+
+func NewDocumentRoutingRule(documentRoutingRuleRepository repository.DocumentRoutingRuleRepository,
+	disciplineGroupRepository repository.DisciplineGroupRepository,
+	packageRepository repository.PackageRepository,
+	userRepository repository.UserRepository,
+	db *gorm.DB) DocumentRoutingRuleService {
+	return &documentRoutingRuleService{
+		documentRoutingRuleRepository: documentRoutingRuleRepository,
+		disciplineGroupRepository:     disciplineGroupRepository,
+		packageRepository:             packageRepository,
+		userRepository:                userRepository,
+		db:                            db,
+	}
+}
+
+func (s *documentRoutingRuleService) GetAll(ctx context.Context, userId, packageId string) ([]dto.DocumentRoutingRuleResponse, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.PackageID != nil && user.PackageID.String() != packageId {
+		return nil, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	documentRoutingRules, err := s.documentRoutingRuleRepository.GetAllByPackageID(ctx, nil, packageId, "DisciplineGroup")
+	if err != nil {
+		return nil, err
+	}
+
+	res := []dto.DocumentRoutingRuleResponse{}
+	for _, documentRoutingRule := range documentRoutingRules {
+		res = append(res, documentRoutingRuleResponse(documentRoutingRule))
+	}
+
+	return res, nil
+}
+
+func (s *documentRoutingRuleService) Create(ctx context.Context, req dto.DocumentRoutingRuleRequest) (dto.DocumentRoutingRuleResponse, error) {
+	pkg, err := s.packageRepository.GetByID(ctx, nil, req.PackageID)
+	if err != nil {
+		return dto.DocumentRoutingRuleResponse{}, err
+	}
+
+	documentRoutingRule := entity.DocumentRoutingRule{PackageID: pkg.ID}
+	if err := s.fill(ctx, &documentRoutingRule, req); err != nil {
+		return dto.DocumentRoutingRuleResponse{}, err
+	}
+
+	documentRoutingRule, err = s.documentRoutingRuleRepository.Create(ctx, nil, documentRoutingRule)
+	if err != nil {
+		return dto.DocumentRoutingRuleResponse{}, err
+	}
+
+	return s.getResponse(ctx, documentRoutingRule.ID.String())
+}
+
+func (s *documentRoutingRuleService) Update(ctx context.Context, req dto.DocumentRoutingRuleRequest) (dto.DocumentRoutingRuleResponse, error) {
+	documentRoutingRule, err := s.get(ctx, req.PackageID, req.ID)
+	if err != nil {
+		return dto.DocumentRoutingRuleResponse{}, err
+	}
+
+	if err := s.fill(ctx, &documentRoutingRule, req); err != nil {
+		return dto.DocumentRoutingRuleResponse{}, err
+	}
+
+	if _, err := s.documentRoutingRuleRepository.Update(ctx, nil, documentRoutingRule); err != nil {
+		return dto.DocumentRoutingRuleResponse{}, err
+	}
+
+	return s.getResponse(ctx, documentRoutingRule.ID.String())
+}
+
+func (s *documentRoutingRuleService) Delete(ctx context.Context, userId, packageId, documentRoutingRuleId string) error {
+	documentRoutingRule, err := s.get(ctx, packageId, documentRoutingRuleId)
+	if err != nil {
+		return err
+	}
+
+	// mark who deleted
+	documentRoutingRule.DeletedBy = uuid.MustParse(userId)
+	if err := s.documentRoutingRuleRepository.Delete(ctx, nil, documentRoutingRule); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *documentRoutingRuleService) fill(ctx context.Context, documentRoutingRule *entity.DocumentRoutingRule, req dto.DocumentRoutingRuleRequest) error {
+	disciplineGroup, err := s.disciplineGroupRepository.GetByID(ctx, nil, req.DisciplineGroupID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return myerror.New("discipline group not found", http.StatusNotFound)
+		}
+		return err
+	}
+
+	if disciplineGroup.PackageID != documentRoutingRule.PackageID {
+		return myerror.New("discipline group is not in this package", http.StatusBadRequest)
+	}
+
+	documentRoutingRule.Discipline = strings.TrimSpace(req.Discipline)
+	documentRoutingRule.DocumentType = nil
+	if req.DocumentType != nil && strings.TrimSpace(*req.DocumentType) != "" {
+		documentType := strings.TrimSpace(*req.DocumentType)
+		documentRoutingRule.DocumentType = &documentType
+	}
+	documentRoutingRule.DisciplineGroupID = disciplineGroup.ID
+	documentRoutingRule.UpdatedBy = uuid.MustParse(req.UserId)
+
+	return nil
+}
+
+func (s *documentRoutingRuleService) get(ctx context.Context, packageId, documentRoutingRuleId string) (entity.DocumentRoutingRule, error) {
+	documentRoutingRule, err := s.documentRoutingRuleRepository.GetByID(ctx, nil, documentRoutingRuleId)
+	if err != nil {
+		return entity.DocumentRoutingRule{}, err
+	}
+
+	if documentRoutingRule.PackageID.String() != packageId {
+		return entity.DocumentRoutingRule{}, myerror.New("routing rule not found", http.StatusNotFound)
+	}
+
+	return documentRoutingRule, nil
+}
+
+func (s *documentRoutingRuleService) getResponse(ctx context.Context, documentRoutingRuleId string) (dto.DocumentRoutingRuleResponse, error) {
+	documentRoutingRule, err := s.documentRoutingRuleRepository.GetByID(ctx, nil, documentRoutingRuleId, "DisciplineGroup")
+	if err != nil {
+		return dto.DocumentRoutingRuleResponse{}, err
+	}
+
+	return documentRoutingRuleResponse(documentRoutingRule), nil
+}
+
+func documentRoutingRuleResponse(documentRoutingRule entity.DocumentRoutingRule) dto.DocumentRoutingRuleResponse {
+	res := dto.DocumentRoutingRuleResponse{
+		ID:                documentRoutingRule.ID.String(),
+		Discipline:        documentRoutingRule.Discipline,
+		DocumentType:      documentRoutingRule.DocumentType,
+		DisciplineGroupID: documentRoutingRule.DisciplineGroupID.String(),
+		PackageID:         documentRoutingRule.PackageID.String(),
+	}
+
+	if documentRoutingRule.DisciplineGroup != nil {
+		res.ReviewFocus = documentRoutingRule.DisciplineGroup.ReviewFocus
+	}
+
+	return res
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/CRS-Project/crs-backend/internal/api/repository"
 	"github.com/CRS-Project/crs-backend/internal/dto"
@@ -31,6 +32,8 @@ type (
 		disciplineListDocumentRepository repository.DisciplineListDocumentRepository
 		packageRepository                repository.PackageRepository
 		userRepository                   repository.UserRepository
+		documentRoutingRuleRepository    repository.DocumentRoutingRuleRepository
+		slaService                       SLAService
 		db                               *gorm.DB ``
 	}
 )
@@ -39,12 +42,16 @@ func NewDocument(documentRepository repository.DocumentRepository,
 	disciplineListDocumentRepository repository.DisciplineListDocumentRepository,
 	packageRepository repository.PackageRepository,
 	userRepository repository.UserRepository,
+	documentRoutingRuleRepository repository.DocumentRoutingRuleRepository,
+	slaService SLAService,
 	db *gorm.DB) DocumentService {
 	return &documentService{
 		documentRepository:               documentRepository,
 		disciplineListDocumentRepository: disciplineListDocumentRepository,
 		packageRepository:                packageRepository,
 		userRepository:                   userRepository,
+		documentRoutingRuleRepository:    documentRoutingRuleRepository,
+		slaService:                       slaService,
 		db:                               db,
 	}
 }
@@ -70,7 +77,7 @@ func (s *documentService) Create(ctx context.Context, req dto.CreateDocumentRequ
 		contractor = user
 	}
 
-	documentResult, err := s.documentRepository.Create(ctx, nil, entity.Document{
+	document := entity.Document{
 		ContractorID:             contractor.ID,
 		DocumentUrl:              req.DocumentUrl,
 		PackageID:                pkg.ID,
@@ -80,12 +87,30 @@ func (s *documentService) Create(ctx context.Context, req dto.CreateDocumentRequ
 		CompanyDocumentNumber:    req.CompanyDocumentNumber,
 		ContractorDocumentNumber: req.ContractorDocumentNumber,
 		DocumentTitle:            req.DocumentTitle,
+		Revision:                 req.Revision,
+		PageCount:                req.PageCount,
 		Discipline:               req.Discipline,
 		SubDiscipline:            req.SubDiscipline,
 		DocumentType:             req.DocumentType,
 		DocumentCategory:         req.DocumentCategory,
 		DueDate:                  req.DueDate,
 		Status:                   entity.StatusDocument(req.Status),
+	}
+
+	// a due date given by hand wins over the SLA
+	if err := s.issue(ctx, *pkg, &document, false, req.DueDate == nil); err != nil {
+		return dto.DocumentDetailResponse{}, err
+	}
+
+	var documentResult entity.Document
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		var err error
+		documentResult, err = s.documentRepository.Create(ctx, nil, document)
+		if err != nil {
+			return err
+		}
+
+		return s.routeDocuments(ctx, pkg.ID, []entity.Document{documentResult})
 	})
 	if err != nil {
 		return dto.DocumentDetailResponse{}, err
@@ -100,6 +125,8 @@ func (s *documentService) Create(ctx context.Context, req dto.CreateDocumentRequ
 		CompanyDocumentNumber:    documentResult.CompanyDocumentNumber,
 		ContractorDocumentNumber: documentResult.ContractorDocumentNumber,
 		DocumentTitle:            documentResult.DocumentTitle,
+		Revision:                 documentResult.Revision,
+		PageCount:                documentResult.PageCount,
 		Discipline:               documentResult.Discipline,
 		SubDiscipline:            documentResult.SubDiscipline,
 		DocumentType:             documentResult.DocumentType,
@@ -225,34 +252,51 @@ func (s *documentService) CreateBulk(ctx context.Context, req dto.CreateBulkDocu
 		return nil, err
 	}
 
-	var documentsRes []dto.GetAllDocumentResponse
-	for _, document := range documents {
-		document, err = s.documentRepository.Create(ctx, nil, document)
-		if err != nil {
+	if len(documents) == 0 {
+		return nil, myerror.New("no valid data in sheets", http.StatusBadRequest)
+	}
+
+	for i := range documents {
+		if err := s.issue(ctx, *pkg, &documents[i], false, true); err != nil {
 			return nil, err
 		}
-
-		documentsRes = append(documentsRes, dto.GetAllDocumentResponse{
-			ID:                       document.ID.String(),
-			CompanyDocumentNumber:    document.CompanyDocumentNumber,
-			ContractorDocumentNumber: document.ContractorDocumentNumber,
-			DocumentTitle:            document.DocumentTitle,
-			DocumentType:             document.DocumentType,
-			DocumentCategory:         document.DocumentCategory,
-			Package:                  pkg.Name,
-			Status:                   string(document.Status),
-		})
 	}
 
-	if len(documentsRes) == 0 {
-		return nil, myerror.New("no valid data in sheets", http.StatusBadRequest)
+	// a sheet is imported completely or not at all
+	var documentsRes []dto.GetAllDocumentResponse
+	var created []entity.Document
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		for _, document := range documents {
+			document, err := s.documentRepository.Create(ctx, nil, document)
+			if err != nil {
+				return err
+			}
+
+			documentsRes = append(documentsRes, dto.GetAllDocumentResponse{
+				ID:                       document.ID.String(),
+				CompanyDocumentNumber:    document.CompanyDocumentNumber,
+				ContractorDocumentNumber: document.ContractorDocumentNumber,
+				DocumentTitle:            document.DocumentTitle,
+				DocumentType:             document.DocumentType,
+				DocumentCategory:         document.DocumentCategory,
+				Package:                  pkg.Name,
+				DueDate:                  document.DueDate,
+				Status:                   string(document.Status),
+			})
+			created = append(created, document)
+		}
+
+		return s.routeDocuments(ctx, pkg.ID, created)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return documentsRes, nil
 }
 
 func (s *documentService) GetAll(ctx context.Context, userId string, metaReq meta.Meta) ([]dto.GetAllDocumentResponse, meta.Meta, error) {
-	pkg, _, err := s.getPackagePermission(ctx, userId)
+	pkg, user, err := s.getPackagePermission(ctx, userId)
 	if err != nil {
 		return nil, meta.Meta{}, err
 	}
@@ -262,7 +306,7 @@ func (s *documentService) GetAll(ctx context.Context, userId string, metaReq met
 		pkgId = pkg.ID.String()
 	}
 
-	documents, metaRes, err := s.documentRepository.GetAll(ctx, nil, pkgId, metaReq, "Contractor", "Package", "DisciplineListDocuments.Comments")
+	documents, metaRes, err := s.documentRepository.GetAll(ctx, nil, pkgId, commentScope(user), metaReq, "Contractor", "Package", "DisciplineListDocuments.Comments", "ReviewOutcomes")
 	if err != nil {
 		return nil, meta.Meta{}, err
 	}
@@ -285,6 +329,7 @@ func (s *documentService) GetAll(ctx context.Context, userId string, metaReq met
 			DueDate:                  document.DueDate,
 			Status:                   string(document.Status),
 			TotalComments:            totalComment,
+			ReviewOutcome:            currentReviewOutcome(document),
 		})
 	}
 
@@ -292,7 +337,7 @@ func (s *documentService) GetAll(ctx context.Context, userId string, metaReq met
 }
 
 func (s *documentService) GetByID(ctx context.Context, documentId string) (dto.DocumentDetailResponse, error) {
-	document, err := s.documentRepository.GetByID(ctx, nil, documentId, "Contractor", "Package")
+	document, err := s.documentRepository.GetByID(ctx, nil, documentId, "Contractor", "Package", "ReviewOutcomes")
 	if err != nil {
 		return dto.DocumentDetailResponse{}, err
 	}
@@ -306,6 +351,8 @@ func (s *documentService) GetByID(ctx context.Context, documentId string) (dto.D
 		CompanyDocumentNumber:    document.CompanyDocumentNumber,
 		ContractorDocumentNumber: document.ContractorDocumentNumber,
 		DocumentTitle:            document.DocumentTitle,
+		Revision:                 document.Revision,
+		PageCount:                document.PageCount,
 		Discipline:               document.Discipline,
 		SubDiscipline:            document.SubDiscipline,
 		DocumentType:             document.DocumentType,
@@ -313,6 +360,7 @@ func (s *documentService) GetByID(ctx context.Context, documentId string) (dto.D
 		Package:                  document.Package.Name,
 		DueDate:                  document.DueDate,
 		Status:                   string(document.Status),
+		ReviewOutcome:            currentReviewOutcome(document),
 	}, nil
 }
 
@@ -331,6 +379,11 @@ func (s *documentService) Update(ctx context.Context, req dto.UpdateDocumentRequ
 		return dto.DocumentDetailResponse{}, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
 	}
 
+	// a new revision is a resubmission, its due date comes from the SLA
+	// unless it is changed by hand too
+	resubmitted := req.Revision != document.Revision
+	dueDateChanged := req.DueDate != nil && (document.DueDate == nil || !req.DueDate.Equal(*document.DueDate))
+
 	document.DocumentUrl = req.DocumentUrl
 	document.DocumentSerialNumber = req.DocumentSerialNumber
 	document.CTRNumber = req.CTRNumber
@@ -338,6 +391,8 @@ func (s *documentService) Update(ctx context.Context, req dto.UpdateDocumentRequ
 	document.CompanyDocumentNumber = req.CompanyDocumentNumber
 	document.ContractorDocumentNumber = req.ContractorDocumentNumber
 	document.DocumentTitle = req.DocumentTitle
+	document.Revision = req.Revision
+	document.PageCount = req.PageCount
 	document.Discipline = req.Discipline
 	document.SubDiscipline = req.SubDiscipline
 	document.DocumentType = req.DocumentType
@@ -346,6 +401,12 @@ func (s *documentService) Update(ctx context.Context, req dto.UpdateDocumentRequ
 	document.DueDate = req.DueDate
 	document.UpdatedBy = uuid.MustParse(req.UserID)
 
+	if resubmitted {
+		if err := s.issue(ctx, *document.Package, &document, true, !dueDateChanged); err != nil {
+			return dto.DocumentDetailResponse{}, err
+		}
+	}
+
 	document, err = s.documentRepository.Update(ctx, nil, document)
 	if err != nil {
 		return dto.DocumentDetailResponse{}, err
@@ -360,6 +421,8 @@ func (s *documentService) Update(ctx context.Context, req dto.UpdateDocumentRequ
 		CompanyDocumentNumber:    document.CompanyDocumentNumber,
 		ContractorDocumentNumber: document.ContractorDocumentNumber,
 		DocumentTitle:            document.DocumentTitle,
+		Revision:                 document.Revision,
+		PageCount:                document.PageCount,
 		Discipline:               document.Discipline,
 		SubDiscipline:            document.SubDiscipline,
 		DocumentType:             document.DocumentType,
@@ -397,6 +460,54 @@ func (s *documentService) Delete(ctx context.Context, userId, documentId string)
 	return nil
 }
 
+// routeDocuments adds new documents to the discipline groups picked by the
+// routing rules of the package
+func (s *documentService) routeDocuments(ctx context.Context, packageId uuid.UUID, documents []entity.Document) error {
+	documentRoutingRules, err := s.documentRoutingRuleRepository.GetAllByPackageID(ctx, nil, packageId.String(), "DisciplineGroup")
+	if err != nil {
+		return err
+	}
+
+	for _, document := range documents {
+		routed := make(map[uuid.UUID]bool)
+		for _, rule := range documentRoutingRules {
+			if rule.DisciplineGroup == nil || routed[rule.DisciplineGroupID] || !rule.Matches(document) {
+				continue
+			}
+			routed[rule.DisciplineGroupID] = true
+
+			if _, err := s.disciplineListDocumentRepository.Create(ctx, nil, entity.DisciplineListDocument{
+				DisciplineGroupID: rule.DisciplineGroupID,
+				DocumentID:        document.ID,
+				PackageID:         packageId,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// issue starts the review of the current revision of the document, the due
+// date is taken from the SLA of the package when withDueDate is set
+func (s *documentService) issue(ctx context.Context, pkg entity.Package, document *entity.Document, resubmission bool, withDueDate bool) error {
+	issuedAt := time.Now()
+	document.IssuedAt = &issuedAt
+
+	if !withDueDate {
+		return nil
+	}
+
+	dueDate, err := s.slaService.DueDate(ctx, pkg, document.Status.Purpose(), resubmission, issuedAt)
+	if err != nil {
+		return err
+	}
+	document.DueDate = &dueDate
+
+	return nil
+}
+
 func (s *documentService) getPackagePermission(ctx context.Context, userId string) (*entity.Package, entity.User, error) {
 	user, err := s.userRepository.GetById(ctx, nil, userId)
 	if err != nil {
@@ -414,3 +525,15 @@ func (s *documentService) getPackagePermission(ctx context.Context, userId strin
 
 	return &pkg, user, nil
 }
+
+// currentReviewOutcome is the outcome of the revision under review, the
+// review outcomes of the document must be loaded
+func currentReviewOutcome(document entity.Document) *dto.ReviewOutcomeCode {
+	reviewOutcome := document.CurrentReviewOutcome()
+	if reviewOutcome == nil {
+		return nil
+	}
+
+	code := reviewOutcomeCode(reviewOutcome.Code)
+	return &code
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"testing"
+
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/xuri/excelize/v2"
+)
+
+// documentSheet builds the upload of CreateBulk with one IFR document per
+// number, the documents start on the fourth row like in the template
+func documentSheet(t *testing.T, numbers ...string) *multipart.FileHeader {
+	t.Helper()
+
+	xlsx := excelize.NewFile()
+	sheet := xlsx.GetSheetList()[0]
+	for i, number := range numbers {
+		row := []any{"", "", "x", "", "", "", "CDN-" + number, "KDN-" + number, "Document " + number, "Mechanical", "", "", "Drawing", "", string(entity.StatusDocumentIFR)}
+		if err := xlsx.SetSheetRow(sheet, fmt.Sprintf("A%d", i+4), &row); err != nil {
+			t.Fatalf("write row: %v", err)
+		}
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file_sheet", "documents.xlsx")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if err := xlsx.Write(part); err != nil {
+		t.Fatalf("write sheet: %v", err)
+	}
+	form.Close()
+
+	parsed, err := multipart.NewReader(&body, form.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("read form: %v", err)
+	}
+	t.Cleanup(func() { parsed.RemoveAll() })
+
+	return parsed.File["file_sheet"][0]
+}
+
+func TestDocumentServiceCreateBulk(t *testing.T) {
+	createBulk := func(f *fakeFixture) error {
+		_, err := f.documentService().CreateBulk(context.Background(), dto.CreateBulkDocumentRequest{
+			UserID:    f.superAdmin.ID.String(),
+			PackageID: f.pkg.ID.String(),
+			FileSheet: documentSheet(t, "002", "003"),
+		})
+		return err
+	}
+
+	t.Run("rolls back", func(t *testing.T) {
+		f := newFakeFixture(t)
+		// both documents are saved before the first one fails to be routed
+		f.db.fail("DisciplineListDocumentRepository.Create", 1)
+
+		if err := createBulk(f); !errors.Is(err, errFakeWrite) {
+			t.Fatalf("CreateBulk() error = %v, want %v", err, errFakeWrite)
+		}
+		f.assertWrites(t)
+	})
+
+	t.Run("commits", func(t *testing.T) {
+		f := newFakeFixture(t)
+
+		if err := createBulk(f); err != nil {
+			t.Fatalf("CreateBulk() error = %v", err)
+		}
+		f.assertWrites(t,
+			"DocumentRepository.Create",
+			"DocumentRepository.Create",
+			"DisciplineListDocumentRepository.Create",
+			"DisciplineListDocumentRepository.Create")
+	})
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var errFakeWrite = errors.New("fake write failed")
+
+// fakeDB stands in for postgres in the service tests. The fake repositories
+// log their writes on it, gorm drives the transactions through the driver
+// below and a rollback drops what was logged since the transaction or the
+// savepoint began.
+type fakeDB struct {
+	writes     []fakeWrite
+	begin      int
+	savepoints map[string]int
+	calls      map[string]int
+	failures   map[string]int
+}
+
+type fakeWrite struct {
+	method string
+	row    any
+}
+
+func newFakeDB(t *testing.T) (*fakeDB, *gorm.DB) {
+	t.Helper()
+
+	db := &fakeDB{savepoints: map[string]int{}, calls: map[string]int{}, failures: map[string]int{}}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(fakeConnector{db})}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open fake db: %v", err)
+	}
+
+	return db, gormDB
+}
+
+// fail makes the call-th call of the repository method, named like
+// "CommentRepository.Create", return errFakeWrite
+func (db *fakeDB) fail(method string, call int) {
+	db.failures[method] = call
+}
+
+func (db *fakeDB) write(method string, row any) error {
+	db.calls[method]++
+	if db.calls[method] == db.failures[method] {
+		return errFakeWrite
+	}
+
+	db.writes = append(db.writes, fakeWrite{method: method, row: row})
+	return nil
+}
+
+// written lists the rows of the method's writes that were kept
+func (db *fakeDB) written(method string) []any {
+	var rows []any
+	for _, write := range db.writes {
+		if write.method == method {
+			rows = append(rows, write.row)
+		}
+	}
+
+	return rows
+}
+
+type fakeConnector struct {
+	db *fakeDB
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return c, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+func (c fakeConnector) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("fake db: unexpected query %q", query)
+}
+
+func (c fakeConnector) Close() error {
+	return nil
+}
+
+func (c fakeConnector) Begin() (driver.Tx, error) {
+	c.db.begin = len(c.db.writes)
+	return c, nil
+}
+
+func (c fakeConnector) Commit() error {
+	return nil
+}
+
+func (c fakeConnector) Rollback() error {
+	c.db.writes = c.db.writes[:c.db.begin]
+	return nil
+}
+
+// ExecContext only knows savepoints, any other query means a repository
+// was not faked
+func (c fakeConnector) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if name, ok := strings.CutPrefix(query, "SAVEPOINT "); ok {
+		c.db.savepoints[name] = len(c.db.writes)
+	} else if name, ok := strings.CutPrefix(query, "ROLLBACK TO SAVEPOINT "); ok {
+		c.db.writes = c.db.writes[:c.db.savepoints[name]]
+	} else {
+		return nil, fmt.Errorf("fake db: unexpected query %q", query)
+	}
+
+	return driver.RowsAffected(0), nil
+}
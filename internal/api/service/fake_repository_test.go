@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeFixture is a package with a contractor and a reviewer, and a
+// discipline group with one document carrying one comment of the reviewer.
+// A second package has no members. The fake repositories read these rows
+// and log their writes on db.
+type fakeFixture struct {
+	db     *fakeDB
+	gormDB *gorm.DB
+
+	pkg                    entity.Package
+	otherPkg               entity.Package
+	superAdmin             entity.User
+	contractor             entity.User
+	reviewer               entity.User
+	disciplineGroup        entity.DisciplineGroup
+	consolidator           entity.DisciplineGroupConsolidator
+	document               entity.Document
+	disciplineListDocument entity.DisciplineListDocument
+	comment                entity.Comment
+	commentClasses         []entity.CommentClass
+}
+
+func newFakeFixture(t *testing.T) *fakeFixture {
+	t.Helper()
+
+	f := &fakeFixture{}
+	f.db, f.gormDB = newFakeDB(t)
+
+	f.pkg = entity.Package{ID: uuid.New(), Name: "Package A"}
+	f.otherPkg = entity.Package{ID: uuid.New(), Name: "Package B"}
+	f.superAdmin = entity.User{ID: uuid.New(), Name: "Admin", Role: entity.RoleSuperAdmin}
+	f.contractor = entity.User{ID: uuid.New(), Name: "Contractor", Role: entity.RoleContractor, PackageID: &f.pkg.ID}
+	f.reviewer = entity.User{ID: uuid.New(), Name: "Reviewer", Role: entity.RoleReviewer, PackageID: &f.pkg.ID}
+	f.disciplineGroup = entity.DisciplineGroup{ID: uuid.New(), ReviewFocus: "Piping", UserDiscipline: "Mechanical", PackageID: f.pkg.ID}
+	f.consolidator = entity.DisciplineGroupConsolidator{ID: uuid.New(), UserID: f.reviewer.ID, DisciplineGroupID: f.disciplineGroup.ID}
+	f.document = entity.Document{
+		ID:                    uuid.New(),
+		CompanyDocumentNumber: "CDN-001",
+		DocumentTitle:         "Piping layout",
+		Discipline:            "Mechanical",
+		Status:                entity.StatusDocumentIFR,
+		ContractorID:          f.contractor.ID,
+		PackageID:             f.pkg.ID,
+	}
+	f.disciplineListDocument = entity.DisciplineListDocument{ID: uuid.New(), DocumentID: f.document.ID, DisciplineGroupID: f.disciplineGroup.ID, PackageID: f.pkg.ID}
+	f.comment = entity.Comment{ID: uuid.New(), Comment: "Pipe rack clearance is not shown", Stage: entity.CommentStageOfficial, DisciplineListDocumentID: f.disciplineListDocument.ID, UserID: f.reviewer.ID}
+
+	return f
+}
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	*fakeFixture
+}
+
+func (r fakeUserRepository) GetById(_ context.Context, _ *gorm.DB, userId string, _ ...string) (entity.User, error) {
+	for _, user := range []entity.User{r.superAdmin, r.contractor, r.reviewer} {
+		if user.ID.String() == userId {
+			return user, nil
+		}
+	}
+
+	return entity.User{}, gorm.ErrRecordNotFound
+}
+
+func (r fakeUserRepository) GetContractorByPackage(_ context.Context, _ *gorm.DB, packageId string, _ ...string) (entity.User, error) {
+	if packageId != r.pkg.ID.String() {
+		return entity.User{}, gorm.ErrRecordNotFound
+	}
+
+	return r.contractor, nil
+}
+
+type fakePackageRepository struct {
+	repository.PackageRepository
+	*fakeFixture
+}
+
+func (r fakePackageRepository) GetByID(_ context.Context, _ *gorm.DB, pkgID string, _ ...string) (entity.Package, error) {
+	for _, pkg := range []entity.Package{r.pkg, r.otherPkg} {
+		if pkg.ID.String() == pkgID {
+			return pkg, nil
+		}
+	}
+
+	return entity.Package{}, gorm.ErrRecordNotFound
+}
+
+type fakeDocumentRepository struct {
+	repository.DocumentRepository
+	*fakeFixture
+}
+
+func (r fakeDocumentRepository) Create(_ context.Context, _ *gorm.DB, document entity.Document, _ ...string) (entity.Document, error) {
+	document.ID = uuid.New()
+	return document, r.db.write("DocumentRepository.Create", document)
+}
+
+type fakeDocumentRoutingRuleRepository struct {
+	repository.DocumentRoutingRuleRepository
+	*fakeFixture
+}
+
+// GetAllByPackageID routes the mechanical documents of the package to the
+// discipline group
+func (r fakeDocumentRoutingRuleRepository) GetAllByPackageID(_ context.Context, _ *gorm.DB, packageId string, _ ...string) ([]entity.DocumentRoutingRule, error) {
+	if packageId != r.pkg.ID.String() {
+		return nil, nil
+	}
+
+	return []entity.DocumentRoutingRule{{
+		ID:                uuid.New(),
+		Discipline:        "Mechanical",
+		PackageID:         r.pkg.ID,
+		DisciplineGroupID: r.disciplineGroup.ID,
+		DisciplineGroup:   &r.disciplineGroup,
+	}}, nil
+}
+
+type fakeDisciplineGroupRepository struct {
+	repository.DisciplineGroupRepository
+	*fakeFixture
+}
+
+func (r fakeDisciplineGroupRepository) GetByID(_ context.Context, _ *gorm.DB, disciplineGroupID string, _ ...string) (entity.DisciplineGroup, error) {
+	if disciplineGroupID != r.disciplineGroup.ID.String() {
+		return entity.DisciplineGroup{}, gorm.ErrRecordNotFound
+	}
+
+	disciplineGroup := r.disciplineGroup
+	disciplineGroup.DisciplineListDocuments = []entity.DisciplineListDocument{r.disciplineListDocument}
+	return disciplineGroup, nil
+}
+
+func (r fakeDisciplineGroupRepository) Statistic(_ context.Context, _ *gorm.DB, _ string, _ repository.CommentScope) (dto.DisciplineGroupStatistic, error) {
+	return dto.DisciplineGroupStatistic{}, nil
+}
+
+func (r fakeDisciplineGroupRepository) Update(_ context.Context, _ *gorm.DB, disciplineGroup entity.DisciplineGroup, _ ...string) error {
+	return r.db.write("DisciplineGroupRepository.Update", disciplineGroup)
+}
+
+func (r fakeDisciplineGroupRepository) Delete(_ context.Context, _ *gorm.DB, disciplineGroup entity.DisciplineGroup, _ ...string) error {
+	return r.db.write("DisciplineGroupRepository.Delete", disciplineGroup)
+}
+
+type fakeDisciplineGroupConsolidatorRepository struct {
+	repository.DisciplineGroupConsolidatorRepository
+	*fakeFixture
+}
+
+func (r fakeDisciplineGroupConsolidatorRepository) GetAllConsolidator(_ context.Context, _ *gorm.DB, _, _ string, _ ...string) ([]entity.DisciplineGroupConsolidator, error) {
+	return []entity.DisciplineGroupConsolidator{r.consolidator}, nil
+}
+
+func (r fakeDisciplineGroupConsolidatorRepository) CreateBulk(_ context.Context, _ *gorm.DB, disciplineGroupConsolidators []entity.DisciplineGroupConsolidator, _ ...string) error {
+	return r.db.write("DisciplineGroupConsolidatorRepository.CreateBulk", disciplineGroupConsolidators)
+}
+
+func (r fakeDisciplineGroupConsolidatorRepository) DeleteByID(_ context.Context, _ *gorm.DB, id string) error {
+	return r.db.write("DisciplineGroupConsolidatorRepository.DeleteByID", id)
+}
+
+type fakeDisciplineListDocumentRepository struct {
+	repository.DisciplineListDocumentRepository
+	*fakeFixture
+}
+
+func (r fakeDisciplineListDocumentRepository) Create(_ context.Context, _ *gorm.DB, disciplineListDocument entity.DisciplineListDocument, _ ...string) (entity.DisciplineListDocument, error) {
+	disciplineListDocument.ID = uuid.New()
+	return disciplineListDocument, r.db.write("DisciplineListDocumentRepository.Create", disciplineListDocument)
+}
+
+func (r fakeDisciplineListDocumentRepository) GetByID(_ context.Context, _ *gorm.DB, disciplineListDocumentID string, _ ...string) (entity.DisciplineListDocument, error) {
+	if disciplineListDocumentID != r.disciplineListDocument.ID.String() {
+		return entity.DisciplineListDocument{}, gorm.ErrRecordNotFound
+	}
+
+	disciplineListDocument := r.disciplineListDocument
+	disciplineListDocument.Document = &r.document
+	return disciplineListDocument, nil
+}
+
+func (r fakeDisciplineListDocumentRepository) DeleteByDisciplineGroupID(_ context.Context, _ *gorm.DB, disciplineGroupID string, _ ...string) error {
+	return r.db.write("DisciplineListDocumentRepository.DeleteByDisciplineGroupID", disciplineGroupID)
+}
+
+type fakeDisciplineListDocumentConsolidatorRepository struct {
+	repository.DisciplineListDocumentConsolidatorRepository
+	*fakeFixture
+}
+
+func (r fakeDisciplineListDocumentConsolidatorRepository) DeleteByDisciplineListDocumentID(_ context.Context, _ *gorm.DB, disciplineListDocumentID []string) error {
+	return r.db.write("DisciplineListDocumentConsolidatorRepository.DeleteByDisciplineListDocumentID", disciplineListDocumentID)
+}
+
+func (r fakeDisciplineListDocumentConsolidatorRepository) DeleteByDisciplineGroupConsolidatorID(_ context.Context, _ *gorm.DB, disciplineGroupIDs []string) error {
+	return r.db.write("DisciplineListDocumentConsolidatorRepository.DeleteByDisciplineGroupConsolidatorID", disciplineGroupIDs)
+}
+
+type fakeCommentRepository struct {
+	repository.CommentRepository
+	*fakeFixture
+}
+
+func (r fakeCommentRepository) Create(_ context.Context, _ *gorm.DB, comment entity.Comment, _ ...string) (entity.Comment, error) {
+	comment.ID = uuid.New()
+	return comment, r.db.write("CommentRepository.Create", comment)
+}
+
+func (r fakeCommentRepository) GetByID(_ context.Context, _ *gorm.DB, commentID string, _ ...string) (entity.Comment, error) {
+	if commentID != r.comment.ID.String() {
+		return entity.Comment{}, gorm.ErrRecordNotFound
+	}
+
+	comment := r.comment
+	comment.User = &r.reviewer
+	return comment, nil
+}
+
+func (r fakeCommentRepository) Update(_ context.Context, _ *gorm.DB, comment entity.Comment, _ ...string) error {
+	return r.db.write("CommentRepository.Update", comment)
+}
+
+func (r fakeCommentRepository) DeleteByDisciplineListDocumentID(_ context.Context, _ *gorm.DB, disciplineListDocumentID []string) error {
+	return r.db.write("CommentRepository.DeleteByDisciplineListDocumentID", disciplineListDocumentID)
+}
+
+// fakeStatisticRepository answers with empty statistics, or the cards and
+// responses set per package, and logs the package and the comment scope of
+// every read
+type fakeStatisticRepository struct {
+	repository.StatisticRepository
+	reads     []string
+	scopes    []repository.CommentScope
+	cards     map[string]dto.StatisticUserCard
+	responses map[string][]dto.StatisticSLAResponse
+}
+
+func (r *fakeStatisticRepository) readComments(packageId string, scope repository.CommentScope) {
+	r.reads = append(r.reads, packageId)
+	r.scopes = append(r.scopes, scope)
+}
+
+func (r *fakeStatisticRepository) GetAOCAndCommentChart(_ context.Context, _ *gorm.DB, packageId string, scope repository.CommentScope, _ dto.StatisticSeriesFilter) ([]dto.StatisticAOCAndCommentChart, error) {
+	r.readComments(packageId, scope)
+	return nil, nil
+}
+
+func (r *fakeStatisticRepository) GetCommentChart(_ context.Context, _ *gorm.DB, packageId string, scope repository.CommentScope, _ dto.StatisticCommentChartFilter) ([]dto.StatisticCommentChart, error) {
+	r.readComments(packageId, scope)
+	return nil, nil
+}
+
+func (r *fakeStatisticRepository) GetCommentCard(_ context.Context, _ *gorm.DB, packageId string, scope repository.CommentScope) (dto.StatisticAOCAndCommentCard, error) {
+	r.readComments(packageId, scope)
+	return dto.StatisticAOCAndCommentCard{}, nil
+}
+
+func (r *fakeStatisticRepository) GetUserCard(_ context.Context, _ *gorm.DB, packageId, _ string) (dto.StatisticUserCard, error) {
+	r.reads = append(r.reads, packageId)
+	return r.cards[packageId], nil
+}
+
+func (r *fakeStatisticRepository) GetCommentUserChart(_ context.Context, _ *gorm.DB, packageId string, scope repository.CommentScope) ([]dto.StatisticCommentUsersChart, error) {
+	r.readComments(packageId, scope)
+	return nil, nil
+}
+
+func (r *fakeStatisticRepository) GetCommentUserData(_ context.Context, _ *gorm.DB, packageId string, scope repository.CommentScope, metaReq meta.Meta) ([]dto.StatisticCommentUsersData, meta.Meta, error) {
+	r.readComments(packageId, scope)
+	return nil, metaReq, nil
+}
+
+func (r *fakeStatisticRepository) GetCommentClassChart(_ context.Context, _ *gorm.DB, packageId string, scope repository.CommentScope, _ entity.CommentClassKind) ([]dto.StatisticCommentClassChart, error) {
+	r.readComments(packageId, scope)
+	return nil, nil
+}
+
+func (r *fakeStatisticRepository) GetReviewOutcomeChart(_ context.Context, _ *gorm.DB, packageId string) ([]dto.StatisticReviewOutcomeChart, error) {
+	r.reads = append(r.reads, packageId)
+	return nil, nil
+}
+
+func (r *fakeStatisticRepository) GetSLADisciplineGroupResponses(_ context.Context, _ *gorm.DB, packageId string) ([]dto.StatisticSLAResponse, error) {
+	r.reads = append(r.reads, packageId)
+	return nil, nil
+}
+
+func (r *fakeStatisticRepository) GetSLAReviewerResponses(_ context.Context, _ *gorm.DB, packageId string) ([]dto.StatisticSLAResponse, error) {
+	r.reads = append(r.reads, packageId)
+	return r.responses[packageId], nil
+}
+
+type fakeCommentClassRepository struct {
+	repository.CommentClassRepository
+	*fakeFixture
+}
+
+func (r fakeCommentClassRepository) GetAllByPackageID(_ context.Context, _ *gorm.DB, packageId string, _ ...string) ([]entity.CommentClass, error) {
+	if packageId != r.pkg.ID.String() {
+		return nil, nil
+	}
+
+	return r.commentClasses, nil
+}
+
+type fakeNotificationService struct {
+	NotificationService
+	*fakeFixture
+}
+
+func (s fakeNotificationService) Notify(_ context.Context, recipients []uuid.UUID, _ entity.Notification) error {
+	return s.db.write("NotificationService.Notify", recipients)
+}
+
+// fakeSLAService gives every document two weeks
+type fakeSLAService struct {
+	SLAService
+}
+
+func (fakeSLAService) DueDate(_ context.Context, _ entity.Package, _ entity.TransmittalPurpose, _ bool, issuedAt time.Time) (time.Time, error) {
+	return issuedAt.AddDate(0, 0, 14), nil
+}
+
+func (f *fakeFixture) disciplineGroupService() DisciplineGroupService {
+	return NewDisciplineGroup(
+		fakeDisciplineGroupRepository{fakeFixture: f},
+		fakeDisciplineGroupConsolidatorRepository{fakeFixture: f},
+		fakeDisciplineListDocumentRepository{fakeFixture: f},
+		fakeDisciplineListDocumentConsolidatorRepository{fakeFixture: f},
+		fakePackageRepository{fakeFixture: f},
+		fakeCommentRepository{fakeFixture: f},
+		fakeUserRepository{fakeFixture: f},
+		nil,
+		nil,
+		f.gormDB)
+}
+
+func (f *fakeFixture) commentService() CommentService {
+	return NewComment(
+		fakeCommentRepository{fakeFixture: f},
+		fakeCommentClassRepository{fakeFixture: f},
+		fakeDocumentRepository{fakeFixture: f},
+		fakeDisciplineListDocumentRepository{fakeFixture: f},
+		fakeUserRepository{fakeFixture: f},
+		nil,
+		fakeNotificationService{fakeFixture: f},
+		f.gormDB)
+}
+
+func (f *fakeFixture) documentService() DocumentService {
+	return NewDocument(
+		fakeDocumentRepository{fakeFixture: f},
+		fakeDisciplineListDocumentRepository{fakeFixture: f},
+		fakePackageRepository{fakeFixture: f},
+		fakeUserRepository{fakeFixture: f},
+		fakeDocumentRoutingRuleRepository{fakeFixture: f},
+		fakeSLAService{},
+		f.gormDB)
+}
+
+func (f *fakeFixture) statisticService(statistics *fakeStatisticRepository) StatisticService {
+	return NewStatistic(
+		statistics,
+		nil,
+		nil,
+		nil,
+		fakeUserRepository{fakeFixture: f},
+		fakePackageRepository{fakeFixture: f},
+		f.gormDB)
+}
+
+// assertWrites fails unless the kept writes are exactly the methods, in order
+func (f *fakeFixture) assertWrites(t *testing.T, methods ...string) {
+	t.Helper()
+
+	var got []string
+	for _, write := range f.db.writes {
+		got = append(got, write.method)
+	}
+
+	if !slices.Equal(got, methods) {
+		t.Errorf("writes = %v, want %v", got, methods)
+	}
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	mylog "github.com/CRS-Project/crs-backend/internal/pkg/logger"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultJobWorkers        = 2
+	defaultJobRetentionHours = 24
+	jobQueueSize             = 256
+	jobPollInterval          = 30 * time.Second
+	jobCleanupInterval       = time.Hour
+	jobArtifactDir           = "jobs"
+)
+
+var contentTypeByExtension = map[string]string{
+	".pdf":  "application/pdf",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".csv":  "text/csv",
+	".json": "application/json",
+}
+
+type (
+	// JobHandler describes one kind of background work. Scope resolves the
+	// package a job touches so it can be checked against the caller, and Run
+	// does the work. Run may return a nil buffer when there is no artifact.
+	JobHandler struct {
+		Scope func(ctx context.Context, params map[string]string) (*uuid.UUID, error)
+		Run   func(ctx context.Context, job entity.Job, progress func(int)) (*bytes.Buffer, string, error)
+	}
+
+	JobService interface {
+		Register(jobType entity.JobType, handler JobHandler)
+		Start()
+		Submit(ctx context.Context, req dto.JobRequest) (dto.JobResponse, error)
+		GetByID(ctx context.Context, userId, jobId string) (dto.JobResponse, error)
+		GetArtifact(ctx context.Context, userId, jobId string) (dto.JobArtifact, error)
+	}
+
+	jobService struct {
+		jobRepository  repository.JobRepository
+		userRepository repository.UserRepository
+		db             *gorm.DB
+
+		mu        sync.RWMutex
+		handlers  map[entity.JobType]JobHandler
+		queue     chan string
+		workers   int
+		retention time.Duration
+		startOnce sync.Once
+	}
+)
+
+func NewJob(jobRepository repository.JobRepository, userRepository repository.UserRepository, db *gorm.DB) JobService {
+	workers := defaultJobWorkers
+	if v, err := strconv.Atoi(os.Getenv("JOB_WORKERS")); err == nil && v > 0 {
+		workers = v
+	}
+
+	retentionHours := defaultJobRetentionHours
+	if v, err := strconv.Atoi(os.Getenv("JOB_RETENTION_HOURS")); err == nil && v > 0 {
+		retentionHours = v
+	}
+
+	return &jobService{
+		jobRepository:  jobRepository,
+		userRepository: userRepository,
+		db:             db,
+		handlers:       make(map[entity.JobType]JobHandler),
+		queue:          make(chan string, jobQueueSize),
+		workers:        workers,
+		retention:      time.Duration(retentionHours) * time.Hour,
+	}
+}
+
+func (s *jobService) Register(jobType entity.JobType, handler JobHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[jobType] = handler
+}
+
+// Start launches the workers and the retention cleanup. Jobs left running by
+// a previous process are queued again.
+func (s *jobService) Start() {
+	s.startOnce.Do(func() {
+		ctx := context.Background()
+		if err := s.jobRepository.ResetRunning(ctx, nil); err != nil {
+			mylog.Errorln("failed to reset running jobs:", err)
+		}
+
+		for i := 0; i < s.workers; i++ {
+			go s.work()
+		}
+
+		go s.poll()
+		go s.cleanup()
+	})
+}
+
+func (s *jobService) Submit(ctx context.Context, req dto.JobRequest) (dto.JobResponse, error) {
+	jobType := entity.JobType(req.Type)
+	handler, ok := s.handler(jobType)
+	if !ok {
+		return dto.JobResponse{}, myerror.New(fmt.Sprintf("unknown job type %s", req.Type), http.StatusBadRequest)
+	}
+
+	user, err := s.userRepository.GetById(ctx, nil, req.UserId)
+	if err != nil {
+		return dto.JobResponse{}, err
+	}
+
+	var packageId *uuid.UUID
+	if handler.Scope != nil {
+		packageId, err = handler.Scope(ctx, req.Params)
+		if err != nil {
+			return dto.JobResponse{}, err
+		}
+	}
+
+	if user.PackageID != nil && packageId != nil && *user.PackageID != *packageId {
+		return dto.JobResponse{}, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	dedupKey := jobDedupKey(jobType, req.Params)
+	existing, err := s.jobRepository.GetActiveByDedupKey(ctx, nil, dedupKey)
+	if err == nil {
+		res := jobResponse(existing)
+		res.IsDuplicate = true
+		return res, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.JobResponse{}, err
+	}
+
+	job, err := s.jobRepository.Create(ctx, nil, entity.Job{
+		Type:      jobType,
+		Status:    entity.JobStatusPending,
+		Params:    req.Params,
+		DedupKey:  dedupKey,
+		PackageID: packageId,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		// a concurrent request may have won the unique index on the key
+		if existing, findErr := s.jobRepository.GetActiveByDedupKey(ctx, nil, dedupKey); findErr == nil {
+			res := jobResponse(existing)
+			res.IsDuplicate = true
+			return res, nil
+		}
+		return dto.JobResponse{}, err
+	}
+
+	s.enqueue(job.ID.String())
+
+	return jobResponse(job), nil
+}
+
+func (s *jobService) GetByID(ctx context.Context, userId, jobId string) (dto.JobResponse, error) {
+	job, err := s.getPermittedJob(ctx, userId, jobId)
+	if err != nil {
+		return dto.JobResponse{}, err
+	}
+
+	return jobResponse(job), nil
+}
+
+func (s *jobService) GetArtifact(ctx context.Context, userId, jobId string) (dto.JobArtifact, error) {
+	job, err := s.getPermittedJob(ctx, userId, jobId)
+	if err != nil {
+		return dto.JobArtifact{}, err
+	}
+
+	if job.Status != entity.JobStatusSucceeded {
+		return dto.JobArtifact{}, myerror.New("job has not finished yet", http.StatusConflict)
+	}
+
+	if job.ArtifactPath == nil {
+		return dto.JobArtifact{}, myerror.New("job has no artifact", http.StatusNotFound)
+	}
+
+	if _, err := os.Stat(*job.ArtifactPath); err != nil {
+		return dto.JobArtifact{}, myerror.New("artifact is no longer available", http.StatusGone)
+	}
+
+	return dto.JobArtifact{
+		Path:        *job.ArtifactPath,
+		Filename:    *job.ArtifactName,
+		ContentType: *job.ArtifactContentType,
+	}, nil
+}
+
+// getPermittedJob allows the owner, super admins and members of the job's
+// package, since deduplicated jobs are shared between requesters.
+func (s *jobService) getPermittedJob(ctx context.Context, userId, jobId string) (entity.Job, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return entity.Job{}, err
+	}
+
+	job, err := s.jobRepository.GetByID(ctx, nil, jobId)
+	if err != nil {
+		return entity.Job{}, err
+	}
+
+	if job.UserID == user.ID || user.PackageID == nil {
+		return job, nil
+	}
+
+	if job.PackageID != nil && *job.PackageID == *user.PackageID {
+		return job, nil
+	}
+
+	return entity.Job{}, myerror.New("you don't have permission for this job", http.StatusUnauthorized)
+}
+
+func (s *jobService) handler(jobType entity.JobType) (JobHandler, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	handler, ok := s.handlers[jobType]
+	return handler, ok
+}
+
+func (s *jobService) enqueue(jobId string) {
+	select {
+	case s.queue <- jobId:
+	default:
+		// the poller picks it up once the queue drains
+		mylog.Infof("job queue is full, %s stays pending", jobId)
+	}
+}
+
+func (s *jobService) work() {
+	for jobId := range s.queue {
+		s.run(jobId)
+	}
+}
+
+func (s *jobService) poll() {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		jobs, err := s.jobRepository.GetAllPending(context.Background(), nil)
+		if err != nil {
+			mylog.Errorln("failed to get pending jobs:", err)
+			continue
+		}
+
+		for _, job := range jobs {
+			s.enqueue(job.ID.String())
+		}
+	}
+}
+
+func (s *jobService) cleanup() {
+	ticker := time.NewTicker(jobCleanupInterval)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		ctx := context.Background()
+		jobs, err := s.jobRepository.GetAllExpired(ctx, nil, time.Now())
+		if err != nil {
+			mylog.Errorln("failed to get expired jobs:", err)
+			continue
+		}
+
+		for _, job := range jobs {
+			if job.ArtifactPath != nil {
+				if err := os.Remove(*job.ArtifactPath); err != nil && !os.IsNotExist(err) {
+					mylog.Errorln("failed to remove job artifact:", err)
+					continue
+				}
+			}
+
+			if err := s.jobRepository.Delete(ctx, nil, job); err != nil {
+				mylog.Errorln("failed to delete expired job:", err)
+			}
+		}
+	}
+}
+
+func (s *jobService) run(jobId string) {
+	ctx := context.Background()
+
+	claimed, err := s.jobRepository.Claim(ctx, nil, jobId, time.Now())
+	if err != nil {
+		mylog.Errorln("failed to claim job:", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	job, err := s.jobRepository.GetByID(ctx, nil, jobId)
+	if err != nil {
+		mylog.Errorln("failed to get job:", err)
+		return
+	}
+
+	progress := func(p int) {
+		if p < 0 || p > 100 {
+			return
+		}
+		if err := s.jobRepository.UpdateProgress(ctx, nil, jobId, p); err != nil {
+			mylog.Errorln("failed to update job progress:", err)
+		}
+	}
+
+	buf, filename, runErr := s.execute(ctx, job, progress)
+	if runErr == nil && buf != nil {
+		runErr = s.storeArtifact(&job, buf, filename)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.retention)
+	job.FinishedAt = &now
+	job.ExpiresAt = &expiresAt
+	if runErr != nil {
+		msg := runErr.Error()
+		job.Status = entity.JobStatusFailed
+		job.Error = &msg
+	} else {
+		job.Status = entity.JobStatusSucceeded
+		job.Progress = 100
+	}
+
+	if _, err := s.jobRepository.Update(ctx, nil, job); err != nil {
+		mylog.Errorln("failed to finish job:", err)
+	}
+}
+
+// execute runs the registered handler and turns a panic into a failed job
+func (s *jobService) execute(ctx context.Context, job entity.Job, progress func(int)) (buf *bytes.Buffer, filename string, err error) {
+	handler, ok := s.handler(job.Type)
+	if !ok {
+		return nil, "", fmt.Errorf("no handler registered for %s", job.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			buf, filename, err = nil, "", fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler.Run(ctx, job, progress)
+}
+
+func (s *jobService) storeArtifact(job *entity.Job, buf *bytes.Buffer, filename string) error {
+	dir := filepath.Join(utils.PATH, jobArtifactDir)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s", job.ID, filepath.Base(filename)))
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	contentType, ok := contentTypeByExtension[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+
+	job.ArtifactPath = &path
+	job.ArtifactName = &filename
+	job.ArtifactContentType = &contentType
+	return nil
+}
+
+// jobDedupKey hashes the job type with its params in a stable order
+func jobDedupKey(jobType entity.JobType, params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(string(jobType))
+	for _, k := range keys {
+		fmt.Fprintf(&b, "|%s=%s", k, params[k])
+	}
+
+	sum := sha1.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func jobResponse(job entity.Job) dto.JobResponse {
+	return dto.JobResponse{
+		ID:         job.ID.String(),
+		Type:       string(job.Type),
+		Status:     string(job.Status),
+		Progress:   job.Progress,
+		Params:     job.Params,
+		Error:      job.Error,
+		Filename:   job.ArtifactName,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+		ExpiresAt:  job.ExpiresAt,
+	}
+}
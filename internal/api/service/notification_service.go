@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	mylog "github.com/CRS-Project/crs-backend/internal/pkg/logger"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	NotificationService interface {
+		Notify(ctx context.Context, recipients []uuid.UUID, notification entity.Notification) error
+		NotifyAssignment(ctx context.Context, document entity.Document, disciplineListDocumentId uuid.UUID, disciplineGroupConsolidatorIds []string) error
+		GetAll(ctx context.Context, userId string, unreadOnly bool, metaReq meta.Meta) ([]dto.NotificationResponse, meta.Meta, error)
+		Read(ctx context.Context, req dto.ReadNotificationRequest) error
+	}
+
+	notificationService struct {
+		notificationRepository                repository.NotificationRepository
+		delegationRepository                  repository.DelegationRepository
+		disciplineGroupConsolidatorRepository repository.DisciplineGroupConsolidatorRepository
+		db                                    *gorm.DB
+	}
+)
+
+func NewNotification(notificationRepository repository.NotificationRepository,
+	delegationRepository repository.DelegationRepository,
+	disciplineGroupConsolidatorRepository repository.DisciplineGroupConsolidatorRepository,
+	db *gorm.DB) NotificationService {
+	return &notificationService{
+		notificationRepository:                notificationRepository,
+		delegationRepository:                  delegationRepository,
+		disciplineGroupConsolidatorRepository: disciplineGroupConsolidatorRepository,
+		db:                                    db,
+	}
+}
+
+// Notify sends the notification to every recipient. Recipients who are away
+// have it forwarded to their substitute as well, so nothing stalls while the
+// delegation is active.
+func (s *notificationService) Notify(ctx context.Context, recipients []uuid.UUID, notification entity.Notification) error {
+	seen := make(map[uuid.UUID]bool)
+	var userIds []string
+	var notifications []entity.Notification
+	for _, recipient := range recipients {
+		if recipient == uuid.Nil || seen[recipient] {
+			continue
+		}
+		seen[recipient] = true
+		userIds = append(userIds, recipient.String())
+
+		n := notification
+		n.UserID = recipient
+		n.OnBehalfOfID = nil
+		notifications = append(notifications, n)
+	}
+
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	delegations, err := s.delegationRepository.GetActiveByUserIDs(ctx, nil, userIds, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, delegation := range delegations {
+		onBehalfOf := delegation.UserID
+		n := notification
+		n.UserID = delegation.SubstituteID
+		n.OnBehalfOfID = &onBehalfOf
+		notifications = append(notifications, n)
+	}
+
+	return s.notificationRepository.CreateBulk(ctx, nil, notifications)
+}
+
+// notifyQuietly sends notifications in a savepoint of the running
+// transaction. When they fail only the savepoint is rolled back and the
+// failure is logged, the change they tell about is kept.
+func notifyQuietly(ctx context.Context, db *gorm.DB, notify func(ctx context.Context) error) {
+	if err := repository.Transaction(ctx, db, notify); err != nil {
+		mylog.Errorln("failed to send notification:", err)
+	}
+}
+
+// NotifyAssignment tells the users behind the discipline group consolidators
+// that the document was assigned to them
+func (s *notificationService) NotifyAssignment(ctx context.Context, document entity.Document, disciplineListDocumentId uuid.UUID, disciplineGroupConsolidatorIds []string) error {
+	if len(disciplineGroupConsolidatorIds) == 0 {
+		return nil
+	}
+
+	consolidators, err := s.disciplineGroupConsolidatorRepository.GetByIDs(ctx, nil, disciplineGroupConsolidatorIds)
+	if err != nil {
+		return err
+	}
+
+	var recipients []uuid.UUID
+	for _, c := range consolidators {
+		recipients = append(recipients, c.UserID)
+	}
+
+	return s.Notify(ctx, recipients, entity.Notification{
+		Type:                     entity.NotificationDocumentAssigned,
+		Title:                    "Document assigned",
+		Message:                  fmt.Sprintf("%s - %s is assigned to you for review", document.CompanyDocumentNumber, document.DocumentTitle),
+		DisciplineListDocumentID: &disciplineListDocumentId,
+	})
+}
+
+func (s *notificationService) GetAll(ctx context.Context, userId string, unreadOnly bool, metaReq meta.Meta) ([]dto.NotificationResponse, meta.Meta, error) {
+	notifications, metaRes, err := s.notificationRepository.GetAllByUserID(ctx, nil, userId, unreadOnly, metaReq, "OnBehalfOf")
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	res := []dto.NotificationResponse{}
+	for _, n := range notifications {
+		item := dto.NotificationResponse{
+			ID:        n.ID.String(),
+			Type:      string(n.Type),
+			Title:     n.Title,
+			Message:   n.Message,
+			IsRead:    n.ReadAt != nil,
+			ReadAt:    n.ReadAt,
+			CreatedAt: n.CreatedAt,
+		}
+
+		if n.DisciplineListDocumentID != nil {
+			id := n.DisciplineListDocumentID.String()
+			item.DisciplineListDocumentID = &id
+		}
+
+		if n.CommentID != nil {
+			id := n.CommentID.String()
+			item.CommentID = &id
+		}
+
+		if n.OnBehalfOf != nil {
+			item.OnBehalfOf = userComment(n.OnBehalfOf)
+		}
+
+		res = append(res, item)
+	}
+
+	return res, metaRes, nil
+}
+
+// Read marks the given notifications as read, or all of them when the
+// request lists none
+func (s *notificationService) Read(ctx context.Context, req dto.ReadNotificationRequest) error {
+	for _, notificationId := range req.NotificationIDs {
+		notification, err := s.notificationRepository.GetByID(ctx, nil, notificationId)
+		if err != nil {
+			return err
+		}
+
+		if notification.UserID.String() != req.UserId {
+			return myerror.New("you don't have permission for this notification", http.StatusUnauthorized)
+		}
+	}
+
+	return s.notificationRepository.MarkRead(ctx, nil, req.UserId, req.NotificationIDs)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/CRS-Project/crs-backend/internal/api/repository"
 	"github.com/CRS-Project/crs-backend/internal/dto"
@@ -24,25 +25,29 @@ type (
 		DeletePackage(ctx context.Context, id string) error
 		GeneratePDF(ctx context.Context, id string) (*bytes.Buffer, string, error)
 		GenerateExcel(ctx context.Context, id string) (*bytes.Buffer, string, error)
+		BuildReport(ctx context.Context, id string) ([]mypdf.GenerateRequestData, mypdf.Template, error)
 	}
 
 	packageService struct {
-		packageRepository      repository.PackageRepository
-		userRepository         repository.UserRepository
-		disciplineGroupService DisciplineGroupService
-		db                     *gorm.DB
+		packageRepository        repository.PackageRepository
+		userRepository           repository.UserRepository
+		disciplineGroupService   DisciplineGroupService
+		reportTemplateRepository repository.ReportTemplateRepository
+		db                       *gorm.DB
 	}
 )
 
 func NewPackage(packageRepository repository.PackageRepository,
 	userRepository repository.UserRepository,
 	disciplineGroupService DisciplineGroupService,
+	reportTemplateRepository repository.ReportTemplateRepository,
 	db *gorm.DB) PackageService {
 	return &packageService{
-		packageRepository:      packageRepository,
-		userRepository:         userRepository,
-		disciplineGroupService: disciplineGroupService,
-		db:                     db,
+		packageRepository:        packageRepository,
+		userRepository:           userRepository,
+		disciplineGroupService:   disciplineGroupService,
+		reportTemplateRepository: reportTemplateRepository,
+		db:                       db,
 	}
 }
 
@@ -55,6 +60,15 @@ func (s *packageService) CreatePackage(ctx context.Context, req dto.CreatePackag
 	pkgCreation := entity.Package{
 		Name: req.Name,
 	}
+	if req.ReviewPeriodDays != nil {
+		pkgCreation.ReviewPeriodDays = *req.ReviewPeriodDays
+	}
+	if req.TimeZone != nil {
+		if _, err := time.LoadLocation(*req.TimeZone); err != nil || *req.TimeZone == "" {
+			return dto.PackageInfo{}, myerror.New("time zone is not a valid IANA time zone", http.StatusBadRequest)
+		}
+		pkgCreation.TimeZone = *req.TimeZone
+	}
 
 	pkgResult, err := s.packageRepository.Create(ctx, nil, pkgCreation)
 	if err != nil {
@@ -123,6 +137,15 @@ func (s *packageService) UpdatePackage(ctx context.Context, req dto.UpdatePackag
 		return dto.PackageInfo{}, err
 	}
 	pkg.Name = req.Name
+	if req.ReviewPeriodDays != nil {
+		pkg.ReviewPeriodDays = *req.ReviewPeriodDays
+	}
+	if req.TimeZone != nil {
+		if _, err := time.LoadLocation(*req.TimeZone); err != nil || *req.TimeZone == "" {
+			return dto.PackageInfo{}, myerror.New("time zone is not a valid IANA time zone", http.StatusBadRequest)
+		}
+		pkg.TimeZone = *req.TimeZone
+	}
 
 	pkg, err = s.packageRepository.Update(ctx, nil, pkg)
 	if err != nil {
@@ -146,39 +169,49 @@ func (s *packageService) DeletePackage(ctx context.Context, id string) error {
 }
 
 func (s *packageService) GeneratePDF(ctx context.Context, id string) (*bytes.Buffer, string, error) {
-	data, err := s.packageRepository.GetByID(ctx, nil, id, "DisciplineGroups.Package", "DisciplineGroups.DisciplineGroupConsolidators.User", "DisciplineGroups.DisciplineListDocuments.Comments.CommentReplies", "DisciplineGroups.DisciplineListDocuments.Comments.User", "DisciplineGroups.DisciplineListDocuments.Document")
+	requestData, tpl, err := s.BuildReport(ctx, id)
 	if err != nil {
 		return nil, "", err
 	}
 
-	contractor, err := s.userRepository.GetContractorByPackage(ctx, nil, id, "Package")
+	pdfBuffer, filename, err := mypdf.Generate(requestData, tpl)
 	if err != nil {
 		return nil, "", err
 	}
 
-	var requestData []mypdf.GenerateRequestData
-	for _, aocg := range data.DisciplineGroups {
-		generateData := s.disciplineGroupService.ConstructGeneratePDF(aocg, contractor)
-		requestData = append(requestData, generateData...)
+	return pdfBuffer, filename, nil
+}
+
+func (s *packageService) GenerateExcel(ctx context.Context, id string) (*bytes.Buffer, string, error) {
+	requestData, tpl, err := s.BuildReport(ctx, id)
+	if err != nil {
+		return nil, "", err
 	}
 
-	pdfBuffer, filename, err := mypdf.Generate(requestData)
+	excelBuffer, filename, err := mypdf.GenerateExcel(requestData, tpl)
 	if err != nil {
 		return nil, "", err
 	}
 
-	return pdfBuffer, filename, nil
+	return excelBuffer, filename, nil
 }
 
-func (s *packageService) GenerateExcel(ctx context.Context, id string) (*bytes.Buffer, string, error) {
-	data, err := s.packageRepository.GetByID(ctx, nil, id, "DisciplineGroups.Package", "DisciplineGroups.DisciplineGroupConsolidators.User", "DisciplineGroups.DisciplineListDocuments.Comments.CommentReplies", "DisciplineGroups.DisciplineListDocuments.Comments.User", "DisciplineGroups.DisciplineListDocuments.Document")
+// BuildReport loads the CRS of every discipline group in the package together
+// with the package report template
+func (s *packageService) BuildReport(ctx context.Context, id string) ([]mypdf.GenerateRequestData, mypdf.Template, error) {
+	data, err := s.packageRepository.GetByID(ctx, nil, id, "DisciplineGroups.Package", "DisciplineGroups.DisciplineGroupConsolidators.User", "DisciplineGroups.DisciplineListDocuments.Comments.CommentReplies", "DisciplineGroups.DisciplineListDocuments.Comments.User", "DisciplineGroups.DisciplineListDocuments.Comments.Category", "DisciplineGroups.DisciplineListDocuments.Comments.Severity", "DisciplineGroups.DisciplineListDocuments.Document.ReviewOutcomes")
 	if err != nil {
-		return nil, "", err
+		return nil, mypdf.Template{}, err
 	}
 
 	contractor, err := s.userRepository.GetContractorByPackage(ctx, nil, id, "Package")
 	if err != nil {
-		return nil, "", err
+		return nil, mypdf.Template{}, err
+	}
+
+	tpl, err := loadReportTemplate(ctx, s.reportTemplateRepository, id)
+	if err != nil {
+		return nil, mypdf.Template{}, err
 	}
 
 	var requestData []mypdf.GenerateRequestData
@@ -187,10 +220,5 @@ func (s *packageService) GenerateExcel(ctx context.Context, id string) (*bytes.B
 		requestData = append(requestData, generateData...)
 	}
 
-	excelBuffer, filename, err := mypdf.GenerateExcel(requestData)
-	if err != nil {
-		return nil, "", err
-	}
-
-	return excelBuffer, filename, nil
+	return requestData, tpl, nil
 }
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"gorm.io/gorm"
+)
+
+const (
+	queueAssigned         = "ASSIGNED"
+	queueDueDate          = "DUE_DATE"
+	queueAwaitingCloseOut = "AWAITING_CLOSE_OUT"
+	queueReply            = "REPLY"
+
+	urgencyOverdue = "OVERDUE"
+	urgencyDueSoon = "DUE_SOON"
+	urgencyNormal  = "NORMAL"
+
+	defaultQueueDueSoonDays = 3
+	defaultQueueReplyDays   = 7
+)
+
+var urgencyRank = map[string]int{
+	urgencyOverdue: 0,
+	urgencyDueSoon: 1,
+	urgencyNormal:  2,
+}
+
+type (
+	QueueService interface {
+		GetQueue(ctx context.Context, userId string, metaReq meta.Meta) ([]dto.QueueItem, meta.Meta, error)
+	}
+
+	queueService struct {
+		queueRepository      repository.QueueRepository
+		delegationRepository repository.DelegationRepository
+		userRepository       repository.UserRepository
+		db                   *gorm.DB
+
+		dueSoon   time.Duration
+		replySpan time.Duration
+	}
+)
+
+func NewQueue(queueRepository repository.QueueRepository,
+	delegationRepository repository.DelegationRepository,
+	userRepository repository.UserRepository,
+	db *gorm.DB) QueueService {
+	dueSoonDays := defaultQueueDueSoonDays
+	if v, err := strconv.Atoi(os.Getenv("QUEUE_DUE_SOON_DAYS")); err == nil && v >= 0 {
+		dueSoonDays = v
+	}
+
+	replyDays := defaultQueueReplyDays
+	if v, err := strconv.Atoi(os.Getenv("QUEUE_REPLY_DAYS")); err == nil && v > 0 {
+		replyDays = v
+	}
+
+	return &queueService{
+		queueRepository:      queueRepository,
+		delegationRepository: delegationRepository,
+		userRepository:       userRepository,
+		db:                   db,
+		dueSoon:              time.Duration(dueSoonDays) * 24 * time.Hour,
+		replySpan:            time.Duration(replyDays) * 24 * time.Hour,
+	}
+}
+
+// GetQueue collects what the user has to act on over every package: assigned
+// documents they did not comment on yet, commented documents close to or
+// past their due date that still have open comments, open comments that got
+// an answer and wait for close out, and recent replies on their comments.
+// Work of the users they currently stand in for is included as well.
+//
+// Items are sorted by urgency by default and can be filtered by type,
+// urgency, package_id and discipline_group_id.
+func (s *queueService) GetQueue(ctx context.Context, userId string, metaReq meta.Meta) ([]dto.QueueItem, meta.Meta, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	filters, err := queueFilters(metaReq)
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	now := time.Now()
+	delegations, err := s.delegationRepository.GetActiveBySubstituteID(ctx, nil, userId, now, "User")
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	owners := map[string]*entity.User{user.ID.String(): nil}
+	userIds := []string{user.ID.String()}
+	for _, d := range delegations {
+		if _, ok := owners[d.UserID.String()]; ok {
+			continue
+		}
+		owners[d.UserID.String()] = d.User
+		userIds = append(userIds, d.UserID.String())
+	}
+
+	onBehalfOf := func(ownerId string) *dto.UserComment {
+		if owner := owners[ownerId]; owner != nil {
+			return userComment(owner)
+		}
+		return nil
+	}
+
+	var items []dto.QueueItem
+	seen := make(map[string]bool)
+	add := func(key string, item dto.QueueItem) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		item.Urgency = s.urgency(item.DueDate, now)
+		items = append(items, item)
+	}
+
+	documents, err := s.queueRepository.GetAssignedDocuments(ctx, nil, userIds)
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	for _, d := range documents {
+		var itemType string
+		switch {
+		case d.MyComment == 0:
+			itemType = queueAssigned
+		case d.OpenComment > 0 && d.DueDate != nil && d.DueDate.Before(now.Add(s.dueSoon)):
+			itemType = queueDueDate
+		default:
+			continue
+		}
+
+		add(itemType+d.DisciplineListDocumentID, dto.QueueItem{
+			Type:                     itemType,
+			DueDate:                  d.DueDate,
+			At:                       d.AssignedAt,
+			PackageID:                d.PackageID,
+			PackageName:              d.PackageName,
+			DisciplineGroupID:        d.DisciplineGroupID,
+			ReviewFocus:              d.ReviewFocus,
+			DisciplineListDocumentID: d.DisciplineListDocumentID,
+			DocumentID:               d.DocumentID,
+			CompanyDocumentNumber:    d.CompanyDocumentNumber,
+			DocumentTitle:            d.DocumentTitle,
+			OpenComment:              d.OpenComment,
+			OnBehalfOf:               onBehalfOf(d.OwnerID),
+		})
+	}
+
+	closeOuts, err := s.queueRepository.GetAwaitingCloseOut(ctx, nil, userIds, commentScope(user))
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	for _, c := range closeOuts {
+		add(queueAwaitingCloseOut+c.CommentID, queueCommentItem(queueAwaitingCloseOut, c, onBehalfOf(c.OwnerID)))
+	}
+
+	replies, err := s.queueRepository.GetReplies(ctx, nil, userIds, now.Add(-s.replySpan), commentScope(user))
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	for _, c := range replies {
+		add(queueReply+c.ReplyID, queueCommentItem(queueReply, c, onBehalfOf(c.OwnerID)))
+	}
+
+	res := []dto.QueueItem{}
+	for _, item := range items {
+		if matchQueueFilters(item, filters) {
+			res = append(res, item)
+		}
+	}
+
+	if err := sortQueue(res, metaReq); err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	metaReq.Count(len(res))
+	skip, limit := metaReq.GetSkipAndLimit()
+	if skip > len(res) {
+		skip = len(res)
+	}
+	end := skip + limit
+	if end > len(res) {
+		end = len(res)
+	}
+
+	return res[skip:end], metaReq, nil
+}
+
+func (s *queueService) urgency(dueDate *time.Time, now time.Time) string {
+	switch {
+	case dueDate == nil:
+		return urgencyNormal
+	case dueDate.Before(now):
+		return urgencyOverdue
+	case dueDate.Before(now.Add(s.dueSoon)):
+		return urgencyDueSoon
+	default:
+		return urgencyNormal
+	}
+}
+
+func queueCommentItem(itemType string, c dto.QueueCommentRow, onBehalfOf *dto.UserComment) dto.QueueItem {
+	commentId, comment := c.CommentID, c.Comment
+	replyId, reply, replyBy := c.ReplyID, c.Reply, c.ReplyBy
+
+	return dto.QueueItem{
+		Type:                     itemType,
+		DueDate:                  c.DueDate,
+		At:                       c.At,
+		PackageID:                c.PackageID,
+		PackageName:              c.PackageName,
+		DisciplineGroupID:        c.DisciplineGroupID,
+		ReviewFocus:              c.ReviewFocus,
+		DisciplineListDocumentID: c.DisciplineListDocumentID,
+		DocumentID:               c.DocumentID,
+		CompanyDocumentNumber:    c.CompanyDocumentNumber,
+		DocumentTitle:            c.DocumentTitle,
+		CommentID:                &commentId,
+		Comment:                  &comment,
+		ReplyID:                  &replyId,
+		Reply:                    &reply,
+		ReplyBy:                  &replyBy,
+		OnBehalfOf:               onBehalfOf,
+	}
+}
+
+func queueFilters(metaReq meta.Meta) (map[string]string, error) {
+	if metaReq.FilterBy == "" {
+		return map[string]string{}, nil
+	}
+
+	if len(strings.Split(metaReq.FilterBy, ",")) > len(strings.Split(metaReq.Filter, ",")) {
+		return nil, myerror.New("every filter_by needs a filter value", http.StatusBadRequest)
+	}
+
+	filters := metaReq.SeparateFilter()
+	for field := range filters {
+		switch field {
+		case "type", "urgency", "package_id", "discipline_group_id":
+		default:
+			return nil, myerror.New("invalid filter field: "+field, http.StatusBadRequest)
+		}
+	}
+
+	return filters, nil
+}
+
+func matchQueueFilters(item dto.QueueItem, filters map[string]string) bool {
+	for field, value := range filters {
+		if value == "" {
+			continue
+		}
+
+		var got string
+		switch field {
+		case "type":
+			got = item.Type
+		case "urgency":
+			got = item.Urgency
+		case "package_id":
+			got = item.PackageID
+		case "discipline_group_id":
+			got = item.DisciplineGroupID
+		}
+
+		if !strings.EqualFold(got, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortQueue orders by urgency, then due date and then the most recent
+// activity. Sorting by due_date or at uses that field first.
+func sortQueue(items []dto.QueueItem, metaReq meta.Meta) error {
+	if metaReq.Sort != "asc" && metaReq.Sort != "desc" {
+		return myerror.New("invalid sort (must be 'asc' or 'desc')", http.StatusBadRequest)
+	}
+
+	byDueDate := func(a, b dto.QueueItem) int {
+		switch {
+		case a.DueDate == nil && b.DueDate == nil:
+			return 0
+		case a.DueDate == nil:
+			return 1
+		case b.DueDate == nil:
+			return -1
+		default:
+			return a.DueDate.Compare(*b.DueDate)
+		}
+	}
+
+	var first func(a, b dto.QueueItem) int
+	switch metaReq.SortBy {
+	case "", "id", "urgency":
+		first = func(a, b dto.QueueItem) int { return urgencyRank[a.Urgency] - urgencyRank[b.Urgency] }
+	case "due_date":
+		first = byDueDate
+	case "at":
+		first = func(a, b dto.QueueItem) int { return a.At.Compare(b.At) }
+	default:
+		return myerror.New("invalid sort_by field: "+metaReq.SortBy, http.StatusBadRequest)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if c := first(items[i], items[j]); c != 0 {
+			if metaReq.Sort == "desc" {
+				return c > 0
+			}
+			return c < 0
+		}
+		if c := byDueDate(items[i], items[j]); c != 0 {
+			return c < 0
+		}
+		return items[i].At.After(items[j].At)
+	})
+
+	return nil
+}
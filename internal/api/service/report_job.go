@@ -0,0 +1,85 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/google/uuid"
+)
+
+type reportGenerator func(ctx context.Context, job entity.Job) (*bytes.Buffer, string, error)
+
+// RegisterReportJobs registers the CRS report exports so they can run in the
+// background instead of inside the request.
+func RegisterReportJobs(jobService JobService,
+	packageService PackageService,
+	disciplineGroupService DisciplineGroupService,
+	disciplineGroupRepository repository.DisciplineGroupRepository) {
+	packageScope := func(ctx context.Context, params map[string]string) (*uuid.UUID, error) {
+		packageId, err := uuid.Parse(params["package_id"])
+		if err != nil {
+			return nil, myerror.New("params.package_id must be a valid id", http.StatusBadRequest)
+		}
+
+		return &packageId, nil
+	}
+
+	disciplineGroupScope := func(ctx context.Context, params map[string]string) (*uuid.UUID, error) {
+		if _, err := uuid.Parse(params["discipline_group_id"]); err != nil {
+			return nil, myerror.New("params.discipline_group_id must be a valid id", http.StatusBadRequest)
+		}
+
+		disciplineGroup, err := disciplineGroupRepository.GetByID(ctx, nil, params["discipline_group_id"])
+		if err != nil {
+			return nil, err
+		}
+
+		return &disciplineGroup.PackageID, nil
+	}
+
+	jobService.Register(entity.JobTypePackagePDF, JobHandler{
+		Scope: packageScope,
+		Run: reportJob(func(ctx context.Context, job entity.Job) (*bytes.Buffer, string, error) {
+			return packageService.GeneratePDF(ctx, job.Params["package_id"])
+		}),
+	})
+
+	jobService.Register(entity.JobTypePackageExcel, JobHandler{
+		Scope: packageScope,
+		Run: reportJob(func(ctx context.Context, job entity.Job) (*bytes.Buffer, string, error) {
+			return packageService.GenerateExcel(ctx, job.Params["package_id"])
+		}),
+	})
+
+	jobService.Register(entity.JobTypeDisciplineGroupPDF, JobHandler{
+		Scope: disciplineGroupScope,
+		Run: reportJob(func(ctx context.Context, job entity.Job) (*bytes.Buffer, string, error) {
+			return disciplineGroupService.GeneratePDF(ctx, job.UserID.String(), job.Params["discipline_group_id"])
+		}),
+	})
+
+	jobService.Register(entity.JobTypeDisciplineGroupExcel, JobHandler{
+		Scope: disciplineGroupScope,
+		Run: reportJob(func(ctx context.Context, job entity.Job) (*bytes.Buffer, string, error) {
+			return disciplineGroupService.GenerateExcel(ctx, job.UserID.String(), job.Params["discipline_group_id"])
+		}),
+	})
+}
+
+func reportJob(generate reportGenerator) func(ctx context.Context, job entity.Job, progress func(int)) (*bytes.Buffer, string, error) {
+	return func(ctx context.Context, job entity.Job, progress func(int)) (*bytes.Buffer, string, error) {
+		progress(10)
+
+		buf, filename, err := generate(ctx, job)
+		if err != nil {
+			return nil, "", err
+		}
+
+		progress(90)
+		return buf, filename, nil
+	}
+}
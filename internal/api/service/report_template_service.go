@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	mypdf "github.com/CRS-Project/crs-backend/internal/pkg/pdf"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	ReportTemplateService interface {
+		GetByPackageID(ctx context.Context, userId, packageId string) (dto.ReportTemplateResponse, error)
+		Upsert(ctx context.Context, req dto.ReportTemplateRequest) (dto.ReportTemplateResponse, error)
+		Delete(ctx context.Context, userId, packageId string) error
+	}
+
+	reportTemplateService struct {
+		reportTemplateRepository repository.ReportTemplateRepository
+		packageRepository        repository.PackageRepository
+		userRepository           repository.UserRepository
+		db                       *gorm.DB
+	}
+)
+
+func NewReportTemplate(reportTemplateRepository repository.ReportTemplateRepository,
+	packageRepository repository.PackageRepository,
+	userRepository repository.UserRepository,
+	db *gorm.DB) ReportTemplateService {
+	return &reportTemplateService{
+		reportTemplateRepository: reportTemplateRepository,
+		packageRepository:        packageRepository,
+		userRepository:           userRepository,
+		db:                       db,
+	}
+}
+
+func (s *reportTemplateService) GetByPackageID(ctx context.Context, userId, packageId string) (dto.ReportTemplateResponse, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return dto.ReportTemplateResponse{}, err
+	}
+
+	if user.PackageID != nil && user.PackageID.String() != packageId {
+		return dto.ReportTemplateResponse{}, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	if _, err := s.packageRepository.GetByID(ctx, nil, packageId); err != nil {
+		return dto.ReportTemplateResponse{}, err
+	}
+
+	reportTemplate, err := s.reportTemplateRepository.GetByPackageID(ctx, nil, packageId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return reportTemplateResponse(nil, packageId, mypdf.DefaultTemplate()), nil
+	} else if err != nil {
+		return dto.ReportTemplateResponse{}, err
+	}
+
+	return reportTemplateResponse(&reportTemplate, packageId, reportTemplate.ToTemplate("")), nil
+}
+
+func (s *reportTemplateService) Upsert(ctx context.Context, req dto.ReportTemplateRequest) (dto.ReportTemplateResponse, error) {
+	pkg, err := s.packageRepository.GetByID(ctx, nil, req.PackageId)
+	if err != nil {
+		return dto.ReportTemplateResponse{}, err
+	}
+
+	if req.LogoUrl != nil {
+		if _, ok := utils.GetUploadedFilePath(*req.LogoUrl); !ok {
+			return dto.ReportTemplateResponse{}, myerror.New("logo must be uploaded through /api/v1/uploads", http.StatusBadRequest)
+		}
+	}
+
+	headerFields := make([]mypdf.HeaderField, len(req.HeaderFields))
+	for i, h := range req.HeaderFields {
+		headerFields[i] = mypdf.HeaderField{Label: h.Label, Key: h.Key, Value: h.Value, Right: h.Right}
+	}
+
+	columns := make([]mypdf.Column, len(req.Columns))
+	for i, c := range req.Columns {
+		columns[i] = mypdf.Column{Key: c.Key, Label: c.Label, Width: c.Width, ExcelWidth: c.ExcelWidth, Highlight: c.Highlight}
+	}
+
+	reportTemplate, err := s.reportTemplateRepository.GetByPackageID(ctx, nil, pkg.ID.String())
+	isNew := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !isNew {
+		return dto.ReportTemplateResponse{}, err
+	}
+
+	reportTemplate.PackageID = pkg.ID
+	reportTemplate.LogoUrl = req.LogoUrl
+	reportTemplate.Title = req.Title
+	reportTemplate.PaperSize = req.PaperSize
+	reportTemplate.Orientation = req.Orientation
+	reportTemplate.HeaderFields = headerFields
+	reportTemplate.Columns = columns
+	reportTemplate.FooterNote = req.FooterNote
+	reportTemplate.SignatureBlocks = req.SignatureBlocks
+	reportTemplate.UpdatedBy = uuid.MustParse(req.UserId)
+
+	if err := reportTemplate.ToTemplate("").Validate(); err != nil {
+		return dto.ReportTemplateResponse{}, myerror.New(err.Error(), http.StatusBadRequest)
+	}
+
+	if isNew {
+		reportTemplate, err = s.reportTemplateRepository.Create(ctx, nil, reportTemplate)
+	} else {
+		reportTemplate, err = s.reportTemplateRepository.Update(ctx, nil, reportTemplate)
+	}
+	if err != nil {
+		return dto.ReportTemplateResponse{}, err
+	}
+
+	return reportTemplateResponse(&reportTemplate, pkg.ID.String(), reportTemplate.ToTemplate("")), nil
+}
+
+func (s *reportTemplateService) Delete(ctx context.Context, userId, packageId string) error {
+	reportTemplate, err := s.reportTemplateRepository.GetByPackageID(ctx, nil, packageId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return myerror.New("package already uses the default template", http.StatusNotFound)
+		}
+		return err
+	}
+
+	// mark who deleted
+	reportTemplate.DeletedBy = uuid.MustParse(userId)
+	if err := s.reportTemplateRepository.Delete(ctx, nil, reportTemplate); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// loadReportTemplate returns the template of a package, or the default one
+// when the package has none
+func loadReportTemplate(ctx context.Context, reportTemplateRepository repository.ReportTemplateRepository, packageId string) (mypdf.Template, error) {
+	reportTemplate, err := reportTemplateRepository.GetByPackageID(ctx, nil, packageId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return mypdf.DefaultTemplate(), nil
+	} else if err != nil {
+		return mypdf.Template{}, err
+	}
+
+	logoPath := ""
+	if reportTemplate.LogoUrl != nil {
+		logoPath, _ = utils.GetUploadedFilePath(*reportTemplate.LogoUrl)
+	}
+
+	return reportTemplate.ToTemplate(logoPath), nil
+}
+
+func reportTemplateResponse(reportTemplate *entity.ReportTemplate, packageId string, tpl mypdf.Template) dto.ReportTemplateResponse {
+	res := dto.ReportTemplateResponse{
+		PackageID:       packageId,
+		IsDefault:       reportTemplate == nil,
+		Title:           tpl.Title,
+		PaperSize:       tpl.PaperSize,
+		Orientation:     tpl.Orientation,
+		FooterNote:      tpl.FooterNote,
+		SignatureBlocks: tpl.SignatureBlocks,
+		AvailableColumn: mypdf.ColumnKeys,
+		AvailableHeader: mypdf.HeaderKeys,
+	}
+
+	if reportTemplate != nil {
+		id := reportTemplate.ID.String()
+		res.ID = &id
+		res.LogoUrl = reportTemplate.LogoUrl
+	}
+
+	for _, h := range tpl.HeaderFields {
+		res.HeaderFields = append(res.HeaderFields, dto.ReportTemplateHeaderField{
+			Label: h.Label,
+			Key:   h.Key,
+			Value: h.Value,
+			Right: h.Right,
+		})
+	}
+
+	for _, c := range tpl.Columns {
+		res.Columns = append(res.Columns, dto.ReportTemplateColumn{
+			Key:        c.Key,
+			Label:      c.Label,
+			Width:      c.Width,
+			ExcelWidth: c.ExcelWidth,
+			Highlight:  c.Highlight,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	ReviewOutcomeService interface {
+		Get(ctx context.Context, userId, documentId string) (dto.DocumentReviewOutcomeResponse, error)
+		Set(ctx context.Context, req dto.ReviewOutcomeRequest) (dto.ReviewOutcomeResponse, error)
+	}
+
+	reviewOutcomeService struct {
+		reviewOutcomeRepository repository.ReviewOutcomeRepository
+		documentRepository      repository.DocumentRepository
+		userRepository          repository.UserRepository
+		delegationRepository    repository.DelegationRepository
+		db                      *gorm.DB
+	}
+)
+
+func NewReviewOutcome(reviewOutcomeRepository repository.ReviewOutcomeRepository,
+	documentRepository repository.DocumentRepository,
+	userRepository repository.UserRepository,
+	delegationRepository repository.DelegationRepository,
+	db *gorm.DB) ReviewOutcomeService {
+	return &reviewOutcomeService{
+		reviewOutcomeRepository: reviewOutcomeRepository,
+		documentRepository:      documentRepository,
+		userRepository:          userRepository,
+		delegationRepository:    delegationRepository,
+		db:                      db,
+	}
+}
+
+func (s *reviewOutcomeService) Get(ctx context.Context, userId, documentId string) (dto.DocumentReviewOutcomeResponse, error) {
+	document, _, err := s.get(ctx, userId, documentId)
+	if err != nil {
+		return dto.DocumentReviewOutcomeResponse{}, err
+	}
+
+	reviewOutcomes, err := s.reviewOutcomeRepository.GetAllByDocumentID(ctx, nil, documentId, "SetBy")
+	if err != nil {
+		return dto.DocumentReviewOutcomeResponse{}, err
+	}
+
+	res := dto.DocumentReviewOutcomeResponse{
+		DocumentID: document.ID.String(),
+		Revision:   document.Revision,
+		Suggestion: suggestReviewOutcome(document),
+		History:    []dto.ReviewOutcomeResponse{},
+	}
+
+	for _, reviewOutcome := range reviewOutcomes {
+		reviewOutcomeRes := reviewOutcomeResponse(reviewOutcome)
+		if reviewOutcome.Revision == document.Revision {
+			res.Current = &reviewOutcomeRes
+		}
+
+		res.History = append(res.History, reviewOutcomeRes)
+	}
+
+	return res, nil
+}
+
+// Set gives the revision under review its outcome, setting it again
+// overwrites the previous code of the same revision
+func (s *reviewOutcomeService) Set(ctx context.Context, req dto.ReviewOutcomeRequest) (dto.ReviewOutcomeResponse, error) {
+	document, user, err := s.get(ctx, req.UserId, req.DocumentID)
+	if err != nil {
+		return dto.ReviewOutcomeResponse{}, err
+	}
+
+	allowed, err := s.isConsolidator(ctx, user, document)
+	if err != nil {
+		return dto.ReviewOutcomeResponse{}, err
+	}
+
+	if !allowed {
+		return dto.ReviewOutcomeResponse{}, myerror.New("only consolidators of this document can set its review outcome", http.StatusUnauthorized)
+	}
+
+	reviewOutcome, err := s.reviewOutcomeRepository.GetByRevision(ctx, nil, document.ID.String(), document.Revision)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.ReviewOutcomeResponse{}, err
+	}
+
+	reviewOutcome.Revision = document.Revision
+	reviewOutcome.DocumentID = document.ID
+	reviewOutcome.Code = entity.ReviewOutcomeCode(req.Code)
+	reviewOutcome.SuggestedCode = entity.ReviewOutcomeCode(suggestReviewOutcome(document).Code.Code)
+	reviewOutcome.Remarks = req.Remarks
+	reviewOutcome.SetAt = time.Now()
+	reviewOutcome.SetByID = user.ID
+	reviewOutcome.UpdatedBy = user.ID
+
+	if reviewOutcome.ID == uuid.Nil {
+		reviewOutcome, err = s.reviewOutcomeRepository.Create(ctx, nil, reviewOutcome)
+	} else {
+		reviewOutcome, err = s.reviewOutcomeRepository.Update(ctx, nil, reviewOutcome)
+	}
+	if err != nil {
+		return dto.ReviewOutcomeResponse{}, err
+	}
+
+	reviewOutcome.SetBy = &user
+	return reviewOutcomeResponse(reviewOutcome), nil
+}
+
+// get loads the document with what the suggestion and the consolidator check
+// need
+func (s *reviewOutcomeService) get(ctx context.Context, userId, documentId string) (entity.Document, entity.User, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return entity.Document{}, entity.User{}, err
+	}
+
+	document, err := s.documentRepository.GetByID(ctx, nil, documentId,
+		"DisciplineListDocuments.Comments.Severity",
+		"DisciplineListDocuments.Consolidators.DisciplineGroupConsolidator")
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Document{}, entity.User{}, myerror.New("document not found", http.StatusNotFound)
+		}
+		return entity.Document{}, entity.User{}, err
+	}
+
+	if user.PackageID != nil && document.PackageID != *user.PackageID {
+		return entity.Document{}, entity.User{}, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	return document, user, nil
+}
+
+func (s *reviewOutcomeService) isConsolidator(ctx context.Context, user entity.User, document entity.Document) (bool, error) {
+	if user.PackageID == nil {
+		return true, nil
+	}
+
+	for _, disciplineListDocument := range document.DisciplineListDocuments {
+		for _, consolidator := range disciplineListDocument.Consolidators {
+			if consolidator.DisciplineGroupConsolidator == nil {
+				continue
+			}
+
+			ok, err := actsFor(ctx, s.delegationRepository, user.ID, consolidator.DisciplineGroupConsolidator.UserID)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// suggestReviewOutcome applies the rules to the comments of the CRS on the
+// revision under review:
+//   - without comments the document is approved
+//   - a comment makes it approved with comments, unless its severity leads
+//     to a worse outcome
+//   - a comment closed out as rejected no longer holds the document back,
+//     it counts as approved with comments at most
+//
+// The worst outcome of all comments wins.
+func suggestReviewOutcome(document entity.Document) dto.ReviewOutcomeSuggestion {
+	counts := map[entity.ReviewOutcomeCode]int{}
+	outcome := entity.ReviewOutcomeApproved
+	for _, disciplineListDocument := range document.DisciplineListDocuments {
+		for _, comment := range disciplineListDocument.Comments {
+			if !comment.InOfficialSet() || !document.OnCurrentRevision(&comment) {
+				continue
+			}
+
+			code := entity.ReviewOutcomeApprovedWithComments
+			if comment.Severity != nil && comment.Severity.ReviewOutcome != nil {
+				code = *comment.Severity.ReviewOutcome
+			}
+
+			if comment.Status != nil && *comment.Status == entity.CommentStatusReject &&
+				code.Number() > entity.ReviewOutcomeApprovedWithComments.Number() {
+				code = entity.ReviewOutcomeApprovedWithComments
+			}
+
+			counts[code]++
+			if code.Number() > outcome.Number() {
+				outcome = code
+			}
+		}
+	}
+
+	reasons := []string{}
+	if len(counts) == 0 {
+		reasons = append(reasons, "no comments in the CRS")
+	}
+
+	for i := len(entity.ReviewOutcomeCodes) - 1; i >= 0; i-- {
+		code := entity.ReviewOutcomeCodes[i]
+		if counts[code] > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d comment(s) lead to %s", counts[code], code.Label()))
+		}
+	}
+
+	return dto.ReviewOutcomeSuggestion{
+		Code:    reviewOutcomeCode(outcome),
+		Reasons: reasons,
+	}
+}
+
+func reviewOutcomeCode(code entity.ReviewOutcomeCode) dto.ReviewOutcomeCode {
+	return dto.ReviewOutcomeCode{
+		Code:   string(code),
+		Number: code.Number(),
+		Label:  code.Label(),
+	}
+}
+
+// reviewOutcomeLabel is what the CRS header shows, empty until the outcome of
+// the revision is set
+func reviewOutcomeLabel(document *entity.Document) string {
+	if document == nil || document.CurrentReviewOutcome() == nil {
+		return ""
+	}
+
+	return document.CurrentReviewOutcome().Code.Label()
+}
+
+func reviewOutcomeResponse(reviewOutcome entity.ReviewOutcome) dto.ReviewOutcomeResponse {
+	res := dto.ReviewOutcomeResponse{
+		ID:       reviewOutcome.ID.String(),
+		Revision: reviewOutcome.Revision,
+		Code:     reviewOutcomeCode(reviewOutcome.Code),
+		Remarks:  reviewOutcome.Remarks,
+		SetAt:    reviewOutcome.SetAt.Format("15.04 • 02 Jan 2006"),
+	}
+
+	if reviewOutcome.SuggestedCode != "" {
+		suggested := reviewOutcomeCode(reviewOutcome.SuggestedCode)
+		res.Suggested = &suggested
+	}
+
+	if reviewOutcome.SetBy != nil {
+		res.SetBy = userComment(reviewOutcome.SetBy)
+	}
+
+	return res
+}
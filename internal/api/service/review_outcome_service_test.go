@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+)
+
+func TestSuggestReviewOutcomeCurrentRevision(t *testing.T) {
+	issuedAt := time.Now().AddDate(0, 0, -3)
+	official := func(createdAt time.Time, anchorRevision string) entity.Comment {
+		comment := entity.Comment{Stage: entity.CommentStageOfficial, Visibility: entity.CommentVisibilityPublic, AnchorRevision: anchorRevision}
+		comment.CreatedAt = createdAt
+		return comment
+	}
+
+	for _, tt := range []struct {
+		name    string
+		comment entity.Comment
+		want    entity.ReviewOutcomeCode
+	}{
+		{"written on revision A", official(issuedAt.AddDate(0, 0, -10), ""), entity.ReviewOutcomeApproved},
+		{"anchored on revision A", official(issuedAt.AddDate(0, 0, 1), "A"), entity.ReviewOutcomeApproved},
+		{"written on revision B", official(issuedAt.AddDate(0, 0, 1), ""), entity.ReviewOutcomeApprovedWithComments},
+		{"anchored on revision B", official(issuedAt.AddDate(0, 0, 1), "B"), entity.ReviewOutcomeApprovedWithComments},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			document := entity.Document{
+				Revision: "B",
+				IssuedAt: &issuedAt,
+				DisciplineListDocuments: []entity.DisciplineListDocument{
+					{Comments: []entity.Comment{tt.comment}},
+				},
+			}
+
+			if got := suggestReviewOutcome(document).Code.Code; got != string(tt.want) {
+				t.Errorf("suggestReviewOutcome() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	ReviewWindowService interface {
+		GetAll(ctx context.Context, userId, packageId, disciplineGroupId string) ([]dto.ReviewWindowResponse, error)
+		Create(ctx context.Context, req dto.ReviewWindowRequest) (dto.ReviewWindowResponse, error)
+		Update(ctx context.Context, req dto.ReviewWindowRequest) (dto.ReviewWindowResponse, error)
+		Delete(ctx context.Context, userId, packageId, reviewWindowId string) error
+	}
+
+	reviewWindowService struct {
+		reviewWindowRepository    repository.ReviewWindowRepository
+		disciplineGroupRepository repository.DisciplineGroupRepository
+		packageRepository         repository.PackageRepository
+		userRepository            repository.UserRepository
+		db                        *gorm.DB
+	}
+)
+
+func NewReviewWindow(reviewWindowRepository repository.ReviewWindowRepository,
+	disciplineGroupRepository repository.DisciplineGroupRepository,
+	packageRepository repository.PackageRepository,
+	userRepository repository.UserRepository,
+	db *gorm.DB) ReviewWindowService {
+	return &reviewWindowService{
+		reviewWindowRepository:    reviewWindowRepository,
+		disciplineGroupRepository: disciplineGroupRepository,
+		packageRepository:         packageRepository,
+		userRepository:            userRepository,
+		db:                        db,
+	}
+}
+
+func (s *reviewWindowService) GetAll(ctx context.Context, userId, packageId, disciplineGroupId string) ([]dto.ReviewWindowResponse, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.PackageID != nil && user.PackageID.String() != packageId {
+		return nil, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	reviewWindows, err := s.reviewWindowRepository.GetAllByPackageID(ctx, nil, packageId, disciplineGroupId, "DisciplineGroup")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	res := []dto.ReviewWindowResponse{}
+	for _, reviewWindow := range reviewWindows {
+		res = append(res, reviewWindowResponse(reviewWindow, now))
+	}
+
+	return res, nil
+}
+
+func (s *reviewWindowService) Create(ctx context.Context, req dto.ReviewWindowRequest) (dto.ReviewWindowResponse, error) {
+	pkg, err := s.packageRepository.GetByID(ctx, nil, req.PackageID)
+	if err != nil {
+		return dto.ReviewWindowResponse{}, err
+	}
+
+	reviewWindow := entity.ReviewWindow{
+		PackageID: pkg.ID,
+	}
+	if err := s.fill(ctx, &reviewWindow, req); err != nil {
+		return dto.ReviewWindowResponse{}, err
+	}
+
+	reviewWindow, err = s.reviewWindowRepository.Create(ctx, nil, reviewWindow)
+	if err != nil {
+		return dto.ReviewWindowResponse{}, err
+	}
+
+	return reviewWindowResponse(reviewWindow, time.Now()), nil
+}
+
+func (s *reviewWindowService) Update(ctx context.Context, req dto.ReviewWindowRequest) (dto.ReviewWindowResponse, error) {
+	reviewWindow, err := s.get(ctx, req.PackageID, req.ID)
+	if err != nil {
+		return dto.ReviewWindowResponse{}, err
+	}
+
+	if err := s.fill(ctx, &reviewWindow, req); err != nil {
+		return dto.ReviewWindowResponse{}, err
+	}
+
+	reviewWindow, err = s.reviewWindowRepository.Update(ctx, nil, reviewWindow)
+	if err != nil {
+		return dto.ReviewWindowResponse{}, err
+	}
+
+	return reviewWindowResponse(reviewWindow, time.Now()), nil
+}
+
+func (s *reviewWindowService) Delete(ctx context.Context, userId, packageId, reviewWindowId string) error {
+	reviewWindow, err := s.get(ctx, packageId, reviewWindowId)
+	if err != nil {
+		return err
+	}
+
+	// mark who deleted
+	reviewWindow.DeletedBy = uuid.MustParse(userId)
+	return s.reviewWindowRepository.Delete(ctx, nil, reviewWindow)
+}
+
+func (s *reviewWindowService) fill(ctx context.Context, reviewWindow *entity.ReviewWindow, req dto.ReviewWindowRequest) error {
+	reviewWindow.Name = strings.TrimSpace(req.Name)
+	reviewWindow.OpensAt = req.OpensAt
+	reviewWindow.ClosesAt = req.ClosesAt
+	reviewWindow.UpdatedBy = uuid.MustParse(req.UserId)
+
+	if reviewWindow.Name == "" {
+		return myerror.New("name is required", http.StatusBadRequest)
+	}
+
+	if !reviewWindow.ClosesAt.After(reviewWindow.OpensAt) {
+		return myerror.New("a review window must close after it opens", http.StatusBadRequest)
+	}
+
+	reviewWindow.DisciplineGroupID = nil
+	reviewWindow.DisciplineGroup = nil
+	if req.DisciplineGroupID != nil && *req.DisciplineGroupID != "" {
+		disciplineGroup, err := s.disciplineGroupRepository.GetByID(ctx, nil, *req.DisciplineGroupID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return myerror.New("discipline group not found", http.StatusNotFound)
+			}
+			return err
+		}
+
+		if disciplineGroup.PackageID != reviewWindow.PackageID {
+			return myerror.New("discipline group must belong to this package", http.StatusBadRequest)
+		}
+
+		reviewWindow.DisciplineGroupID = &disciplineGroup.ID
+		reviewWindow.DisciplineGroup = &disciplineGroup
+	}
+
+	return nil
+}
+
+func (s *reviewWindowService) get(ctx context.Context, packageId, reviewWindowId string) (entity.ReviewWindow, error) {
+	reviewWindow, err := s.reviewWindowRepository.GetByID(ctx, nil, reviewWindowId, "DisciplineGroup")
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ReviewWindow{}, myerror.New("review window not found", http.StatusNotFound)
+		}
+		return entity.ReviewWindow{}, err
+	}
+
+	if reviewWindow.PackageID.String() != packageId {
+		return entity.ReviewWindow{}, myerror.New("review window not found", http.StatusNotFound)
+	}
+
+	return reviewWindow, nil
+}
+
+func reviewWindowResponse(reviewWindow entity.ReviewWindow, now time.Time) dto.ReviewWindowResponse {
+	res := dto.ReviewWindowResponse{
+		ID:        reviewWindow.ID.String(),
+		Name:      reviewWindow.Name,
+		OpensAt:   reviewWindow.OpensAt,
+		ClosesAt:  reviewWindow.ClosesAt,
+		IsOpen:    reviewWindow.IsOpen(now),
+		PackageID: reviewWindow.PackageID.String(),
+	}
+
+	if reviewWindow.DisciplineGroup != nil {
+		res.DisciplineGroup = &dto.ReviewWindowDisciplineGroup{
+			ID:          reviewWindow.DisciplineGroup.ID.String(),
+			ReviewFocus: reviewWindow.DisciplineGroup.ReviewFocus,
+		}
+	}
+
+	return res
+}
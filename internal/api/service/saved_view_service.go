@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"gorm.io/gorm"
+)
+
+type (
+	SavedViewService interface {
+		Create(ctx context.Context, req dto.SavedViewRequest) (dto.SavedViewResponse, error)
+		GetAll(ctx context.Context, userId, resource string) ([]dto.SavedViewResponse, error)
+		GetByID(ctx context.Context, userId, savedViewId string) (dto.SavedViewResponse, error)
+		Update(ctx context.Context, req dto.SavedViewRequest) (dto.SavedViewResponse, error)
+		Delete(ctx context.Context, userId, savedViewId string) error
+	}
+
+	savedViewService struct {
+		savedViewRepository repository.SavedViewRepository
+		userRepository      repository.UserRepository
+		packageRepository   repository.PackageRepository
+		db                  *gorm.DB
+	}
+)
+
+func NewSavedView(savedViewRepository repository.SavedViewRepository,
+	userRepository repository.UserRepository,
+	packageRepository repository.PackageRepository,
+	db *gorm.DB) SavedViewService {
+	return &savedViewService{
+		savedViewRepository: savedViewRepository,
+		userRepository:      userRepository,
+		packageRepository:   packageRepository,
+		db:                  db,
+	}
+}
+
+func (s *savedViewService) Create(ctx context.Context, req dto.SavedViewRequest) (dto.SavedViewResponse, error) {
+	user, err := s.userRepository.GetById(ctx, nil, req.UserId)
+	if err != nil {
+		return dto.SavedViewResponse{}, err
+	}
+
+	savedView := entity.SavedView{
+		UserID: user.ID,
+	}
+	if err := s.fill(ctx, &savedView, user, req); err != nil {
+		return dto.SavedViewResponse{}, err
+	}
+
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if savedView.IsDefault {
+			if err := s.savedViewRepository.ClearDefault(ctx, nil, savedView.PackageID.String(), savedView.Resource, ""); err != nil {
+				return err
+			}
+		}
+
+		savedView, err = s.savedViewRepository.Create(ctx, nil, savedView)
+		return err
+	})
+	if err != nil {
+		return dto.SavedViewResponse{}, err
+	}
+
+	return s.getResponse(ctx, user, savedView.ID.String())
+}
+
+func (s *savedViewService) GetAll(ctx context.Context, userId, resource string) ([]dto.SavedViewResponse, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	savedViews, err := s.savedViewRepository.GetAllVisible(ctx, nil, user, strings.ToUpper(resource), "User", "Package")
+	if err != nil {
+		return nil, err
+	}
+
+	res := []dto.SavedViewResponse{}
+	for _, savedView := range savedViews {
+		res = append(res, savedViewResponse(savedView, user))
+	}
+
+	return res, nil
+}
+
+func (s *savedViewService) GetByID(ctx context.Context, userId, savedViewId string) (dto.SavedViewResponse, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return dto.SavedViewResponse{}, err
+	}
+
+	return s.getResponse(ctx, user, savedViewId)
+}
+
+func (s *savedViewService) Update(ctx context.Context, req dto.SavedViewRequest) (dto.SavedViewResponse, error) {
+	user, savedView, err := s.getOwned(ctx, req.UserId, req.ID)
+	if err != nil {
+		return dto.SavedViewResponse{}, err
+	}
+
+	if err := s.fill(ctx, &savedView, user, req); err != nil {
+		return dto.SavedViewResponse{}, err
+	}
+
+	savedView.UpdatedBy = user.ID
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if savedView.IsDefault {
+			if err := s.savedViewRepository.ClearDefault(ctx, nil, savedView.PackageID.String(), savedView.Resource, savedView.ID.String()); err != nil {
+				return err
+			}
+		}
+
+		_, err := s.savedViewRepository.Update(ctx, nil, savedView)
+		return err
+	})
+	if err != nil {
+		return dto.SavedViewResponse{}, err
+	}
+
+	return s.getResponse(ctx, user, savedView.ID.String())
+}
+
+func (s *savedViewService) Delete(ctx context.Context, userId, savedViewId string) error {
+	user, savedView, err := s.getOwned(ctx, userId, savedViewId)
+	if err != nil {
+		return err
+	}
+
+	savedView.DeletedBy = user.ID
+	return s.savedViewRepository.Delete(ctx, nil, savedView)
+}
+
+// fill validates the request and copies it into the view. Views of package
+// users always belong to their package, a super admin names the package to
+// share with or publish for.
+func (s *savedViewService) fill(ctx context.Context, savedView *entity.SavedView, user entity.User, req dto.SavedViewRequest) error {
+	if req.IsDefault && user.PackageID != nil {
+		return myerror.New("only super admin can publish a default view", http.StatusUnauthorized)
+	}
+
+	packageId := user.PackageID
+	if user.PackageID == nil {
+		if req.PackageID != nil {
+			pkg, err := s.packageRepository.GetByID(ctx, nil, *req.PackageID)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return myerror.New("package not found", http.StatusNotFound)
+				}
+				return err
+			}
+			packageId = &pkg.ID
+		}
+
+		if (req.IsShared || req.IsDefault) && packageId == nil {
+			return myerror.New("package_id is required to share a view", http.StatusBadRequest)
+		}
+	} else if req.PackageID != nil && *req.PackageID != user.PackageID.String() {
+		return myerror.New("you can only share a view with your package", http.StatusUnauthorized)
+	}
+
+	if req.Take > meta.MaxTake() {
+		return myerror.New("take is over the page size limit", http.StatusBadRequest)
+	}
+
+	var columns []string
+	seen := map[string]bool{}
+	for _, column := range req.Columns {
+		column = strings.TrimSpace(column)
+		if column == "" || seen[column] {
+			continue
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+
+	savedView.Name = strings.TrimSpace(req.Name)
+	savedView.Resource = entity.SavedViewResource(req.Resource)
+	savedView.Take = req.Take
+	savedView.Sort = req.Sort
+	savedView.SortBy = req.SortBy
+	savedView.Filter = req.Filter
+	savedView.FilterBy = req.FilterBy
+	savedView.Query = req.Query
+	savedView.Columns = columns
+	savedView.IsShared = req.IsShared
+	savedView.IsDefault = req.IsDefault
+	savedView.PackageID = packageId
+
+	if err := s.savedViewRepository.Validate(*savedView); err != nil {
+		return myerror.New(err.Error(), http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// getOwned returns the view if the user created it or is a super admin
+func (s *savedViewService) getOwned(ctx context.Context, userId, savedViewId string) (entity.User, entity.SavedView, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return entity.User{}, entity.SavedView{}, err
+	}
+
+	savedView, err := s.savedViewRepository.GetByID(ctx, nil, savedViewId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.User{}, entity.SavedView{}, myerror.New("view not found", http.StatusNotFound)
+		}
+		return entity.User{}, entity.SavedView{}, err
+	}
+
+	if user.PackageID != nil && savedView.UserID != user.ID {
+		return entity.User{}, entity.SavedView{}, myerror.New("you don't have permission for this view", http.StatusUnauthorized)
+	}
+
+	return user, savedView, nil
+}
+
+func (s *savedViewService) getResponse(ctx context.Context, user entity.User, savedViewId string) (dto.SavedViewResponse, error) {
+	savedView, err := s.savedViewRepository.GetByID(ctx, nil, savedViewId, "User", "Package")
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.SavedViewResponse{}, myerror.New("view not found", http.StatusNotFound)
+		}
+		return dto.SavedViewResponse{}, err
+	}
+
+	if !savedView.VisibleTo(user) {
+		return dto.SavedViewResponse{}, myerror.New("you don't have permission for this view", http.StatusUnauthorized)
+	}
+
+	return savedViewResponse(savedView, user), nil
+}
+
+func savedViewResponse(savedView entity.SavedView, user entity.User) dto.SavedViewResponse {
+	res := dto.SavedViewResponse{
+		ID:        savedView.ID.String(),
+		Name:      savedView.Name,
+		Resource:  string(savedView.Resource),
+		Take:      savedView.Take,
+		Sort:      savedView.Sort,
+		SortBy:    savedView.SortBy,
+		Filter:    savedView.Filter,
+		FilterBy:  savedView.FilterBy,
+		Query:     savedView.Query,
+		Columns:   savedView.Columns,
+		IsShared:  savedView.IsShared,
+		IsDefault: savedView.IsDefault,
+		IsOwner:   savedView.UserID == user.ID,
+	}
+
+	if res.Columns == nil {
+		res.Columns = []string{}
+	}
+
+	if savedView.PackageID != nil {
+		packageId := savedView.PackageID.String()
+		res.PackageID = &packageId
+	}
+
+	if savedView.Package != nil {
+		res.PackageName = &savedView.Package.Name
+	}
+
+	if savedView.User != nil {
+		res.User = userComment(savedView.User)
+	}
+
+	return res
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"gorm.io/gorm"
+)
+
+const (
+	searchTypeDocument = "DOCUMENT"
+	searchTypeComment  = "COMMENT"
+)
+
+type (
+	SearchService interface {
+		Search(ctx context.Context, req dto.SearchRequest, metaReq meta.Meta) ([]dto.SearchResult, meta.Meta, error)
+	}
+
+	searchService struct {
+		searchRepository repository.SearchRepository
+		userRepository   repository.UserRepository
+		db               *gorm.DB
+	}
+)
+
+func NewSearch(searchRepository repository.SearchRepository,
+	userRepository repository.UserRepository,
+	db *gorm.DB) SearchService {
+	return &searchService{
+		searchRepository: searchRepository,
+		userRepository:   userRepository,
+		db:               db,
+	}
+}
+
+// Search looks for the keyword in documents and comments of the user's
+// package, every package for a super admin. Each word matches as a prefix,
+// so a fragment of a word or a document number is enough.
+func (s *searchService) Search(ctx context.Context, req dto.SearchRequest, metaReq meta.Meta) ([]dto.SearchResult, meta.Meta, error) {
+	user, err := s.userRepository.GetById(ctx, nil, req.UserId)
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	query := searchTsQuery(req.Keyword)
+	if query == "" {
+		return nil, meta.Meta{}, myerror.New("keyword is required", http.StatusBadRequest)
+	}
+
+	params := dto.SearchParams{
+		Query:     query,
+		Pattern:   "%" + repository.EscapeLike(strings.TrimSpace(req.Keyword)) + "%",
+		Documents: true,
+		Comments:  true,
+	}
+
+	switch strings.ToUpper(req.Type) {
+	case "":
+	case searchTypeDocument:
+		params.Comments = false
+	case searchTypeComment:
+		params.Documents = false
+	default:
+		return nil, meta.Meta{}, myerror.New("type must be DOCUMENT or COMMENT", http.StatusBadRequest)
+	}
+
+	if user.PackageID != nil {
+		params.PackageID = user.PackageID.String()
+	}
+
+	// contractors only see the public comments of the consolidated CRS
+	params.PublicOnly = user.Role == entity.RoleContractor
+
+	params.Offset, params.Limit = metaReq.GetSkipAndLimit()
+
+	results, total, err := s.searchRepository.Search(ctx, nil, params)
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	metaReq.Count(int(total))
+	if results == nil {
+		results = []dto.SearchResult{}
+	}
+
+	return results, metaReq, nil
+}
+
+// searchTsQuery turns free text into a tsquery where every word must match
+// as a prefix, anything but letters and digits is dropped
+func searchTsQuery(keyword string) string {
+	words := strings.FieldsFunc(keyword, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = strings.ToLower(word) + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	mypdf "github.com/CRS-Project/crs-backend/internal/pkg/pdf"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const signOffArtifactDir = "signoff"
+
+type (
+	SignOffService interface {
+		Create(ctx context.Context, req dto.SignOffRequest) (dto.SignOffResponse, error)
+		GetByID(ctx context.Context, userId, signOffId string) (dto.SignOffResponse, error)
+		Approve(ctx context.Context, req dto.SignOffActionRequest) (dto.SignOffResponse, error)
+		Reject(ctx context.Context, req dto.SignOffActionRequest) (dto.SignOffResponse, error)
+		GetArtifact(ctx context.Context, userId, signOffId string) (string, string, error)
+		Verify(ctx context.Context, req dto.SignOffVerifyRequest) (dto.SignOffVerifyResponse, error)
+	}
+
+	signOffService struct {
+		signOffRepository      repository.SignOffRepository
+		userRepository         repository.UserRepository
+		packageService         PackageService
+		disciplineGroupService DisciplineGroupService
+		db                     *gorm.DB
+	}
+)
+
+func NewSignOff(signOffRepository repository.SignOffRepository,
+	userRepository repository.UserRepository,
+	packageService PackageService,
+	disciplineGroupService DisciplineGroupService,
+	db *gorm.DB) SignOffService {
+	return &signOffService{
+		signOffRepository:      signOffRepository,
+		userRepository:         userRepository,
+		packageService:         packageService,
+		disciplineGroupService: disciplineGroupService,
+		db:                     db,
+	}
+}
+
+func (s *signOffService) Create(ctx context.Context, req dto.SignOffRequest) (dto.SignOffResponse, error) {
+	if (req.PackageID == nil) == (req.DisciplineGroupID == nil) {
+		return dto.SignOffResponse{}, myerror.New("fill exactly one of package_id or discipline_group_id", http.StatusBadRequest)
+	}
+
+	signOff := entity.SignOff{
+		Status: entity.SignOffStatusInProgress,
+		UserID: uuid.MustParse(req.UserId),
+	}
+
+	if req.PackageID != nil {
+		packageId, err := uuid.Parse(*req.PackageID)
+		if err != nil {
+			return dto.SignOffResponse{}, myerror.New("package_id must be a valid id", http.StatusBadRequest)
+		}
+		signOff.Scope = entity.SignOffScopePackage
+		signOff.PackageID = packageId
+	} else {
+		disciplineGroupId, err := uuid.Parse(*req.DisciplineGroupID)
+		if err != nil {
+			return dto.SignOffResponse{}, myerror.New("discipline_group_id must be a valid id", http.StatusBadRequest)
+		}
+		signOff.Scope = entity.SignOffScopeDisciplineGroup
+		signOff.DisciplineGroupID = &disciplineGroupId
+	}
+
+	requestData, _, packageId, err := s.buildReport(ctx, signOff)
+	if err != nil {
+		return dto.SignOffResponse{}, err
+	}
+	signOff.PackageID = packageId
+	signOff.ContentHash = mypdf.ContentHash(requestData)
+
+	seen := make(map[string]bool)
+	for i, step := range req.Steps {
+		user, err := s.userRepository.GetById(ctx, nil, step.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.SignOffResponse{}, myerror.New(fmt.Sprintf("user of step %d not found", i+1), http.StatusNotFound)
+			}
+			return dto.SignOffResponse{}, err
+		}
+
+		if user.PackageID != nil && *user.PackageID != packageId {
+			return dto.SignOffResponse{}, myerror.New(fmt.Sprintf("user of step %d is not in this package", i+1), http.StatusBadRequest)
+		}
+
+		if seen[step.Role] {
+			return dto.SignOffResponse{}, myerror.New(fmt.Sprintf("role %s is used more than once", step.Role), http.StatusBadRequest)
+		}
+		seen[step.Role] = true
+
+		signOff.Steps = append(signOff.Steps, entity.SignOffStep{
+			Sequence: i + 1,
+			Role:     step.Role,
+			Status:   entity.SignOffStepStatusPending,
+			UserID:   user.ID,
+		})
+	}
+
+	signOff, err = s.signOffRepository.Create(ctx, nil, signOff)
+	if err != nil {
+		return dto.SignOffResponse{}, err
+	}
+
+	return s.getResponse(ctx, signOff.ID.String())
+}
+
+func (s *signOffService) GetByID(ctx context.Context, userId, signOffId string) (dto.SignOffResponse, error) {
+	signOff, err := s.signOffRepository.GetByID(ctx, nil, signOffId, "Steps.User")
+	if err != nil {
+		return dto.SignOffResponse{}, err
+	}
+
+	if err := s.checkPermission(ctx, userId, signOff); err != nil {
+		return dto.SignOffResponse{}, err
+	}
+
+	return signOffResponse(signOff), nil
+}
+
+func (s *signOffService) Approve(ctx context.Context, req dto.SignOffActionRequest) (dto.SignOffResponse, error) {
+	signOff, step, err := s.getCurrentStep(ctx, req.UserId, req.SignOffId)
+	if err != nil {
+		return dto.SignOffResponse{}, err
+	}
+
+	// the data must still be what every earlier approver signed
+	requestData, tpl, _, err := s.buildReport(ctx, signOff)
+	if err != nil {
+		return dto.SignOffResponse{}, err
+	}
+
+	contentHash := mypdf.ContentHash(requestData)
+	if contentHash != signOff.ContentHash {
+		return dto.SignOffResponse{}, myerror.New("the CRS changed after the sign-off started, reject it and start a new sign-off", http.StatusConflict)
+	}
+
+	now := time.Now()
+	step.Status = entity.SignOffStepStatusApproved
+	step.ContentHash = &contentHash
+	step.Note = req.Note
+	step.ActedAt = &now
+
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if _, err := s.signOffRepository.UpdateStep(ctx, nil, *step); err != nil {
+			return err
+		}
+
+		if signOff.CurrentStep() != nil {
+			return nil
+		}
+
+		return s.complete(ctx, signOff, requestData, tpl)
+	})
+	if err != nil {
+		return dto.SignOffResponse{}, err
+	}
+
+	return s.getResponse(ctx, signOff.ID.String())
+}
+
+func (s *signOffService) Reject(ctx context.Context, req dto.SignOffActionRequest) (dto.SignOffResponse, error) {
+	signOff, step, err := s.getCurrentStep(ctx, req.UserId, req.SignOffId)
+	if err != nil {
+		return dto.SignOffResponse{}, err
+	}
+
+	now := time.Now()
+	step.Status = entity.SignOffStepStatusRejected
+	step.Note = req.Note
+	step.ActedAt = &now
+	signOff.Status = entity.SignOffStatusRejected
+	signOff.UpdatedBy = uuid.MustParse(req.UserId)
+
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if _, err := s.signOffRepository.UpdateStep(ctx, nil, *step); err != nil {
+			return err
+		}
+
+		_, err := s.signOffRepository.Update(ctx, nil, signOff)
+		return err
+	})
+	if err != nil {
+		return dto.SignOffResponse{}, err
+	}
+
+	return s.getResponse(ctx, signOff.ID.String())
+}
+
+func (s *signOffService) GetArtifact(ctx context.Context, userId, signOffId string) (string, string, error) {
+	signOff, err := s.signOffRepository.GetByID(ctx, nil, signOffId)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := s.checkPermission(ctx, userId, signOff); err != nil {
+		return "", "", err
+	}
+
+	if signOff.Status != entity.SignOffStatusCompleted || signOff.ArtifactPath == nil {
+		return "", "", myerror.New("sign-off is not completed yet", http.StatusBadRequest)
+	}
+
+	return *signOff.ArtifactPath, "comment_resolution_sheet_signed.pdf", nil
+}
+
+func (s *signOffService) Verify(ctx context.Context, req dto.SignOffVerifyRequest) (dto.SignOffVerifyResponse, error) {
+	file, err := req.File.Open()
+	if err != nil {
+		return dto.SignOffVerifyResponse{}, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return dto.SignOffVerifyResponse{}, err
+	}
+
+	sum := sha256.Sum256(content)
+	res := dto.SignOffVerifyResponse{FileHash: hex.EncodeToString(sum[:])}
+
+	signOff, err := s.signOffRepository.GetByFileHash(ctx, nil, res.FileHash, "Steps.User")
+	if err == nil {
+		signOffRes := signOffResponse(signOff)
+		res.Valid = true
+		res.Message = "file is identical to the signed CRS"
+		res.ContentHash = &signOff.ContentHash
+		res.SignOff = &signOffRes
+		return res, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.SignOffVerifyResponse{}, err
+	}
+
+	contentHash, ok := mypdf.ExtractContentHash(content)
+	if !ok {
+		res.Message = "file is not a signed CRS"
+		return res, nil
+	}
+	res.ContentHash = &contentHash
+
+	signOff, err = s.signOffRepository.GetByContentHash(ctx, nil, contentHash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		res.Message = "file carries a content hash that no completed sign-off approved"
+		return res, nil
+	} else if err != nil {
+		return dto.SignOffVerifyResponse{}, err
+	}
+
+	signOffRes := signOffResponse(signOff)
+	res.Message = "file was modified after it was signed"
+	res.SignOff = &signOffRes
+	return res, nil
+}
+
+// complete renders the signed sheet once the last step is approved and keeps
+// the hash of the file for verification
+func (s *signOffService) complete(ctx context.Context, signOff entity.SignOff, requestData []mypdf.GenerateRequestData, tpl mypdf.Template) error {
+	approval := mypdf.ApprovalBlock{ContentHash: signOff.ContentHash}
+	for _, step := range signOff.Steps {
+		name := step.UserID.String()
+		if step.User != nil {
+			name = step.User.Name
+		}
+
+		approval.Approvals = append(approval.Approvals, mypdf.Approval{
+			Role:        step.Role,
+			Name:        name,
+			ApprovedAt:  step.ActedAt.Format("02 Jan 2006 15:04"),
+			ContentHash: *step.ContentHash,
+		})
+	}
+
+	buf, _, err := mypdf.GenerateSigned(requestData, tpl, approval)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(utils.PATH, signOffArtifactDir)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, signOff.ID.String()+".pdf")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	fileHash := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	signOff.Status = entity.SignOffStatusCompleted
+	signOff.FileHash = &fileHash
+	signOff.ArtifactPath = &path
+	signOff.CompletedAt = &now
+	if _, err := s.signOffRepository.Update(ctx, nil, signOff); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getCurrentStep returns the step waiting for approval, only when it belongs
+// to the user
+func (s *signOffService) getCurrentStep(ctx context.Context, userId, signOffId string) (entity.SignOff, *entity.SignOffStep, error) {
+	signOff, err := s.signOffRepository.GetByID(ctx, nil, signOffId, "Steps.User")
+	if err != nil {
+		return entity.SignOff{}, nil, err
+	}
+
+	if signOff.Status != entity.SignOffStatusInProgress {
+		return entity.SignOff{}, nil, myerror.New("sign-off is already "+string(signOff.Status), http.StatusBadRequest)
+	}
+
+	step := signOff.CurrentStep()
+	if step == nil || step.UserID.String() != userId {
+		return entity.SignOff{}, nil, myerror.New("it is not your turn to sign off", http.StatusUnauthorized)
+	}
+
+	return signOff, step, nil
+}
+
+func (s *signOffService) buildReport(ctx context.Context, signOff entity.SignOff) ([]mypdf.GenerateRequestData, mypdf.Template, uuid.UUID, error) {
+	if signOff.Scope == entity.SignOffScopeDisciplineGroup {
+		requestData, tpl, disciplineGroup, err := s.disciplineGroupService.BuildReport(ctx, signOff.DisciplineGroupID.String())
+		if err != nil {
+			return nil, mypdf.Template{}, uuid.Nil, err
+		}
+
+		return requestData, tpl, disciplineGroup.PackageID, nil
+	}
+
+	requestData, tpl, err := s.packageService.BuildReport(ctx, signOff.PackageID.String())
+	if err != nil {
+		return nil, mypdf.Template{}, uuid.Nil, err
+	}
+
+	return requestData, tpl, signOff.PackageID, nil
+}
+
+func (s *signOffService) checkPermission(ctx context.Context, userId string, signOff entity.SignOff) error {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return err
+	}
+
+	if user.PackageID != nil && *user.PackageID != signOff.PackageID {
+		return myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	return nil
+}
+
+func (s *signOffService) getResponse(ctx context.Context, signOffId string) (dto.SignOffResponse, error) {
+	signOff, err := s.signOffRepository.GetByID(ctx, nil, signOffId, "Steps.User")
+	if err != nil {
+		return dto.SignOffResponse{}, err
+	}
+
+	return signOffResponse(signOff), nil
+}
+
+func signOffResponse(signOff entity.SignOff) dto.SignOffResponse {
+	res := dto.SignOffResponse{
+		ID:          signOff.ID.String(),
+		Scope:       string(signOff.Scope),
+		Status:      string(signOff.Status),
+		PackageID:   signOff.PackageID.String(),
+		ContentHash: signOff.ContentHash,
+		FileHash:    signOff.FileHash,
+		CompletedAt: signOff.CompletedAt,
+		CreatedAt:   signOff.CreatedAt,
+	}
+
+	if signOff.DisciplineGroupID != nil {
+		disciplineGroupId := signOff.DisciplineGroupID.String()
+		res.DisciplineGroupID = &disciplineGroupId
+	}
+
+	for _, step := range signOff.Steps {
+		stepRes := dto.SignOffStepResponse{
+			ID:          step.ID.String(),
+			Sequence:    step.Sequence,
+			Role:        step.Role,
+			Status:      string(step.Status),
+			UserID:      step.UserID.String(),
+			ContentHash: step.ContentHash,
+			Note:        step.Note,
+			ActedAt:     step.ActedAt,
+		}
+		if step.User != nil {
+			stepRes.Name = step.User.Name
+		}
+		res.Steps = append(res.Steps, stepRes)
+	}
+
+	return res
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	SLAService interface {
+		GetAllRules(ctx context.Context, userId, packageId string) ([]dto.SLARuleResponse, error)
+		CreateRule(ctx context.Context, req dto.SLARuleRequest) (dto.SLARuleResponse, error)
+		UpdateRule(ctx context.Context, req dto.SLARuleRequest) (dto.SLARuleResponse, error)
+		DeleteRule(ctx context.Context, userId, packageId, slaRuleId string) error
+		GetAllHolidays(ctx context.Context, userId, packageId string, year int) ([]dto.HolidayResponse, error)
+		CreateHoliday(ctx context.Context, req dto.HolidayRequest) (dto.HolidayResponse, error)
+		UpdateHoliday(ctx context.Context, req dto.HolidayRequest) (dto.HolidayResponse, error)
+		DeleteHoliday(ctx context.Context, userId, packageId, holidayId string) error
+		DueDate(ctx context.Context, pkg entity.Package, purpose entity.TransmittalPurpose, resubmission bool, issuedAt time.Time) (time.Time, error)
+	}
+
+	slaService struct {
+		slaRuleRepository repository.SLARuleRepository
+		holidayRepository repository.HolidayRepository
+		packageRepository repository.PackageRepository
+		userRepository    repository.UserRepository
+		db                *gorm.DB
+	}
+)
+
+func NewSLA(slaRuleRepository repository.SLARuleRepository,
+	holidayRepository repository.HolidayRepository,
+	packageRepository repository.PackageRepository,
+	userRepository repository.UserRepository,
+	db *gorm.DB) SLAService {
+	return &slaService{
+		slaRuleRepository: slaRuleRepository,
+		holidayRepository: holidayRepository,
+		packageRepository: packageRepository,
+		userRepository:    userRepository,
+		db:                db,
+	}
+}
+
+func (s *slaService) GetAllRules(ctx context.Context, userId, packageId string) ([]dto.SLARuleResponse, error) {
+	if err := s.checkPackagePermission(ctx, userId, packageId); err != nil {
+		return nil, err
+	}
+
+	slaRules, err := s.slaRuleRepository.GetAllByPackageID(ctx, nil, packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []dto.SLARuleResponse{}
+	for _, slaRule := range slaRules {
+		res = append(res, slaRuleResponse(slaRule))
+	}
+
+	return res, nil
+}
+
+func (s *slaService) CreateRule(ctx context.Context, req dto.SLARuleRequest) (dto.SLARuleResponse, error) {
+	pkg, err := s.packageRepository.GetByID(ctx, nil, req.PackageID)
+	if err != nil {
+		return dto.SLARuleResponse{}, err
+	}
+
+	slaRule := entity.SLARule{
+		PackageID: pkg.ID,
+	}
+	if err := s.fillRule(ctx, &slaRule, req); err != nil {
+		return dto.SLARuleResponse{}, err
+	}
+
+	slaRule, err = s.slaRuleRepository.Create(ctx, nil, slaRule)
+	if err != nil {
+		return dto.SLARuleResponse{}, err
+	}
+
+	return slaRuleResponse(slaRule), nil
+}
+
+func (s *slaService) UpdateRule(ctx context.Context, req dto.SLARuleRequest) (dto.SLARuleResponse, error) {
+	slaRule, err := s.slaRuleRepository.GetByID(ctx, nil, req.ID)
+	if err != nil || slaRule.PackageID.String() != req.PackageID {
+		return dto.SLARuleResponse{}, myerror.New("sla rule not found", http.StatusNotFound)
+	}
+
+	if err := s.fillRule(ctx, &slaRule, req); err != nil {
+		return dto.SLARuleResponse{}, err
+	}
+
+	slaRule, err = s.slaRuleRepository.Update(ctx, nil, slaRule)
+	if err != nil {
+		return dto.SLARuleResponse{}, err
+	}
+
+	return slaRuleResponse(slaRule), nil
+}
+
+func (s *slaService) DeleteRule(ctx context.Context, userId, packageId, slaRuleId string) error {
+	slaRule, err := s.slaRuleRepository.GetByID(ctx, nil, slaRuleId)
+	if err != nil || slaRule.PackageID.String() != packageId {
+		return myerror.New("sla rule not found", http.StatusNotFound)
+	}
+
+	// mark who deleted, due dates already set keep their value
+	slaRule.DeletedBy = uuid.MustParse(userId)
+	return s.slaRuleRepository.Delete(ctx, nil, slaRule)
+}
+
+func (s *slaService) GetAllHolidays(ctx context.Context, userId, packageId string, year int) ([]dto.HolidayResponse, error) {
+	if err := s.checkPackagePermission(ctx, userId, packageId); err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.holidayRepository.GetAllByPackageID(ctx, nil, packageId, year)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []dto.HolidayResponse{}
+	for _, holiday := range holidays {
+		res = append(res, holidayResponse(holiday))
+	}
+
+	return res, nil
+}
+
+func (s *slaService) CreateHoliday(ctx context.Context, req dto.HolidayRequest) (dto.HolidayResponse, error) {
+	pkg, err := s.packageRepository.GetByID(ctx, nil, req.PackageID)
+	if err != nil {
+		return dto.HolidayResponse{}, err
+	}
+
+	holiday := entity.Holiday{
+		PackageID: pkg.ID,
+	}
+	if err := s.fillHoliday(ctx, &holiday, req); err != nil {
+		return dto.HolidayResponse{}, err
+	}
+
+	holiday, err = s.holidayRepository.Create(ctx, nil, holiday)
+	if err != nil {
+		return dto.HolidayResponse{}, err
+	}
+
+	return holidayResponse(holiday), nil
+}
+
+func (s *slaService) UpdateHoliday(ctx context.Context, req dto.HolidayRequest) (dto.HolidayResponse, error) {
+	holiday, err := s.holidayRepository.GetByID(ctx, nil, req.ID)
+	if err != nil || holiday.PackageID.String() != req.PackageID {
+		return dto.HolidayResponse{}, myerror.New("holiday not found", http.StatusNotFound)
+	}
+
+	if err := s.fillHoliday(ctx, &holiday, req); err != nil {
+		return dto.HolidayResponse{}, err
+	}
+
+	holiday, err = s.holidayRepository.Update(ctx, nil, holiday)
+	if err != nil {
+		return dto.HolidayResponse{}, err
+	}
+
+	return holidayResponse(holiday), nil
+}
+
+func (s *slaService) DeleteHoliday(ctx context.Context, userId, packageId, holidayId string) error {
+	holiday, err := s.holidayRepository.GetByID(ctx, nil, holidayId)
+	if err != nil || holiday.PackageID.String() != packageId {
+		return myerror.New("holiday not found", http.StatusNotFound)
+	}
+
+	// mark who deleted
+	holiday.DeletedBy = uuid.MustParse(userId)
+	return s.holidayRepository.Delete(ctx, nil, holiday)
+}
+
+// DueDate is the day the review of a document issued at the time ends. The
+// working days of the matching rule are counted in the time zone of the
+// package from the day after the issue, weekends and holidays of the package
+// are skipped.
+func (s *slaService) DueDate(ctx context.Context, pkg entity.Package, purpose entity.TransmittalPurpose, resubmission bool, issuedAt time.Time) (time.Time, error) {
+	slaRules, err := s.slaRuleRepository.GetAllByPackageID(ctx, nil, pkg.ID.String())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	issuedAt = issuedAt.In(pkg.Location())
+	holidays, err := s.holidayRepository.GetAllFrom(ctx, nil, pkg.ID.String(), issuedAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	days := map[string]bool{}
+	for _, holiday := range holidays {
+		days[holiday.Date.Format(time.DateOnly)] = true
+	}
+
+	return addWorkingDays(issuedAt, slaWorkingDays(pkg, slaRules, purpose, resubmission), days), nil
+}
+
+func (s *slaService) fillRule(ctx context.Context, slaRule *entity.SLARule, req dto.SLARuleRequest) error {
+	slaRule.Purpose = (*entity.TransmittalPurpose)(req.Purpose)
+	slaRule.Resubmission = req.Resubmission
+	slaRule.WorkingDays = req.WorkingDays
+	slaRule.UpdatedBy = uuid.MustParse(req.UserId)
+
+	exists, err := s.slaRuleRepository.ExistsByPurpose(ctx, nil, *slaRule)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		purpose := "every purpose"
+		if slaRule.Purpose != nil {
+			purpose = string(*slaRule.Purpose)
+		}
+		return myerror.New(fmt.Sprintf("this package already has a rule for %s", purpose), http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (s *slaService) fillHoliday(ctx context.Context, holiday *entity.Holiday, req dto.HolidayRequest) error {
+	date, err := time.Parse(time.DateOnly, req.Date)
+	if err != nil {
+		return myerror.New("date must be formatted as YYYY-MM-DD", http.StatusBadRequest)
+	}
+
+	holiday.Date = date
+	holiday.Name = strings.TrimSpace(req.Name)
+	holiday.UpdatedBy = uuid.MustParse(req.UserId)
+
+	if holiday.Name == "" {
+		return myerror.New("name is required", http.StatusBadRequest)
+	}
+
+	exists, err := s.holidayRepository.ExistsByDate(ctx, nil, *holiday)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return myerror.New(fmt.Sprintf("%s is already a holiday of this package", req.Date), http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (s *slaService) checkPackagePermission(ctx context.Context, userId, packageId string) error {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return err
+	}
+
+	if user.PackageID != nil && user.PackageID.String() != packageId {
+		return myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	if _, err := s.packageRepository.GetByID(ctx, nil, packageId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return myerror.New("package not found", http.StatusNotFound)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// slaWorkingDays picks the rule of the purpose before the rule for every
+// purpose. A resubmission without a rule of its own is reviewed like a first
+// issue, and the review period of the package applies when nothing matches.
+func slaWorkingDays(pkg entity.Package, slaRules []entity.SLARule, purpose entity.TransmittalPurpose, resubmission bool) int {
+	kinds := []bool{resubmission}
+	if resubmission {
+		kinds = append(kinds, false)
+	}
+
+	for _, kind := range kinds {
+		for _, forPurpose := range []bool{true, false} {
+			for _, slaRule := range slaRules {
+				if slaRule.Resubmission != kind || (slaRule.Purpose != nil) != forPurpose {
+					continue
+				}
+
+				if slaRule.Purpose == nil || *slaRule.Purpose == purpose {
+					return slaRule.WorkingDays
+				}
+			}
+		}
+	}
+
+	return pkg.ReviewPeriodDays
+}
+
+// addWorkingDays is the start of the day the working days after the issue
+// run out, the issue day itself is not counted
+func addWorkingDays(issuedAt time.Time, days int, holidays map[string]bool) time.Time {
+	day := time.Date(issuedAt.Year(), issuedAt.Month(), issuedAt.Day(), 0, 0, 0, 0, issuedAt.Location())
+	for days > 0 {
+		day = day.AddDate(0, 0, 1)
+		if isWorkingDay(day, holidays) {
+			days--
+		}
+	}
+
+	return day
+}
+
+func isWorkingDay(day time.Time, holidays map[string]bool) bool {
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+
+	return !holidays[day.Format(time.DateOnly)]
+}
+
+func slaRuleResponse(slaRule entity.SLARule) dto.SLARuleResponse {
+	return dto.SLARuleResponse{
+		ID:           slaRule.ID.String(),
+		Purpose:      (*string)(slaRule.Purpose),
+		Resubmission: slaRule.Resubmission,
+		WorkingDays:  slaRule.WorkingDays,
+		PackageID:    slaRule.PackageID.String(),
+	}
+}
+
+func holidayResponse(holiday entity.Holiday) dto.HolidayResponse {
+	return dto.HolidayResponse{
+		ID:        holiday.ID.String(),
+		Date:      holiday.Date.Format(time.DateOnly),
+		Name:      holiday.Name,
+		PackageID: holiday.PackageID.String(),
+	}
+}
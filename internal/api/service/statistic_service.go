@@ -2,19 +2,32 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
 
 	"github.com/CRS-Project/crs-backend/internal/api/repository"
 	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
 	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
 	"gorm.io/gorm"
 )
 
 type (
 	StatisticService interface {
-		GetAOCAndCommentChart(ctx context.Context, packageId string) ([]dto.StatisticAOCAndCommentChart, error)
-		GetCommentCard(ctx context.Context, packageId string) (dto.StatisticAOCAndCommentCard, error)
-		GetCommentUserChart(ctx context.Context, packageId string) ([]dto.StatisticCommentUsersChart, error)
-		GetCommentUserData(ctx context.Context, packageId string, metaReq meta.Meta) ([]dto.StatisticCommentUsersData, meta.Meta, error)
+		GetAOCAndCommentChart(ctx context.Context, userId, packageId string, filter dto.StatisticSeriesFilter) ([]dto.StatisticAOCAndCommentChart, error)
+		GetCommentChart(ctx context.Context, userId, packageId string, filter dto.StatisticCommentChartFilter) ([]dto.StatisticCommentChart, error)
+		GetCommentCard(ctx context.Context, userId, packageId string) (dto.StatisticAOCAndCommentCard, error)
+		GetCommentUserChart(ctx context.Context, userId, packageId string) ([]dto.StatisticCommentUsersChart, error)
+		GetCommentUserData(ctx context.Context, userId, packageId string, metaReq meta.Meta) ([]dto.StatisticCommentUsersData, meta.Meta, error)
+		GetCommentCategoryChart(ctx context.Context, userId, packageId string) ([]dto.StatisticCommentClassChart, error)
+		GetCommentSeverityChart(ctx context.Context, userId, packageId string) ([]dto.StatisticCommentClassChart, error)
+		GetReviewOutcomeChart(ctx context.Context, userId, packageId string) ([]dto.StatisticReviewOutcomeChart, error)
+		GetSLACompliance(ctx context.Context, userId, packageId string) (dto.StatisticSLACompliance, error)
+		GetMe(ctx context.Context, userId, packageId string) (dto.StatisticMe, error)
 	}
 
 	statisticService struct {
@@ -23,6 +36,7 @@ type (
 		documentRepository               repository.DocumentRepository
 		disciplineListDocumentRepository repository.DisciplineListDocumentRepository
 		userRepository                   repository.UserRepository
+		packageRepository                repository.PackageRepository
 		db                               *gorm.DB
 	}
 )
@@ -32,6 +46,7 @@ func NewStatistic(statisticRepository repository.StatisticRepository,
 	documentRepository repository.DocumentRepository,
 	disciplineListDocumentRepository repository.DisciplineListDocumentRepository,
 	userRepository repository.UserRepository,
+	packageRepository repository.PackageRepository,
 	db *gorm.DB) StatisticService {
 	return &statisticService{
 		statisticRepository:              statisticRepository,
@@ -39,22 +54,300 @@ func NewStatistic(statisticRepository repository.StatisticRepository,
 		documentRepository:               documentRepository,
 		disciplineListDocumentRepository: disciplineListDocumentRepository,
 		userRepository:                   userRepository,
+		packageRepository:                packageRepository,
 		db:                               db,
 	}
 }
 
-func (s *statisticService) GetAOCAndCommentChart(ctx context.Context, packageId string) ([]dto.StatisticAOCAndCommentChart, error) {
-	return s.statisticRepository.GetAOCAndCommentChart(ctx, nil, packageId)
+func (s *statisticService) GetAOCAndCommentChart(ctx context.Context, userId, packageId string, filter dto.StatisticSeriesFilter) ([]dto.StatisticAOCAndCommentChart, error) {
+	user, err := s.checkPackagePermission(ctx, userId, packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSeriesFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	return s.statisticRepository.GetAOCAndCommentChart(ctx, nil, packageId, statisticCommentScope(user), filter)
+}
+
+func (s *statisticService) GetCommentChart(ctx context.Context, userId, packageId string, filter dto.StatisticCommentChartFilter) ([]dto.StatisticCommentChart, error) {
+	user, err := s.checkPackagePermission(ctx, userId, packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSeriesFilter(&filter.StatisticSeriesFilter); err != nil {
+		return nil, err
+	}
+
+	return s.statisticRepository.GetCommentChart(ctx, nil, packageId, statisticCommentScope(user), filter)
+}
+
+func (s *statisticService) GetCommentCard(ctx context.Context, userId, packageId string) (dto.StatisticAOCAndCommentCard, error) {
+	user, err := s.checkPackagePermission(ctx, userId, packageId)
+	if err != nil {
+		return dto.StatisticAOCAndCommentCard{}, err
+	}
+
+	return s.statisticRepository.GetCommentCard(ctx, nil, packageId, statisticCommentScope(user))
+}
+
+func (s *statisticService) GetCommentUserChart(ctx context.Context, userId, packageId string) ([]dto.StatisticCommentUsersChart, error) {
+	user, err := s.checkPackagePermission(ctx, userId, packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.statisticRepository.GetCommentUserChart(ctx, nil, packageId, statisticCommentScope(user))
+}
+
+func (s *statisticService) GetCommentUserData(ctx context.Context, userId, packageId string, metaReq meta.Meta) ([]dto.StatisticCommentUsersData, meta.Meta, error) {
+	user, err := s.checkPackagePermission(ctx, userId, packageId)
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	return s.statisticRepository.GetCommentUserData(ctx, nil, packageId, statisticCommentScope(user), metaReq)
+}
+
+func (s *statisticService) GetCommentCategoryChart(ctx context.Context, userId, packageId string) ([]dto.StatisticCommentClassChart, error) {
+	user, err := s.checkPackagePermission(ctx, userId, packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.statisticRepository.GetCommentClassChart(ctx, nil, packageId, statisticCommentScope(user), entity.CommentClassCategory)
 }
 
-func (s *statisticService) GetCommentCard(ctx context.Context, packageId string) (dto.StatisticAOCAndCommentCard, error) {
-	return s.statisticRepository.GetCommentCard(ctx, nil, packageId)
+func (s *statisticService) GetCommentSeverityChart(ctx context.Context, userId, packageId string) ([]dto.StatisticCommentClassChart, error) {
+	user, err := s.checkPackagePermission(ctx, userId, packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.statisticRepository.GetCommentClassChart(ctx, nil, packageId, statisticCommentScope(user), entity.CommentClassSeverity)
 }
 
-func (s *statisticService) GetCommentUserChart(ctx context.Context, packageId string) ([]dto.StatisticCommentUsersChart, error) {
-	return s.statisticRepository.GetCommentUserChart(ctx, nil, packageId)
+// GetReviewOutcomeChart lists every outcome code in order, the documents
+// without an outcome come last as pending
+func (s *statisticService) GetReviewOutcomeChart(ctx context.Context, userId, packageId string) ([]dto.StatisticReviewOutcomeChart, error) {
+	if _, err := s.checkPackagePermission(ctx, userId, packageId); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.statisticRepository.GetReviewOutcomeChart(ctx, nil, packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]int{}
+	for _, stat := range stats {
+		totals[stat.Code] = stat.TotalDocuments
+	}
+
+	res := []dto.StatisticReviewOutcomeChart{}
+	for _, code := range entity.ReviewOutcomeCodes {
+		res = append(res, dto.StatisticReviewOutcomeChart{
+			Code:           string(code),
+			Number:         code.Number(),
+			Name:           code.Label(),
+			TotalDocuments: totals[string(code)],
+		})
+	}
+
+	res = append(res, dto.StatisticReviewOutcomeChart{
+		Name:           "Pending",
+		TotalDocuments: totals[""],
+	})
+
+	return res, nil
 }
 
-func (s *statisticService) GetCommentUserData(ctx context.Context, packageId string, metaReq meta.Meta) ([]dto.StatisticCommentUsersData, meta.Meta, error) {
-	return s.statisticRepository.GetCommentUserData(ctx, nil, packageId, metaReq)
+// GetSLACompliance tells how the discipline groups and the reviewers of the
+// package keep to the due dates. A round is on time when it got a response
+// by the end of its due day in the time zone of the package.
+func (s *statisticService) GetSLACompliance(ctx context.Context, userId, packageId string) (dto.StatisticSLACompliance, error) {
+	if _, err := s.checkPackagePermission(ctx, userId, packageId); err != nil {
+		return dto.StatisticSLACompliance{}, err
+	}
+
+	pkg, err := s.packageRepository.GetByID(ctx, nil, packageId)
+	if err != nil {
+		return dto.StatisticSLACompliance{}, err
+	}
+
+	disciplineGroups, err := s.statisticRepository.GetSLADisciplineGroupResponses(ctx, nil, packageId)
+	if err != nil {
+		return dto.StatisticSLACompliance{}, err
+	}
+
+	reviewers, err := s.statisticRepository.GetSLAReviewerResponses(ctx, nil, packageId)
+	if err != nil {
+		return dto.StatisticSLACompliance{}, err
+	}
+
+	now := time.Now()
+	location := pkg.Location()
+	return dto.StatisticSLACompliance{
+		DisciplineGroups: slaCompliance(disciplineGroups, location, now),
+		Reviewers:        slaCompliance(reviewers, location, now),
+	}, nil
+}
+
+func slaCompliance(responses []dto.StatisticSLAResponse, location *time.Location, now time.Time) []dto.StatisticSLAComplianceData {
+	res := []dto.StatisticSLAComplianceData{}
+	index := map[string]int{}
+	for _, response := range responses {
+		i, ok := index[response.ID]
+		if !ok {
+			i = len(res)
+			index[response.ID] = i
+			res = append(res, dto.StatisticSLAComplianceData{
+				ID:   response.ID,
+				Name: response.Name,
+			})
+		}
+
+		// due dates are stored as the start of the due day
+		dueDate := response.DueDate.In(location)
+		endOfDueDay := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, location).AddDate(0, 0, 1)
+
+		res[i].Total++
+		switch {
+		case response.RespondedAt != nil && response.RespondedAt.Before(endOfDueDay):
+			res[i].OnTime++
+		case response.RespondedAt != nil:
+			res[i].Late++
+		case !now.Before(endOfDueDay):
+			res[i].Overdue++
+		default:
+			res[i].Pending++
+		}
+	}
+
+	for i := range res {
+		if due := res[i].OnTime + res[i].Late + res[i].Overdue; due > 0 {
+			res[i].ComplianceRate = math.Round(float64(res[i].OnTime)/float64(due)*10000) / 100
+		}
+	}
+
+	return res
+}
+
+// GetMe is the statistic of the user in their package, super admins pick
+// the package
+func (s *statisticService) GetMe(ctx context.Context, userId, packageId string) (dto.StatisticMe, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return dto.StatisticMe{}, err
+	}
+
+	if user.PackageID != nil {
+		if packageId != "" && packageId != user.PackageID.String() {
+			return dto.StatisticMe{}, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+		}
+		packageId = user.PackageID.String()
+	}
+
+	if packageId == "" {
+		return dto.StatisticMe{}, myerror.New("package_id is required", http.StatusBadRequest)
+	}
+
+	pkg, err := s.packageRepository.GetByID(ctx, nil, packageId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.StatisticMe{}, myerror.New("package not found", http.StatusNotFound)
+		}
+		return dto.StatisticMe{}, err
+	}
+
+	card, err := s.statisticRepository.GetUserCard(ctx, nil, packageId, userId)
+	if err != nil {
+		return dto.StatisticMe{}, err
+	}
+
+	responses, err := s.statisticRepository.GetSLAReviewerResponses(ctx, nil, packageId)
+	if err != nil {
+		return dto.StatisticMe{}, err
+	}
+
+	mine := []dto.StatisticSLAResponse{}
+	for _, response := range responses {
+		if response.ID == userId {
+			mine = append(mine, response)
+		}
+	}
+
+	res := dto.StatisticMe{
+		StatisticUserCard: card,
+		PackageID:         pkg.ID.String(),
+		Package:           pkg.Name,
+		SLA: dto.StatisticSLAComplianceData{
+			ID:   user.ID.String(),
+			Name: user.Name,
+		},
+	}
+	if compliance := slaCompliance(mine, pkg.Location(), time.Now()); len(compliance) > 0 {
+		res.SLA = compliance[0]
+	}
+
+	return res, nil
+}
+
+// checkPackagePermission lets super admins read every package and the other
+// users only their own
+func (s *statisticService) checkPackagePermission(ctx context.Context, userId, packageId string) (entity.User, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	if user.PackageID != nil && user.PackageID.String() != packageId {
+		return entity.User{}, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	return user, nil
+}
+
+// statisticCommentScope counts the consolidated set of comments the user
+// reads, drafts and discarded comments are never counted
+func statisticCommentScope(user entity.User) repository.CommentScope {
+	scope := commentScope(user)
+	scope.Stages = []entity.CommentStage{entity.CommentStageOfficial}
+	return scope
+}
+
+// maxSeriesPeriods keeps a day by day series over years from being built
+const maxSeriesPeriods = 1000
+
+// checkSeriesFilter rejects a from too far back and sets the earliest start
+// of a series without from, which otherwise starts at its first data
+func checkSeriesFilter(filter *dto.StatisticSeriesFilter) error {
+	to := time.Now()
+	if filter.To != nil {
+		to = *filter.To
+	}
+
+	if filter.From != nil && to.Before(*filter.From) {
+		return myerror.New("from must not be after to", http.StatusBadRequest)
+	}
+
+	earliest := to.AddDate(0, 0, -2*maxSeriesPeriods)
+	switch filter.Granularity {
+	case dto.StatisticGranularityDay:
+		earliest = to.AddDate(0, 0, -maxSeriesPeriods)
+	case dto.StatisticGranularityWeek:
+		earliest = to.AddDate(0, 0, -7*maxSeriesPeriods)
+	case dto.StatisticGranularityMonth:
+		earliest = to.AddDate(0, -maxSeriesPeriods, 0)
+	}
+
+	if filter.From != nil && filter.From.Before(earliest) {
+		return myerror.New(fmt.Sprintf("the date range has more than %d periods, pick a coarser granularity", maxSeriesPeriods), http.StatusBadRequest)
+	}
+
+	filter.Earliest = &earliest
+	return nil
 }
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	"github.com/google/uuid"
+)
+
+// statisticCalls runs every statistic behind /statistic/*/:package_id
+var statisticCalls = map[string]func(s StatisticService, userId, packageId string) error{
+	"aoc-comment-chart": func(s StatisticService, userId, packageId string) error {
+		_, err := s.GetAOCAndCommentChart(context.Background(), userId, packageId, dto.StatisticSeriesFilter{})
+		return err
+	},
+	"comment-chart": func(s StatisticService, userId, packageId string) error {
+		_, err := s.GetCommentChart(context.Background(), userId, packageId, dto.StatisticCommentChartFilter{})
+		return err
+	},
+	"aoc-comment-card": func(s StatisticService, userId, packageId string) error {
+		_, err := s.GetCommentCard(context.Background(), userId, packageId)
+		return err
+	},
+	"comment-user-chart": func(s StatisticService, userId, packageId string) error {
+		_, err := s.GetCommentUserChart(context.Background(), userId, packageId)
+		return err
+	},
+	"comment-user-data": func(s StatisticService, userId, packageId string) error {
+		_, _, err := s.GetCommentUserData(context.Background(), userId, packageId, meta.Meta{})
+		return err
+	},
+	"comment-category-chart": func(s StatisticService, userId, packageId string) error {
+		_, err := s.GetCommentCategoryChart(context.Background(), userId, packageId)
+		return err
+	},
+	"comment-severity-chart": func(s StatisticService, userId, packageId string) error {
+		_, err := s.GetCommentSeverityChart(context.Background(), userId, packageId)
+		return err
+	},
+	"review-outcome-chart": func(s StatisticService, userId, packageId string) error {
+		_, err := s.GetReviewOutcomeChart(context.Background(), userId, packageId)
+		return err
+	},
+	"sla-compliance": func(s StatisticService, userId, packageId string) error {
+		_, err := s.GetSLACompliance(context.Background(), userId, packageId)
+		return err
+	},
+}
+
+func assertStatusCode(t *testing.T, err error, want int) {
+	t.Helper()
+
+	var myErr myerror.Error
+	if !errors.As(err, &myErr) || myErr.StatusCode != want {
+		t.Fatalf("error = %v, want status %d", err, want)
+	}
+}
+
+func TestStatisticServicePackagePermission(t *testing.T) {
+	for name, call := range statisticCalls {
+		t.Run(name, func(t *testing.T) {
+			f := newFakeFixture(t)
+
+			statistics := &fakeStatisticRepository{}
+			err := call(f.statisticService(statistics), f.reviewer.ID.String(), f.otherPkg.ID.String())
+			assertStatusCode(t, err, http.StatusUnauthorized)
+			if len(statistics.reads) != 0 {
+				t.Errorf("member read another package: %v", statistics.reads)
+			}
+
+			statistics = &fakeStatisticRepository{}
+			if err := call(f.statisticService(statistics), f.reviewer.ID.String(), f.pkg.ID.String()); err != nil {
+				t.Fatalf("member on own package: error = %v", err)
+			}
+
+			statistics = &fakeStatisticRepository{}
+			if err := call(f.statisticService(statistics), f.superAdmin.ID.String(), f.otherPkg.ID.String()); err != nil {
+				t.Fatalf("super admin: error = %v", err)
+			}
+			if !slices.Contains(statistics.reads, f.otherPkg.ID.String()) {
+				t.Errorf("super admin did not read package B: %v", statistics.reads)
+			}
+		})
+	}
+}
+
+func TestStatisticServiceCommentScope(t *testing.T) {
+	official := []entity.CommentStage{entity.CommentStageOfficial}
+
+	for _, tt := range []struct {
+		name string
+		user func(f *fakeFixture) entity.User
+		want repository.CommentScope
+	}{
+		{"reviewer", func(f *fakeFixture) entity.User { return f.reviewer }, repository.CommentScope{Stages: official}},
+		{"contractor", func(f *fakeFixture) entity.User { return f.contractor }, repository.CommentScope{Stages: official, PublicOnly: true}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeFixture(t)
+			statistics := &fakeStatisticRepository{}
+
+			for name, call := range statisticCalls {
+				if err := call(f.statisticService(statistics), tt.user(f).ID.String(), f.pkg.ID.String()); err != nil {
+					t.Fatalf("%s: error = %v", name, err)
+				}
+			}
+
+			if len(statistics.scopes) == 0 {
+				t.Fatal("no comment was counted")
+			}
+			for _, scope := range statistics.scopes {
+				if !slices.Equal(scope.Stages, tt.want.Stages) || scope.PublicOnly != tt.want.PublicOnly {
+					t.Errorf("scope = %+v, want %+v", scope, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStatisticServiceGetMe(t *testing.T) {
+	f := newFakeFixture(t)
+
+	mine, other := f.pkg.ID.String(), f.otherPkg.ID.String()
+	respondedAt := time.Now().AddDate(0, 0, -2)
+	statistics := &fakeStatisticRepository{
+		cards: map[string]dto.StatisticUserCard{
+			mine:  {TotalComments: 3},
+			other: {TotalComments: 7},
+		},
+		// one round answered in time by the reviewer, the other one is
+		// someone else's
+		responses: map[string][]dto.StatisticSLAResponse{
+			mine: {
+				{ID: f.reviewer.ID.String(), DueDate: time.Now().AddDate(0, 0, 1), RespondedAt: &respondedAt},
+				{ID: uuid.NewString(), DueDate: time.Now().AddDate(0, 0, -7)},
+			},
+		},
+	}
+
+	res, err := f.statisticService(statistics).GetMe(context.Background(), f.reviewer.ID.String(), "")
+	if err != nil {
+		t.Fatalf("GetMe() error = %v", err)
+	}
+	if res.PackageID != mine || res.TotalComments != 3 {
+		t.Errorf("GetMe() = package %s with %d comments, want %s with 3", res.PackageID, res.TotalComments, mine)
+	}
+	if res.SLA.Total != 1 || res.SLA.OnTime != 1 {
+		t.Errorf("GetMe() SLA = %+v, want the one round of the reviewer on time", res.SLA)
+	}
+
+	_, err = f.statisticService(statistics).GetMe(context.Background(), f.reviewer.ID.String(), other)
+	assertStatusCode(t, err, http.StatusUnauthorized)
+
+	_, err = f.statisticService(statistics).GetMe(context.Background(), f.superAdmin.ID.String(), "")
+	assertStatusCode(t, err, http.StatusBadRequest)
+
+	if slices.Contains(statistics.reads, other) {
+		t.Errorf("package B was read: %v", statistics.reads)
+	}
+}
+
+func TestCheckSeriesFilter(t *testing.T) {
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(-5, 0, 0)
+
+	filter := dto.StatisticSeriesFilter{From: &from, To: &to, Granularity: dto.StatisticGranularityDay}
+	assertStatusCode(t, checkSeriesFilter(&filter), http.StatusBadRequest)
+
+	filter = dto.StatisticSeriesFilter{From: &from, To: &to, Granularity: dto.StatisticGranularityWeek}
+	if err := checkSeriesFilter(&filter); err != nil {
+		t.Fatalf("weekly over 5 years: error = %v", err)
+	}
+
+	// without from the series is cut at the earliest start it may have
+	filter = dto.StatisticSeriesFilter{To: &to, Granularity: dto.StatisticGranularityDay}
+	if err := checkSeriesFilter(&filter); err != nil {
+		t.Fatalf("without from: error = %v", err)
+	}
+	if want := to.AddDate(0, 0, -maxSeriesPeriods); filter.Earliest == nil || !filter.Earliest.Equal(want) {
+		t.Errorf("Earliest = %v, want %v", filter.Earliest, want)
+	}
+}
@@ -0,0 +1,502 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/meta"
+	mypdf "github.com/CRS-Project/crs-backend/internal/pkg/pdf"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// the company side of a transmittal on the cover sheet
+const transmittalCompanyParty = "Company Document Control"
+
+type (
+	TransmittalService interface {
+		GetAll(ctx context.Context, userId, packageId string, metaReq meta.Meta) ([]dto.TransmittalResponse, meta.Meta, error)
+		GetByID(ctx context.Context, userId, packageId, transmittalId string) (dto.TransmittalResponse, error)
+		Create(ctx context.Context, req dto.TransmittalRequest) (dto.TransmittalResponse, error)
+		Update(ctx context.Context, req dto.TransmittalRequest) (dto.TransmittalResponse, error)
+		Delete(ctx context.Context, userId, packageId, transmittalId string) error
+		GenerateCoverSheet(ctx context.Context, userId, packageId, transmittalId string) (*bytes.Buffer, string, error)
+	}
+
+	transmittalService struct {
+		transmittalRepository repository.TransmittalRepository
+		documentRepository    repository.DocumentRepository
+		packageRepository     repository.PackageRepository
+		userRepository        repository.UserRepository
+		slaService            SLAService
+		db                    *gorm.DB
+	}
+)
+
+func NewTransmittal(transmittalRepository repository.TransmittalRepository,
+	documentRepository repository.DocumentRepository,
+	packageRepository repository.PackageRepository,
+	userRepository repository.UserRepository,
+	slaService SLAService,
+	db *gorm.DB) TransmittalService {
+	return &transmittalService{
+		transmittalRepository: transmittalRepository,
+		documentRepository:    documentRepository,
+		packageRepository:     packageRepository,
+		userRepository:        userRepository,
+		slaService:            slaService,
+		db:                    db,
+	}
+}
+
+func (s *transmittalService) GetAll(ctx context.Context, userId, packageId string, metaReq meta.Meta) ([]dto.TransmittalResponse, meta.Meta, error) {
+	if _, _, err := s.checkPackagePermission(ctx, userId, packageId); err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	transmittals, metaRes, err := s.transmittalRepository.GetAllByPackageID(ctx, nil, packageId, metaReq, "ReplyTo", "Documents")
+	if err != nil {
+		return nil, meta.Meta{}, err
+	}
+
+	res := []dto.TransmittalResponse{}
+	for _, transmittal := range transmittals {
+		res = append(res, transmittalResponse(transmittal, false))
+	}
+
+	return res, metaRes, nil
+}
+
+func (s *transmittalService) GetByID(ctx context.Context, userId, packageId, transmittalId string) (dto.TransmittalResponse, error) {
+	if _, _, err := s.checkPackagePermission(ctx, userId, packageId); err != nil {
+		return dto.TransmittalResponse{}, err
+	}
+
+	transmittal, err := s.get(ctx, packageId, transmittalId, "ReplyTo", "Documents.Document")
+	if err != nil {
+		return dto.TransmittalResponse{}, err
+	}
+
+	return transmittalResponse(transmittal, true), nil
+}
+
+func (s *transmittalService) Create(ctx context.Context, req dto.TransmittalRequest) (dto.TransmittalResponse, error) {
+	pkg, user, err := s.checkPackagePermission(ctx, req.UserId, req.PackageID)
+	if err != nil {
+		return dto.TransmittalResponse{}, err
+	}
+
+	transmittal := entity.Transmittal{
+		Direction: entity.TransmittalDirection(req.Direction),
+		PackageID: pkg.ID,
+	}
+
+	documents, err := s.fill(ctx, pkg, user, &transmittal, req)
+	if err != nil {
+		return dto.TransmittalResponse{}, err
+	}
+
+	// the number is taken in the same transaction, concurrent transmittals
+	// wait for it to commit before they take theirs
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		transmittal.Sequence, err = s.transmittalRepository.NextSequence(ctx, nil, pkg.ID, transmittal.Direction)
+		if err != nil {
+			return err
+		}
+		transmittal.Number = transmittal.Direction.Number(transmittal.Sequence)
+
+		transmittal, err = s.transmittalRepository.Create(ctx, nil, transmittal)
+		if err != nil {
+			return err
+		}
+
+		return s.updateDueDates(ctx, pkg, transmittal, documents)
+	})
+	if err != nil {
+		return dto.TransmittalResponse{}, err
+	}
+
+	return s.GetByID(ctx, req.UserId, req.PackageID, transmittal.ID.String())
+}
+
+func (s *transmittalService) Update(ctx context.Context, req dto.TransmittalRequest) (dto.TransmittalResponse, error) {
+	pkg, user, err := s.checkPackagePermission(ctx, req.UserId, req.PackageID)
+	if err != nil {
+		return dto.TransmittalResponse{}, err
+	}
+
+	transmittal, err := s.get(ctx, req.PackageID, req.ID)
+	if err != nil {
+		return dto.TransmittalResponse{}, err
+	}
+
+	// the number belongs to the direction
+	if string(transmittal.Direction) != req.Direction {
+		return dto.TransmittalResponse{}, myerror.New("direction of a transmittal can't be changed", http.StatusBadRequest)
+	}
+
+	documents, err := s.fill(ctx, pkg, user, &transmittal, req)
+	if err != nil {
+		return dto.TransmittalResponse{}, err
+	}
+
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if _, err := s.transmittalRepository.Update(ctx, nil, transmittal); err != nil {
+			return err
+		}
+
+		if err := s.transmittalRepository.ReplaceDocuments(ctx, nil, transmittal.ID, transmittal.Documents); err != nil {
+			return err
+		}
+
+		return s.updateDueDates(ctx, pkg, transmittal, documents)
+	})
+	if err != nil {
+		return dto.TransmittalResponse{}, err
+	}
+
+	return s.GetByID(ctx, req.UserId, req.PackageID, transmittal.ID.String())
+}
+
+func (s *transmittalService) Delete(ctx context.Context, userId, packageId, transmittalId string) error {
+	_, user, err := s.checkPackagePermission(ctx, userId, packageId)
+	if err != nil {
+		return err
+	}
+
+	transmittal, err := s.get(ctx, packageId, transmittalId)
+	if err != nil {
+		return err
+	}
+
+	if !canIssueTransmittal(user, transmittal.Direction) {
+		return myerror.New("you don't have permission for this transmittal", http.StatusUnauthorized)
+	}
+
+	// mark who deleted
+	transmittal.DeletedBy = user.ID
+	return repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		return s.transmittalRepository.Delete(ctx, nil, transmittal)
+	})
+}
+
+func (s *transmittalService) GenerateCoverSheet(ctx context.Context, userId, packageId, transmittalId string) (*bytes.Buffer, string, error) {
+	pkg, _, err := s.checkPackagePermission(ctx, userId, packageId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	transmittal, err := s.get(ctx, packageId, transmittalId, "ReplyTo", "Documents.Document")
+	if err != nil {
+		return nil, "", err
+	}
+
+	contractorName := "-"
+	contractor, err := s.userRepository.GetContractorByPackage(ctx, nil, packageId)
+	if err == nil {
+		contractorName = contractor.Name
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", err
+	}
+
+	from, to := contractorName, transmittalCompanyParty
+	if transmittal.Direction == entity.TransmittalOutgoing {
+		from, to = to, from
+	}
+
+	data := mypdf.TransmittalRequestData{
+		Number:          transmittal.Number,
+		Direction:       string(transmittal.Direction),
+		Purpose:         string(transmittal.Purpose),
+		ReferenceNumber: stringOr(transmittal.ReferenceNumber, "-"),
+		ReplyTo:         "-",
+		Package:         pkg.Name,
+		From:            from,
+		To:              to,
+		Subject:         transmittal.Subject,
+		Remarks:         stringOr(transmittal.Remarks, ""),
+		TransmittalDate: transmittal.TransmittalDate.Format("02 Jan 2006"),
+		ReceivedAt:      "-",
+	}
+	if transmittal.ReplyTo != nil {
+		data.ReplyTo = transmittal.ReplyTo.Number
+	}
+	if transmittal.ReceivedAt != nil {
+		data.ReceivedAt = transmittal.ReceivedAt.Format("02 Jan 2006")
+	}
+
+	for i, item := range transmittalResponse(transmittal, true).Documents {
+		row := mypdf.TransmittalRow{
+			No:                   fmt.Sprintf("%d", i+1),
+			DocumentNo:           item.CompanyDocumentNumber,
+			ContractorDocumentNo: item.ContractorDocumentNumber,
+			Title:                item.DocumentTitle,
+			Revision:             item.Revision,
+			DueDate:              "-",
+			ReviewOutcome:        "-",
+		}
+		if item.DueDate != nil && transmittal.Direction == entity.TransmittalIncoming {
+			row.DueDate = item.DueDate.Format("02 Jan 2006")
+		}
+		if item.ReviewOutcome != nil {
+			row.ReviewOutcome = item.ReviewOutcome.Label
+		}
+
+		data.Documents = append(data.Documents, row)
+	}
+
+	return mypdf.GenerateTransmittal(data)
+}
+
+// fill copies the request onto the transmittal and lists its documents, it
+// returns the listed documents of the package
+func (s *transmittalService) fill(ctx context.Context, pkg entity.Package, user entity.User, transmittal *entity.Transmittal, req dto.TransmittalRequest) ([]entity.Document, error) {
+	if !canIssueTransmittal(user, transmittal.Direction) {
+		if transmittal.Direction == entity.TransmittalIncoming {
+			return nil, myerror.New("only the contractor issues incoming transmittals", http.StatusUnauthorized)
+		}
+		return nil, myerror.New("only the company issues outgoing transmittals", http.StatusUnauthorized)
+	}
+
+	transmittal.Purpose = entity.TransmittalPurpose(req.Purpose)
+	transmittal.ReferenceNumber = req.ReferenceNumber
+	transmittal.Subject = req.Subject
+	transmittal.Remarks = req.Remarks
+	transmittal.TransmittalDate = req.TransmittalDate
+	transmittal.ReceivedAt = req.ReceivedAt
+	transmittal.UpdatedBy = user.ID
+
+	// the review period starts the day an incoming transmittal arrives
+	if transmittal.Direction == entity.TransmittalIncoming && transmittal.ReceivedAt == nil {
+		transmittal.ReceivedAt = &transmittal.TransmittalDate
+	}
+
+	if transmittal.ReceivedAt != nil && transmittal.ReceivedAt.Before(transmittal.TransmittalDate) {
+		return nil, myerror.New("received date can't be before the transmittal date", http.StatusBadRequest)
+	}
+
+	transmittal.ReplyToID = nil
+	if req.ReplyToID != nil && *req.ReplyToID != "" {
+		if transmittal.Direction != entity.TransmittalOutgoing {
+			return nil, myerror.New("only outgoing transmittals reply to another one", http.StatusBadRequest)
+		}
+
+		replyTo, err := s.get(ctx, pkg.ID.String(), *req.ReplyToID)
+		if err != nil {
+			return nil, err
+		}
+
+		if replyTo.Direction != entity.TransmittalIncoming {
+			return nil, myerror.New("an outgoing transmittal replies to an incoming one", http.StatusBadRequest)
+		}
+		transmittal.ReplyToID = &replyTo.ID
+	}
+
+	documentIds := []string{}
+	seen := map[string]bool{}
+	for _, item := range req.Documents {
+		if seen[item.DocumentID] {
+			return nil, myerror.New("a document is listed more than once", http.StatusBadRequest)
+		}
+		seen[item.DocumentID] = true
+		documentIds = append(documentIds, item.DocumentID)
+	}
+
+	documents, err := s.documentRepository.GetByIDs(ctx, nil, documentIds, "ReviewOutcomes")
+	if err != nil {
+		return nil, err
+	}
+
+	byId := map[string]entity.Document{}
+	for _, document := range documents {
+		if document.PackageID != pkg.ID {
+			return nil, myerror.New("documents must belong to this package", http.StatusBadRequest)
+		}
+		byId[document.ID.String()] = document
+	}
+
+	transmittal.Documents = nil
+	for _, item := range req.Documents {
+		document, ok := byId[item.DocumentID]
+		if !ok {
+			return nil, myerror.New("document not found", http.StatusNotFound)
+		}
+
+		transmittalDocument := entity.TransmittalDocument{
+			DocumentID: document.ID,
+			Revision:   document.Revision,
+		}
+		if item.Revision != nil && *item.Revision != "" {
+			transmittalDocument.Revision = *item.Revision
+		}
+
+		// a return transmittal carries the outcome of the revision
+		if transmittal.Direction == entity.TransmittalOutgoing {
+			for _, reviewOutcome := range document.ReviewOutcomes {
+				if reviewOutcome.Revision == transmittalDocument.Revision {
+					code := reviewOutcome.Code
+					transmittalDocument.ReviewOutcome = &code
+				}
+			}
+		}
+
+		transmittal.Documents = append(transmittal.Documents, transmittalDocument)
+	}
+
+	return documents, nil
+}
+
+// updateDueDates starts the review of the documents of an incoming
+// transmittal on its received date, the due date comes from the SLA of the
+// package. Revisions that are no longer current keep the due date of their
+// own round.
+func (s *transmittalService) updateDueDates(ctx context.Context, pkg entity.Package, transmittal entity.Transmittal, documents []entity.Document) error {
+	if transmittal.Direction != entity.TransmittalIncoming || transmittal.ReceivedAt == nil {
+		return nil
+	}
+
+	revisions := map[uuid.UUID]string{}
+	for _, item := range transmittal.Documents {
+		revisions[item.DocumentID] = item.Revision
+	}
+
+	for _, document := range documents {
+		if revisions[document.ID] != document.Revision {
+			continue
+		}
+
+		// a document that had an earlier revision reviewed comes back
+		resubmission := false
+		for _, reviewOutcome := range document.ReviewOutcomes {
+			if reviewOutcome.Revision != document.Revision {
+				resubmission = true
+			}
+		}
+
+		dueDate, err := s.slaService.DueDate(ctx, pkg, transmittal.Purpose, resubmission, *transmittal.ReceivedAt)
+		if err != nil {
+			return err
+		}
+
+		document.IssuedAt = transmittal.ReceivedAt
+		document.DueDate = &dueDate
+		document.ReviewOutcomes = nil
+		if _, err := s.documentRepository.Update(ctx, nil, document); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *transmittalService) get(ctx context.Context, packageId, transmittalId string, preloads ...string) (entity.Transmittal, error) {
+	transmittal, err := s.transmittalRepository.GetByID(ctx, nil, transmittalId, preloads...)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Transmittal{}, myerror.New("transmittal not found", http.StatusNotFound)
+		}
+		return entity.Transmittal{}, err
+	}
+
+	if transmittal.PackageID.String() != packageId {
+		return entity.Transmittal{}, myerror.New("transmittal not found", http.StatusNotFound)
+	}
+
+	return transmittal, nil
+}
+
+func (s *transmittalService) checkPackagePermission(ctx context.Context, userId, packageId string) (entity.Package, entity.User, error) {
+	user, err := s.userRepository.GetById(ctx, nil, userId)
+	if err != nil {
+		return entity.Package{}, entity.User{}, err
+	}
+
+	if user.PackageID != nil && user.PackageID.String() != packageId {
+		return entity.Package{}, entity.User{}, myerror.New("you don't have permission for this package", http.StatusUnauthorized)
+	}
+
+	pkg, err := s.packageRepository.GetByID(ctx, nil, packageId)
+	if err != nil {
+		return entity.Package{}, entity.User{}, err
+	}
+
+	return pkg, user, nil
+}
+
+// canIssueTransmittal tells whether the user writes transmittals of the
+// direction, contractors send and the company returns
+func canIssueTransmittal(user entity.User, direction entity.TransmittalDirection) bool {
+	if user.PackageID == nil {
+		return true
+	}
+
+	if direction == entity.TransmittalIncoming {
+		return user.Role == entity.RoleContractor
+	}
+
+	return user.Role != entity.RoleContractor
+}
+
+func transmittalResponse(transmittal entity.Transmittal, withDocuments bool) dto.TransmittalResponse {
+	res := dto.TransmittalResponse{
+		ID:              transmittal.ID.String(),
+		Number:          transmittal.Number,
+		Direction:       string(transmittal.Direction),
+		Purpose:         string(transmittal.Purpose),
+		ReferenceNumber: transmittal.ReferenceNumber,
+		Subject:         transmittal.Subject,
+		Remarks:         transmittal.Remarks,
+		TransmittalDate: transmittal.TransmittalDate,
+		ReceivedAt:      transmittal.ReceivedAt,
+		PackageID:       transmittal.PackageID.String(),
+		TotalDocuments:  len(transmittal.Documents),
+	}
+
+	if transmittal.ReplyTo != nil {
+		res.ReplyTo = &dto.TransmittalReference{
+			ID:     transmittal.ReplyTo.ID.String(),
+			Number: transmittal.ReplyTo.Number,
+		}
+	}
+
+	if !withDocuments {
+		return res
+	}
+
+	for _, item := range transmittal.Documents {
+		document := dto.TransmittalDocumentResponse{
+			DocumentID: item.DocumentID.String(),
+			Revision:   item.Revision,
+		}
+
+		if item.Document != nil {
+			document.CompanyDocumentNumber = item.Document.CompanyDocumentNumber
+			document.ContractorDocumentNumber = item.Document.ContractorDocumentNumber
+			document.DocumentTitle = item.Document.DocumentTitle
+			document.DueDate = item.Document.DueDate
+		}
+
+		if item.ReviewOutcome != nil {
+			code := reviewOutcomeCode(*item.ReviewOutcome)
+			document.ReviewOutcome = &code
+		}
+
+		res.Documents = append(res.Documents, document)
+	}
+
+	return res
+}
+
+func stringOr(value *string, fallback string) string {
+	if value == nil || *value == "" {
+		return fallback
+	}
+	return *value
+}
@@ -44,17 +44,48 @@ func NewRest() RestConfig {
 		disciplineListDocumentRepository             repository.DisciplineListDocumentRepository             = repository.NewDisciplineListDocument(db)
 		disciplineListDocumentConsolidatorRepository repository.DisciplineListDocumentConsolidatorRepository = repository.NewDisciplineListDocumentConsolidator(db)
 		statisticRepository                          repository.StatisticRepository                          = repository.NewStatistic(db)
+		jobRepository                                repository.JobRepository                                = repository.NewJob(db)
+		reportTemplateRepository                     repository.ReportTemplateRepository                     = repository.NewReportTemplate(db)
+		signOffRepository                            repository.SignOffRepository                            = repository.NewSignOff(db)
+		assignmentRuleRepository                     repository.AssignmentRuleRepository                     = repository.NewAssignmentRule(db)
+		documentRoutingRuleRepository                repository.DocumentRoutingRuleRepository                = repository.NewDocumentRoutingRule(db)
+		delegationRepository                         repository.DelegationRepository                         = repository.NewDelegation(db)
+		notificationRepository                       repository.NotificationRepository                       = repository.NewNotification(db)
+		queueRepository                              repository.QueueRepository                              = repository.NewQueue(db)
+		searchRepository                             repository.SearchRepository                             = repository.NewSearch(db)
+		savedViewRepository                          repository.SavedViewRepository                          = repository.NewSavedView(db)
+		commentClassRepository                       repository.CommentClassRepository                       = repository.NewCommentClass(db)
+		reviewOutcomeRepository                      repository.ReviewOutcomeRepository                      = repository.NewReviewOutcome(db)
+		transmittalRepository                        repository.TransmittalRepository                        = repository.NewTransmittal(db)
+		slaRuleRepository                            repository.SLARuleRepository                            = repository.NewSLARule(db)
+		holidayRepository                            repository.HolidayRepository                            = repository.NewHoliday(db)
+		reviewWindowRepository                       repository.ReviewWindowRepository                       = repository.NewReviewWindow(db)
 
 		//=========== (SERVICE) ===========//
+		slaService                    service.SLAService                    = service.NewSLA(slaRuleRepository, holidayRepository, packageRepository, userRepository, db)
+		notificationService           service.NotificationService           = service.NewNotification(notificationRepository, delegationRepository, disciplineGroupConsolidatorRepository, db)
 		authService                   service.AuthService                   = service.NewAuth(userRepository, mailerService, oauthService, db)
 		userService                   service.UserService                   = service.NewUser(userRepository, userDisciplineRepository, disciplineGroupConsolidatorRepository, disciplineListDocumentConsolidatorRepository, packageRepository, db)
 		userDisciplineService         service.UserDisciplineService         = service.NewUserDiscipline(userDisciplineRepository, db)
-		documentService               service.DocumentService               = service.NewDocument(documentRepository, disciplineListDocumentRepository, packageRepository, userRepository, db)
-		commentService                service.CommentService                = service.NewComment(commentRepository, documentRepository, disciplineListDocumentRepository, userRepository, db)
-		disciplineGroupService        service.DisciplineGroupService        = service.NewDisciplineGroup(disciplineGroupRepository, disciplineGroupConsolidatorRepository, disciplineListDocumentRepository, disciplineListDocumentConsolidatorRepository, packageRepository, commentRepository, userRepository, userDisciplineRepository, db)
-		disciplineListDocumentService service.DisciplineListDocumentService = service.NewDisciplineListDocument(disciplineListDocumentRepository, disciplineGroupRepository, disciplineListDocumentConsolidatorRepository, commentRepository, packageRepository, documentRepository, userRepository, userDisciplineRepository, db)
-		statisticService              service.StatisticService              = service.NewStatistic(statisticRepository, commentRepository, documentRepository, disciplineListDocumentRepository, userRepository, db)
-		packageService                service.PackageService                = service.NewPackage(packageRepository, userRepository, disciplineGroupService, db)
+		documentService               service.DocumentService               = service.NewDocument(documentRepository, disciplineListDocumentRepository, packageRepository, userRepository, documentRoutingRuleRepository, slaService, db)
+		commentService                service.CommentService                = service.NewComment(commentRepository, commentClassRepository, documentRepository, disciplineListDocumentRepository, userRepository, delegationRepository, notificationService, db)
+		disciplineGroupService        service.DisciplineGroupService        = service.NewDisciplineGroup(disciplineGroupRepository, disciplineGroupConsolidatorRepository, disciplineListDocumentRepository, disciplineListDocumentConsolidatorRepository, packageRepository, commentRepository, userRepository, userDisciplineRepository, reportTemplateRepository, db)
+		disciplineListDocumentService service.DisciplineListDocumentService = service.NewDisciplineListDocument(disciplineListDocumentRepository, disciplineGroupRepository, disciplineListDocumentConsolidatorRepository, commentRepository, packageRepository, documentRepository, userRepository, userDisciplineRepository, reportTemplateRepository, notificationService, db)
+		statisticService              service.StatisticService              = service.NewStatistic(statisticRepository, commentRepository, documentRepository, disciplineListDocumentRepository, userRepository, packageRepository, db)
+		packageService                service.PackageService                = service.NewPackage(packageRepository, userRepository, disciplineGroupService, reportTemplateRepository, db)
+		jobService                    service.JobService                    = service.NewJob(jobRepository, userRepository, db)
+		reportTemplateService         service.ReportTemplateService         = service.NewReportTemplate(reportTemplateRepository, packageRepository, userRepository, db)
+		signOffService                service.SignOffService                = service.NewSignOff(signOffRepository, userRepository, packageService, disciplineGroupService, db)
+		documentRoutingRuleService    service.DocumentRoutingRuleService    = service.NewDocumentRoutingRule(documentRoutingRuleRepository, disciplineGroupRepository, packageRepository, userRepository, db)
+		assignmentService             service.AssignmentService             = service.NewAssignment(assignmentRuleRepository, disciplineGroupRepository, disciplineGroupConsolidatorRepository, disciplineListDocumentRepository, disciplineListDocumentConsolidatorRepository, documentRepository, userRepository, userDisciplineRepository, delegationRepository, notificationService, db)
+		delegationService             service.DelegationService             = service.NewDelegation(delegationRepository, userRepository, db)
+		queueService                  service.QueueService                  = service.NewQueue(queueRepository, delegationRepository, userRepository, db)
+		searchService                 service.SearchService                 = service.NewSearch(searchRepository, userRepository, db)
+		savedViewService              service.SavedViewService              = service.NewSavedView(savedViewRepository, userRepository, packageRepository, db)
+		commentClassService           service.CommentClassService           = service.NewCommentClass(commentClassRepository, commentRepository, packageRepository, userRepository, db)
+		reviewOutcomeService          service.ReviewOutcomeService          = service.NewReviewOutcome(reviewOutcomeRepository, documentRepository, userRepository, delegationRepository, db)
+		reviewWindowService           service.ReviewWindowService           = service.NewReviewWindow(reviewWindowRepository, disciplineGroupRepository, packageRepository, userRepository, db)
+		transmittalService            service.TransmittalService            = service.NewTransmittal(transmittalRepository, documentRepository, packageRepository, userRepository, slaService, db)
 
 		//=========== (CONTROLLER) ===========//
 		authController                   controller.AuthController                   = controller.NewAuth(authService)
@@ -66,8 +97,27 @@ func NewRest() RestConfig {
 		disciplineGroupController        controller.DisciplineGroupController        = controller.NewDisciplineGroup(disciplineGroupService)
 		disciplineListDocumentController controller.DisciplineListDocumentController = controller.NewDisciplineListDocument(disciplineListDocumentService)
 		statisticController              controller.StatisticController              = controller.NewStatistic(statisticService)
+		jobController                    controller.JobController                    = controller.NewJob(jobService)
+		reportTemplateController         controller.ReportTemplateController         = controller.NewReportTemplate(reportTemplateService)
+		signOffController                controller.SignOffController                = controller.NewSignOff(signOffService)
+		assignmentController             controller.AssignmentController             = controller.NewAssignment(assignmentService)
+		documentRoutingRuleController    controller.DocumentRoutingRuleController    = controller.NewDocumentRoutingRule(documentRoutingRuleService)
+		delegationController             controller.DelegationController             = controller.NewDelegation(delegationService)
+		notificationController           controller.NotificationController           = controller.NewNotification(notificationService)
+		queueController                  controller.QueueController                  = controller.NewQueue(queueService)
+		searchController                 controller.SearchController                 = controller.NewSearch(searchService)
+		savedViewController              controller.SavedViewController              = controller.NewSavedView(savedViewService)
+		commentClassController           controller.CommentClassController           = controller.NewCommentClass(commentClassService)
+		reviewOutcomeController          controller.ReviewOutcomeController          = controller.NewReviewOutcome(reviewOutcomeService)
+		transmittalController            controller.TransmittalController            = controller.NewTransmittal(transmittalService)
+		slaController                    controller.SLAController                    = controller.NewSLA(slaService)
+		reviewWindowController           controller.ReviewWindowController           = controller.NewReviewWindow(reviewWindowService)
 	)
 
+	// Register background jobs
+	service.RegisterReportJobs(jobService, packageService, disciplineGroupService, disciplineGroupRepository)
+	jobService.Start()
+
 	// Register all routes
 	routes.Auth(server, authController, middleware)
 	routes.User(server, userController, middleware)
@@ -78,6 +128,21 @@ func NewRest() RestConfig {
 	routes.DisciplineListDocument(server, disciplineListDocumentController, middleware)
 	routes.Comment(server, commentController, middleware)
 	routes.Statistic(server, statisticController, middleware)
+	routes.Job(server, jobController, middleware)
+	routes.ReportTemplate(server, reportTemplateController, middleware)
+	routes.SignOff(server, signOffController, middleware)
+	routes.Assignment(server, assignmentController, middleware)
+	routes.DocumentRoutingRule(server, documentRoutingRuleController, middleware)
+	routes.Delegation(server, delegationController, middleware)
+	routes.Notification(server, notificationController, middleware)
+	routes.Queue(server, queueController, middleware)
+	routes.Search(server, searchController, middleware)
+	routes.SavedView(server, savedViewController, middleware)
+	routes.CommentClass(server, commentClassController, middleware)
+	routes.ReviewOutcome(server, reviewOutcomeController, middleware)
+	routes.Transmittal(server, transmittalController, middleware)
+	routes.SLA(server, slaController, middleware)
+	routes.ReviewWindow(server, reviewWindowController, middleware)
 
 	return RestConfig{
 		server: server,
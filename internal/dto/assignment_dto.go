@@ -0,0 +1,92 @@
+package dto
+
+import "time"
+
+type (
+	AssignmentRuleRequest struct {
+		ID                string  `json:"-"`
+		Name              string  `json:"name" binding:"required"`
+		Priority          int     `json:"priority"`
+		MatchField        string  `json:"match_field" binding:"required"`
+		MatchValue        string  `json:"match_value" binding:"required"`
+		ReviewerCount     int     `json:"reviewer_count" binding:"min=0"`
+		UserDisciplineID  *string `json:"user_discipline_id"`
+		UserID            *string `json:"user_id"`
+		DisciplineGroupID string  `json:"-"`
+		UserId            string  `json:"-"`
+	}
+
+	AssignmentRuleResponse struct {
+		ID               string  `json:"id"`
+		Name             string  `json:"name"`
+		Priority         int     `json:"priority"`
+		MatchField       string  `json:"match_field"`
+		MatchValue       string  `json:"match_value"`
+		ReviewerCount    int     `json:"reviewer_count"`
+		UserDisciplineID *string `json:"user_discipline_id"`
+		UserDiscipline   *string `json:"user_discipline"`
+		UserID           *string `json:"user_id"`
+		UserName         *string `json:"user_name"`
+	}
+
+	AssignmentProposeRequest struct {
+		DocumentIDs          []string `json:"document_ids" binding:"required,min=1"`
+		ReviewersPerDocument int      `json:"reviewers_per_document" binding:"min=0"`
+		DisciplineGroupID    string   `json:"-"`
+		UserId               string   `json:"-"`
+	}
+
+	AssignmentApplyRequest struct {
+		Assignments       []AssignmentApplyItem `json:"assignments" binding:"required,min=1,dive"`
+		DisciplineGroupID string                `json:"-"`
+		UserId            string                `json:"-"`
+	}
+
+	AssignmentApplyItem struct {
+		DocumentID      string   `json:"document_id" binding:"required"`
+		ConsolidatorIDs []string `json:"consolidator_ids"`
+	}
+
+	// AssignmentMatrixResponse lists documents against the consolidators of a
+	// discipline group. A proposal has the same shape, so it can be sent back
+	// as the assignments of an apply request.
+	AssignmentMatrixResponse struct {
+		Reviewers   []AssignmentReviewer `json:"reviewers"`
+		Assignments []AssignmentItem     `json:"assignments"`
+	}
+
+	AssignmentReviewer struct {
+		ConsolidatorID   string `json:"consolidator_id"`
+		UserID           string `json:"user_id"`
+		Name             string `json:"name"`
+		Initial          string `json:"initial"`
+		UserDiscipline   string `json:"user_discipline"`
+		DisciplineNumber int    `json:"discipline_number"`
+		OpenComment      int    `json:"open_comment"`
+		AssignedDocument int    `json:"assigned_document"`
+		// set while the consolidator is away
+		Substitute *AssignmentSubstitute `json:"substitute"`
+	}
+
+	AssignmentSubstitute struct {
+		UserID string    `json:"user_id"`
+		Name   string    `json:"name"`
+		Until  time.Time `json:"until"`
+	}
+
+	AssignmentItem struct {
+		DocumentID               string   `json:"document_id"`
+		DisciplineListDocumentID *string  `json:"discipline_list_document_id"`
+		CompanyDocumentNumber    string   `json:"company_document_number"`
+		DocumentTitle            string   `json:"document_title"`
+		Discipline               string   `json:"discipline"`
+		ConsolidatorIDs          []string `json:"consolidator_ids"`
+		Reason                   string   `json:"reason,omitempty"`
+	}
+
+	ReviewerLoad struct {
+		UserID           string `json:"user_id"`
+		OpenComment      int    `json:"open_comment"`
+		AssignedDocument int    `json:"assigned_document"`
+	}
+)
@@ -0,0 +1,34 @@
+package dto
+
+type (
+	CommentClassRequest struct {
+		ID          string  `json:"-"`
+		Kind        string  `json:"kind" binding:"required,oneof=CATEGORY SEVERITY"`
+		Code        string  `json:"code" binding:"required,max=20"`
+		Name        string  `json:"name" binding:"required"`
+		Description *string `json:"description"`
+		Rank        int     `json:"rank" binding:"gte=0"`
+		// ReviewOutcome only applies to severities
+		ReviewOutcome *string `json:"review_outcome" binding:"omitempty,oneof=APPROVED APPROVED_WITH_COMMENTS REVISE_AND_RESUBMIT REJECTED"`
+		PackageID     string  `json:"-"`
+		UserId        string  `json:"-"`
+	}
+
+	CommentClassResponse struct {
+		ID            string  `json:"id"`
+		Kind          string  `json:"kind"`
+		Code          string  `json:"code"`
+		Name          string  `json:"name"`
+		Description   *string `json:"description"`
+		Rank          int     `json:"rank"`
+		ReviewOutcome *string `json:"review_outcome"`
+		PackageID     string  `json:"package_id"`
+	}
+
+	// CommentClassCode is the class as shown on a comment
+	CommentClassCode struct {
+		ID   string `json:"id"`
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+)
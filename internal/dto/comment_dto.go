@@ -1,29 +1,59 @@
 package dto
 
+const (
+	ConsolidateAccept  = "ACCEPT"
+	ConsolidateDiscard = "DISCARD"
+	ConsolidateDraft   = "DRAFT"
+)
+
 type (
 	CommentRequest struct {
-		ID                       string  `json:"-"`
-		Section                  string  `json:"section" binding:""`
-		Comment                  string  `json:"comment" binding:"required"`
-		Baseline                 string  `json:"baseline" binding:""`
-		IsCloseOutComment        bool    `json:"is_close_out_comment" binding:""`
-		AttachFileUrl            *string `json:"attach_file_url" binding:""`
-		DisciplineListDocumentId string  `json:"-"`
-		UserId                   string  `json:"-"`
-		ReplyId                  string  `json:"-"`
+		ID                       string         `json:"-"`
+		Section                  string         `json:"section" binding:""`
+		Comment                  string         `json:"comment" binding:"required"`
+		Baseline                 string         `json:"baseline" binding:""`
+		IsCloseOutComment        bool           `json:"is_close_out_comment" binding:""`
+		AttachFileUrl            *string        `json:"attach_file_url" binding:""`
+		Anchor                   *CommentAnchor `json:"anchor" binding:""`
+		OnBehalfOfId             *string        `json:"on_behalf_of_id" binding:"omitempty,uuid"`
+		Visibility               string         `json:"visibility" binding:"omitempty,oneof=PUBLIC INTERNAL"`
+		Mentions                 []string       `json:"mentions" binding:"omitempty,dive,uuid"`
+		CategoryId               string         `json:"category_id" binding:"omitempty,uuid"`
+		SeverityId               string         `json:"severity_id" binding:"omitempty,uuid"`
+		DisciplineListDocumentId string         `json:"-"`
+		UserId                   string         `json:"-"`
+		ReplyId                  string         `json:"-"`
 	}
 
 	UpdateCommentRequest struct {
-		ID                       string  `json:"-"`
-		Section                  string  `json:"section" binding:""`
-		Comment                  string  `json:"comment" binding:"required"`
-		Baseline                 string  `json:"baseline" binding:""`
-		Status                   *string `json:"status"  binding:""`
-		IsCloseOutComment        bool    `json:"is_close_out_comment" binding:""`
-		AttachFileUrl            *string `json:"attach_file_url" binding:""`
-		DisciplineListDocumentId string  `json:"-"`
-		UserId                   string  `json:"-"`
-		ReplyId                  string  `json:"-"`
+		ID                       string         `json:"-"`
+		Section                  string         `json:"section" binding:""`
+		Comment                  string         `json:"comment" binding:"required"`
+		Baseline                 string         `json:"baseline" binding:""`
+		Status                   *string        `json:"status"  binding:""`
+		IsCloseOutComment        bool           `json:"is_close_out_comment" binding:""`
+		AttachFileUrl            *string        `json:"attach_file_url" binding:""`
+		Anchor                   *CommentAnchor `json:"anchor" binding:""`
+		ClearAnchor              bool           `json:"clear_anchor" binding:""`
+		Visibility               string         `json:"visibility" binding:"omitempty,oneof=PUBLIC INTERNAL"`
+		Mentions                 []string       `json:"mentions" binding:"omitempty,dive,uuid"`
+		CategoryId               string         `json:"category_id" binding:"omitempty,uuid"`
+		SeverityId               string         `json:"severity_id" binding:"omitempty,uuid"`
+		DisciplineListDocumentId string         `json:"-"`
+		UserId                   string         `json:"-"`
+		ReplyId                  string         `json:"-"`
+	}
+
+	// CommentAnchor points a comment to a spot in the reviewed document.
+	// The bounding box is optional and expressed as fractions of the page.
+	CommentAnchor struct {
+		Page     int      `json:"page" binding:"required,gte=1"`
+		X        *float64 `json:"x" binding:""`
+		Y        *float64 `json:"y" binding:""`
+		Width    *float64 `json:"width" binding:""`
+		Height   *float64 `json:"height" binding:""`
+		Quote    *string  `json:"quote" binding:""`
+		Revision string   `json:"revision" binding:""`
 	}
 
 	CommentResponse struct {
@@ -32,12 +62,91 @@ type (
 		Comment               string            `json:"comment"`
 		Baseline              string            `json:"baseline"`
 		Status                *string           `json:"status"`
+		Stage                 string            `json:"stage,omitempty"`
+		Visibility            string            `json:"visibility,omitempty"`
+		Category              *CommentClassCode `json:"category,omitempty"`
+		Severity              *CommentClassCode `json:"severity,omitempty"`
+		ParentReplyID         *string           `json:"parent_reply_id,omitempty"`
+		Depth                 int               `json:"depth,omitempty"`
 		DocumentID            string            `json:"document_id"`
 		CommentAt             string            `json:"comment_at"`
 		CompanyDocumentNumber string            `json:"company_document_number"`
 		IsCloseOutComment     bool              `json:"is_close_out_comment"`
 		AttachFileUrl         *string           `json:"attach_file_url"`
+		Anchor                *CommentAnchor    `json:"anchor,omitempty"`
 		UserComment           *UserComment      `json:"user_comment,omitempty"`
+		OnBehalfOf            *UserComment      `json:"on_behalf_of,omitempty"`
+		ConsolidatedBy        *UserComment      `json:"consolidated_by,omitempty"`
+		Mentions              []UserComment     `json:"mentions,omitempty"`
+		Reactions             []CommentReaction `json:"reactions,omitempty"`
+		DuplicateOfID         *string           `json:"duplicate_of_id,omitempty"`
+		SimilarityScore       *float64          `json:"similarity_score,omitempty"`
+		SimilarComments       []SimilarComment  `json:"similar_comments,omitempty"`
+		MergedComments        []CommentResponse `json:"merged_comments,omitempty"`
 		CommentReplies        []CommentResponse `json:"comment_replies"`
 	}
+
+	SimilarComment struct {
+		ID          string       `json:"id"`
+		Section     string       `json:"section"`
+		Comment     string       `json:"comment"`
+		Score       float64      `json:"score"`
+		UserComment *UserComment `json:"user_comment,omitempty"`
+	}
+
+	// CommentCluster is a group of open comments that read alike
+	CommentCluster struct {
+		Score    float64          `json:"score"`
+		Comments []SimilarComment `json:"comments"`
+	}
+
+	// MergeCommentRequest folds comments into a target one, either one of
+	// them or a new comment written from section, comment and baseline
+	MergeCommentRequest struct {
+		CommentIDs               []string `json:"comment_ids" binding:"required,min=1,dive,uuid"`
+		TargetID                 *string  `json:"target_id" binding:"omitempty,uuid"`
+		Section                  string   `json:"section"`
+		Comment                  string   `json:"comment"`
+		Baseline                 string   `json:"baseline"`
+		DisciplineListDocumentId string   `json:"-"`
+		UserId                   string   `json:"-"`
+	}
+
+	// ConsolidateCommentRequest moves a comment through consolidation.
+	// ACCEPT makes it official, applying the edits that are sent, DISCARD
+	// drops it from the CRS and DRAFT sends it back to its author.
+	ConsolidateCommentRequest struct {
+		ID                       string  `json:"-"`
+		Action                   string  `json:"action" binding:"required,oneof=ACCEPT DISCARD DRAFT"`
+		Section                  *string `json:"section" binding:""`
+		Comment                  *string `json:"comment" binding:""`
+		Baseline                 *string `json:"baseline" binding:""`
+		CategoryId               *string `json:"category_id" binding:"omitempty,uuid"`
+		SeverityId               *string `json:"severity_id" binding:"omitempty,uuid"`
+		DisciplineListDocumentId string  `json:"-"`
+		UserId                   string  `json:"-"`
+	}
+
+	CommentReaction struct {
+		Type  string        `json:"type"`
+		Count int           `json:"count"`
+		Users []UserComment `json:"users"`
+	}
+
+	CommentReactionRequest struct {
+		ID                       string `json:"-"`
+		Type                     string `json:"type" binding:"required,oneof=SEEN AGREE"`
+		DisciplineListDocumentId string `json:"-"`
+		UserId                   string `json:"-"`
+	}
+
+	// CommentRevisionResponse is the text of a comment before one of its edits
+	CommentRevisionResponse struct {
+		ID       string       `json:"id"`
+		Section  string       `json:"section"`
+		Comment  string       `json:"comment"`
+		Baseline string       `json:"baseline"`
+		EditedAt string       `json:"edited_at"`
+		EditedBy *UserComment `json:"edited_by,omitempty"`
+	}
 )
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type (
+	DelegationRequest struct {
+		ID           string    `json:"-"`
+		SubstituteID string    `json:"substitute_id" binding:"required,uuid"`
+		StartAt      time.Time `json:"start_at" binding:"required"`
+		EndAt        time.Time `json:"end_at" binding:"required"`
+		Reason       *string   `json:"reason"`
+		// only a super admin can delegate for someone else
+		OnBehalfOfID *string `json:"user_id" binding:"omitempty,uuid"`
+		UserId       string  `json:"-"`
+	}
+
+	DelegationResponse struct {
+		ID         string       `json:"id"`
+		StartAt    time.Time    `json:"start_at"`
+		EndAt      time.Time    `json:"end_at"`
+		Reason     *string      `json:"reason"`
+		IsActive   bool         `json:"is_active"`
+		User       *UserComment `json:"user"`
+		Substitute *UserComment `json:"substitute"`
+	}
+)
@@ -18,6 +18,39 @@ type (
 		UserId            string                                      `json:"-"`
 	}
 
+	// BulkDisciplineListDocumentRequest adds the listed documents, the ones
+	// matching the filter, or the listed ones that match the filter
+	BulkDisciplineListDocumentRequest struct {
+		DocumentIDs       []string                                    `json:"document_ids"`
+		Filter            *DocumentFilter                             `json:"filter"`
+		Consolidators     []DisciplineListDocumentConsolidatorRequest `json:"consolidators" binding:"dive"`
+		DisciplineGroupID string                                      `json:"-"`
+		UserId            string                                      `json:"-"`
+	}
+
+	// DocumentFilter matches values ignoring case, an empty list matches all
+	DocumentFilter struct {
+		DocumentIDs        []string `json:"-"`
+		Disciplines        []string `json:"disciplines"`
+		SubDisciplines     []string `json:"sub_disciplines"`
+		DocumentTypes      []string `json:"document_types"`
+		DocumentCategories []string `json:"document_categories"`
+		Statuses           []string `json:"statuses"`
+	}
+
+	BulkDisciplineListDocumentResponse struct {
+		Matched int                              `json:"matched"`
+		Created []BulkDisciplineListDocumentItem `json:"created"`
+		Skipped []BulkDisciplineListDocumentItem `json:"skipped"`
+	}
+
+	BulkDisciplineListDocumentItem struct {
+		DocumentID               string  `json:"document_id"`
+		CompanyDocumentNumber    string  `json:"company_document_number,omitempty"`
+		DisciplineListDocumentID *string `json:"discipline_list_document_id,omitempty"`
+		Reason                   string  `json:"reason,omitempty"`
+	}
+
 	DisciplineListDocumentResponse struct {
 		ID            string                                       `json:"id"`
 		Package       string                                       `json:"package"`
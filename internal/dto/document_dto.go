@@ -16,6 +16,8 @@ type (
 		CompanyDocumentNumber    string     `json:"company_document_number" binding:""`
 		ContractorDocumentNumber string     `json:"contractor_document_number"`
 		DocumentTitle            string     `json:"document_title" binding:""`
+		Revision                 string     `json:"revision" binding:""`
+		PageCount                *int       `json:"page_count" binding:"omitempty,gte=1"`
 		Discipline               string     `json:"discipline" binding:""`
 		SubDiscipline            *string    `json:"sub_discipline"`
 		DocumentType             string     `json:"document_type" binding:""`
@@ -34,6 +36,8 @@ type (
 		CompanyDocumentNumber    string     `json:"company_document_number" binding:""`
 		ContractorDocumentNumber string     `json:"contractor_document_number" binding:""`
 		DocumentTitle            string     `json:"document_title" binding:""`
+		Revision                 string     `json:"revision" binding:""`
+		PageCount                *int       `json:"page_count" binding:"omitempty,gte=1"`
 		Discipline               string     `json:"discipline" binding:""`
 		SubDiscipline            *string    `json:"sub_discipline"`
 		DocumentType             string     `json:"document_type" binding:""`
@@ -49,33 +53,37 @@ type (
 	}
 
 	GetAllDocumentResponse struct {
-		ID                       string     `json:"id"`
-		CompanyDocumentNumber    string     `json:"company_document_number"`
-		ContractorDocumentNumber string     `json:"contractor_document_number"`
-		DocumentTitle            string     `json:"document_title"`
-		DocumentType             string     `json:"document_type"`
-		DocumentCategory         string     `json:"document_category"`
-		Package                  string     `json:"package"`
-		DueDate                  *time.Time `json:"due_date"`
-		Status                   string     `json:"status"`
-		TotalComments            int        `json:"total_comment"`
+		ID                       string             `json:"id"`
+		CompanyDocumentNumber    string             `json:"company_document_number"`
+		ContractorDocumentNumber string             `json:"contractor_document_number"`
+		DocumentTitle            string             `json:"document_title"`
+		DocumentType             string             `json:"document_type"`
+		DocumentCategory         string             `json:"document_category"`
+		Package                  string             `json:"package"`
+		DueDate                  *time.Time         `json:"due_date"`
+		Status                   string             `json:"status"`
+		TotalComments            int                `json:"total_comment"`
+		ReviewOutcome            *ReviewOutcomeCode `json:"review_outcome"`
 	}
 
 	DocumentDetailResponse struct {
-		ID                       string     `json:"id"`
-		DocumentUrl              *string    `json:"document_url"`
-		DocumentSerialNumber     string     `json:"document_serial_number"`
-		CTRNumber                string     `json:"ctr_number"`
-		WBS                      string     `json:"wbs"`
-		CompanyDocumentNumber    string     `json:"company_document_number"`
-		ContractorDocumentNumber string     `json:"contractor_document_number"`
-		DocumentTitle            string     `json:"document_title"`
-		Discipline               string     `json:"discipline"`
-		SubDiscipline            *string    `json:"sub_discipline"`
-		DocumentType             string     `json:"document_type"`
-		DocumentCategory         string     `json:"document_category"`
-		Package                  string     `json:"package"`
-		DueDate                  *time.Time `json:"due_date"`
-		Status                   string     `json:"status"`
+		ID                       string             `json:"id"`
+		DocumentUrl              *string            `json:"document_url"`
+		DocumentSerialNumber     string             `json:"document_serial_number"`
+		CTRNumber                string             `json:"ctr_number"`
+		WBS                      string             `json:"wbs"`
+		CompanyDocumentNumber    string             `json:"company_document_number"`
+		ContractorDocumentNumber string             `json:"contractor_document_number"`
+		DocumentTitle            string             `json:"document_title"`
+		Revision                 string             `json:"revision"`
+		PageCount                *int               `json:"page_count"`
+		Discipline               string             `json:"discipline"`
+		SubDiscipline            *string            `json:"sub_discipline"`
+		DocumentType             string             `json:"document_type"`
+		DocumentCategory         string             `json:"document_category"`
+		Package                  string             `json:"package"`
+		DueDate                  *time.Time         `json:"due_date"`
+		Status                   string             `json:"status"`
+		ReviewOutcome            *ReviewOutcomeCode `json:"review_outcome,omitempty"`
 	}
 )
@@ -0,0 +1,21 @@
+package dto
+
+type (
+	DocumentRoutingRuleRequest struct {
+		ID                string  `json:"-"`
+		Discipline        string  `json:"discipline" binding:"required"`
+		DocumentType      *string `json:"document_type"`
+		DisciplineGroupID string  `json:"discipline_group_id" binding:"required,uuid"`
+		PackageID         string  `json:"-"`
+		UserId            string  `json:"-"`
+	}
+
+	DocumentRoutingRuleResponse struct {
+		ID                string  `json:"id"`
+		Discipline        string  `json:"discipline"`
+		DocumentType      *string `json:"document_type"`
+		DisciplineGroupID string  `json:"discipline_group_id"`
+		ReviewFocus       string  `json:"review_focus"`
+		PackageID         string  `json:"package_id"`
+	}
+)
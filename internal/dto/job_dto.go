@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type (
+	JobRequest struct {
+		Type   string            `json:"type" binding:"required"`
+		Params map[string]string `json:"params" binding:"required"`
+		UserId string            `json:"-"`
+	}
+
+	JobResponse struct {
+		ID          string            `json:"id"`
+		Type        string            `json:"type"`
+		Status      string            `json:"status"`
+		Progress    int               `json:"progress"`
+		Params      map[string]string `json:"params"`
+		Error       *string           `json:"error"`
+		Filename    *string           `json:"filename"`
+		CreatedAt   time.Time         `json:"created_at"`
+		StartedAt   *time.Time        `json:"started_at"`
+		FinishedAt  *time.Time        `json:"finished_at"`
+		ExpiresAt   *time.Time        `json:"expires_at"`
+		IsDuplicate bool              `json:"is_duplicate"`
+	}
+
+	JobArtifact struct {
+		Path        string
+		Filename    string
+		ContentType string
+	}
+)
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+type (
+	NotificationResponse struct {
+		ID                       string       `json:"id"`
+		Type                     string       `json:"type"`
+		Title                    string       `json:"title"`
+		Message                  string       `json:"message"`
+		IsRead                   bool         `json:"is_read"`
+		ReadAt                   *time.Time   `json:"read_at"`
+		CreatedAt                time.Time    `json:"created_at"`
+		DisciplineListDocumentID *string      `json:"discipline_list_document_id"`
+		CommentID                *string      `json:"comment_id"`
+		OnBehalfOf               *UserComment `json:"on_behalf_of"`
+	}
+
+	ReadNotificationRequest struct {
+		NotificationIDs []string `json:"notification_ids" binding:"dive,uuid"`
+		UserId          string   `json:"-"`
+	}
+)
@@ -2,17 +2,23 @@ package dto
 
 type (
 	CreatePackageRequest struct {
-		Name string `json:"name" binding:"required"`
+		Name             string  `json:"name" binding:"required"`
+		ReviewPeriodDays *int    `json:"review_period_days" binding:"omitempty,gte=1"`
+		TimeZone         *string `json:"time_zone"`
 	}
 
 	UpdatePackageRequest struct {
-		ID   string `json:"id" binding:"required"`
-		Name string `json:"name" binding:"required"`
+		ID               string  `json:"id" binding:"required"`
+		Name             string  `json:"name" binding:"required"`
+		ReviewPeriodDays *int    `json:"review_period_days" binding:"omitempty,gte=1"`
+		TimeZone         *string `json:"time_zone"`
 	}
 
 	PackageInfo struct {
-		ID          string `json:"id"`
-		Name        string `json:"name"`
-		Description string `json:"description"`
+		ID               string `json:"id"`
+		Name             string `json:"name"`
+		Description      string `json:"description"`
+		ReviewPeriodDays int    `json:"review_period_days"`
+		TimeZone         string `json:"time_zone"`
 	}
 )
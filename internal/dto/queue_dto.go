@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+type (
+	QueueItem struct {
+		Type                     string       `json:"type"`
+		Urgency                  string       `json:"urgency"`
+		DueDate                  *time.Time   `json:"due_date"`
+		At                       time.Time    `json:"at"`
+		PackageID                string       `json:"package_id"`
+		PackageName              string       `json:"package_name"`
+		DisciplineGroupID        string       `json:"discipline_group_id"`
+		ReviewFocus              string       `json:"review_focus"`
+		DisciplineListDocumentID string       `json:"discipline_list_document_id"`
+		DocumentID               string       `json:"document_id"`
+		CompanyDocumentNumber    string       `json:"company_document_number"`
+		DocumentTitle            string       `json:"document_title"`
+		OpenComment              int          `json:"open_comment"`
+		CommentID                *string      `json:"comment_id,omitempty"`
+		Comment                  *string      `json:"comment,omitempty"`
+		ReplyID                  *string      `json:"reply_id,omitempty"`
+		Reply                    *string      `json:"reply,omitempty"`
+		ReplyBy                  *string      `json:"reply_by,omitempty"`
+		OnBehalfOf               *UserComment `json:"on_behalf_of,omitempty"`
+	}
+
+	QueueDocumentRow struct {
+		OwnerID                  string     `json:"owner_id"`
+		DisciplineListDocumentID string     `json:"discipline_list_document_id"`
+		DisciplineGroupID        string     `json:"discipline_group_id"`
+		ReviewFocus              string     `json:"review_focus"`
+		PackageID                string     `json:"package_id"`
+		PackageName              string     `json:"package_name"`
+		DocumentID               string     `json:"document_id"`
+		CompanyDocumentNumber    string     `json:"company_document_number"`
+		DocumentTitle            string     `json:"document_title"`
+		DueDate                  *time.Time `json:"due_date"`
+		AssignedAt               time.Time  `json:"assigned_at"`
+		MyComment                int        `json:"my_comment"`
+		OpenComment              int        `json:"open_comment"`
+	}
+
+	QueueCommentRow struct {
+		OwnerID                  string     `json:"owner_id"`
+		CommentID                string     `json:"comment_id"`
+		Comment                  string     `json:"comment"`
+		ReplyID                  string     `json:"reply_id"`
+		Reply                    string     `json:"reply"`
+		ReplyBy                  string     `json:"reply_by"`
+		At                       time.Time  `json:"at"`
+		DisciplineListDocumentID string     `json:"discipline_list_document_id"`
+		DisciplineGroupID        string     `json:"discipline_group_id"`
+		ReviewFocus              string     `json:"review_focus"`
+		PackageID                string     `json:"package_id"`
+		PackageName              string     `json:"package_name"`
+		DocumentID               string     `json:"document_id"`
+		CompanyDocumentNumber    string     `json:"company_document_number"`
+		DocumentTitle            string     `json:"document_title"`
+		DueDate                  *time.Time `json:"due_date"`
+	}
+)
@@ -0,0 +1,47 @@
+package dto
+
+type (
+	ReportTemplateRequest struct {
+		PackageId       string                      `json:"-"`
+		UserId          string                      `json:"-"`
+		LogoUrl         *string                     `json:"logo_url"`
+		Title           string                      `json:"title" binding:"required"`
+		PaperSize       string                      `json:"paper_size" binding:"required,oneof=A3 A4 A5 Letter Legal"`
+		Orientation     string                      `json:"orientation" binding:"required,oneof=L P"`
+		HeaderFields    []ReportTemplateHeaderField `json:"header_fields" binding:"dive"`
+		Columns         []ReportTemplateColumn      `json:"columns" binding:"required,min=1,dive"`
+		FooterNote      string                      `json:"footer_note"`
+		SignatureBlocks []string                    `json:"signature_blocks" binding:"max=6"`
+	}
+
+	ReportTemplateHeaderField struct {
+		Label string `json:"label" binding:"required"`
+		Key   string `json:"key"`
+		Value string `json:"value"`
+		Right bool   `json:"right"`
+	}
+
+	ReportTemplateColumn struct {
+		Key        string  `json:"key" binding:"required"`
+		Label      string  `json:"label" binding:"required"`
+		Width      float64 `json:"width" binding:"required,gt=0"`
+		ExcelWidth float64 `json:"excel_width" binding:"gte=0"`
+		Highlight  bool    `json:"highlight"`
+	}
+
+	ReportTemplateResponse struct {
+		ID              *string                     `json:"id"`
+		PackageID       string                      `json:"package_id"`
+		IsDefault       bool                        `json:"is_default"`
+		LogoUrl         *string                     `json:"logo_url"`
+		Title           string                      `json:"title"`
+		PaperSize       string                      `json:"paper_size"`
+		Orientation     string                      `json:"orientation"`
+		HeaderFields    []ReportTemplateHeaderField `json:"header_fields"`
+		Columns         []ReportTemplateColumn      `json:"columns"`
+		FooterNote      string                      `json:"footer_note"`
+		SignatureBlocks []string                    `json:"signature_blocks"`
+		AvailableColumn []string                    `json:"available_columns"`
+		AvailableHeader []string                    `json:"available_header_keys"`
+	}
+)
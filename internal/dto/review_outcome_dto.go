@@ -0,0 +1,43 @@
+package dto
+
+type (
+	ReviewOutcomeRequest struct {
+		Code       string  `json:"code" binding:"required,oneof=APPROVED APPROVED_WITH_COMMENTS REVISE_AND_RESUBMIT REJECTED"`
+		Remarks    *string `json:"remarks"`
+		DocumentID string  `json:"-"`
+		UserId     string  `json:"-"`
+	}
+
+	// ReviewOutcomeCode is a code along with its number and label, e.g. 2 and
+	// "2 - Approved with comments"
+	ReviewOutcomeCode struct {
+		Code   string `json:"code"`
+		Number int    `json:"number"`
+		Label  string `json:"label"`
+	}
+
+	ReviewOutcomeResponse struct {
+		ID        string             `json:"id"`
+		Revision  string             `json:"revision"`
+		Code      ReviewOutcomeCode  `json:"code"`
+		Suggested *ReviewOutcomeCode `json:"suggested,omitempty"`
+		Remarks   *string            `json:"remarks"`
+		SetAt     string             `json:"set_at"`
+		SetBy     *UserComment       `json:"set_by,omitempty"`
+	}
+
+	// ReviewOutcomeSuggestion is the code the rules give for the current
+	// comments of the document and why
+	ReviewOutcomeSuggestion struct {
+		Code    ReviewOutcomeCode `json:"code"`
+		Reasons []string          `json:"reasons"`
+	}
+
+	DocumentReviewOutcomeResponse struct {
+		DocumentID string                  `json:"document_id"`
+		Revision   string                  `json:"revision"`
+		Current    *ReviewOutcomeResponse  `json:"current"`
+		Suggestion ReviewOutcomeSuggestion `json:"suggestion"`
+		History    []ReviewOutcomeResponse `json:"history"`
+	}
+)
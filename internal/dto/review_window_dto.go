@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type (
+	ReviewWindowRequest struct {
+		ID   string `json:"-"`
+		Name string `json:"name" binding:"required"`
+		// DisciplineGroupID left empty opens the window for the whole package
+		DisciplineGroupID *string   `json:"discipline_group_id" binding:"omitempty,uuid"`
+		OpensAt           time.Time `json:"opens_at" binding:"required"`
+		ClosesAt          time.Time `json:"closes_at" binding:"required"`
+		PackageID         string    `json:"-"`
+		UserId            string    `json:"-"`
+	}
+
+	ReviewWindowResponse struct {
+		ID              string                       `json:"id"`
+		Name            string                       `json:"name"`
+		OpensAt         time.Time                    `json:"opens_at"`
+		ClosesAt        time.Time                    `json:"closes_at"`
+		IsOpen          bool                         `json:"is_open"`
+		DisciplineGroup *ReviewWindowDisciplineGroup `json:"discipline_group"`
+		PackageID       string                       `json:"package_id"`
+	}
+
+	ReviewWindowDisciplineGroup struct {
+		ID          string `json:"id"`
+		ReviewFocus string `json:"review_focus"`
+	}
+)
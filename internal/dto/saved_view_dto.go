@@ -0,0 +1,41 @@
+package dto
+
+type (
+	SavedViewRequest struct {
+		ID       string   `json:"-"`
+		Name     string   `json:"name" binding:"required"`
+		Resource string   `json:"resource" binding:"required,oneof=DOCUMENT COMMENT DISCIPLINE_LIST_DOCUMENT"`
+		Take     int      `json:"take" binding:"min=0"`
+		Sort     string   `json:"sort" binding:"omitempty,oneof=asc desc"`
+		SortBy   string   `json:"sort_by"`
+		Filter   string   `json:"filter"`
+		FilterBy string   `json:"filter_by"`
+		Query    string   `json:"q"`
+		Columns  []string `json:"columns"`
+		IsShared bool     `json:"is_shared"`
+		// only a super admin can publish a package default
+		IsDefault bool `json:"is_default"`
+		// required for a super admin sharing or publishing a view
+		PackageID *string `json:"package_id" binding:"omitempty,uuid"`
+		UserId    string  `json:"-"`
+	}
+
+	SavedViewResponse struct {
+		ID          string       `json:"id"`
+		Name        string       `json:"name"`
+		Resource    string       `json:"resource"`
+		Take        int          `json:"take"`
+		Sort        string       `json:"sort"`
+		SortBy      string       `json:"sort_by"`
+		Filter      string       `json:"filter"`
+		FilterBy    string       `json:"filter_by"`
+		Query       string       `json:"q"`
+		Columns     []string     `json:"columns"`
+		IsShared    bool         `json:"is_shared"`
+		IsDefault   bool         `json:"is_default"`
+		IsOwner     bool         `json:"is_owner"`
+		PackageID   *string      `json:"package_id"`
+		PackageName *string      `json:"package_name"`
+		User        *UserComment `json:"user"`
+	}
+)
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+type (
+	SearchRequest struct {
+		Keyword string
+		Type    string
+		UserId  string
+	}
+
+	// SearchResult is a document or a comment matching the keyword, the
+	// matched words are wrapped in <mark> in the snippet
+	SearchResult struct {
+		Type                     string    `json:"type"`
+		ID                       string    `json:"id"`
+		Rank                     float64   `json:"rank"`
+		Snippet                  string    `json:"snippet"`
+		PackageID                string    `json:"package_id"`
+		PackageName              string    `json:"package_name"`
+		DocumentID               string    `json:"document_id"`
+		CompanyDocumentNumber    string    `json:"company_document_number"`
+		DocumentTitle            string    `json:"document_title"`
+		DisciplineGroupID        *string   `json:"discipline_group_id,omitempty"`
+		DisciplineListDocumentID *string   `json:"discipline_list_document_id,omitempty"`
+		CommentReplyID           *string   `json:"comment_reply_id,omitempty"`
+		CreatedAt                time.Time `json:"created_at"`
+	}
+
+	SearchParams struct {
+		Query      string
+		Pattern    string
+		PackageID  string
+		Documents  bool
+		Comments   bool
+		PublicOnly bool
+		Limit      int
+		Offset     int
+	}
+)
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type (
+	SignOffRequest struct {
+		PackageID         *string              `json:"package_id"`
+		DisciplineGroupID *string              `json:"discipline_group_id"`
+		Steps             []SignOffStepRequest `json:"steps" binding:"required,min=1,dive"`
+		UserId            string               `json:"-"`
+	}
+
+	SignOffStepRequest struct {
+		Role   string `json:"role" binding:"required"`
+		UserID string `json:"user_id" binding:"required,uuid"`
+	}
+
+	SignOffActionRequest struct {
+		Note      *string `json:"note"`
+		UserId    string  `json:"-"`
+		SignOffId string  `json:"-"`
+	}
+
+	SignOffVerifyRequest struct {
+		File *multipart.FileHeader `form:"file" binding:"required"`
+	}
+
+	SignOffResponse struct {
+		ID                string                `json:"id"`
+		Scope             string                `json:"scope"`
+		Status            string                `json:"status"`
+		PackageID         string                `json:"package_id"`
+		DisciplineGroupID *string               `json:"discipline_group_id"`
+		ContentHash       string                `json:"content_hash"`
+		FileHash          *string               `json:"file_hash"`
+		CompletedAt       *time.Time            `json:"completed_at"`
+		CreatedAt         time.Time             `json:"created_at"`
+		Steps             []SignOffStepResponse `json:"steps"`
+	}
+
+	SignOffStepResponse struct {
+		ID          string     `json:"id"`
+		Sequence    int        `json:"sequence"`
+		Role        string     `json:"role"`
+		Status      string     `json:"status"`
+		UserID      string     `json:"user_id"`
+		Name        string     `json:"name"`
+		ContentHash *string    `json:"content_hash"`
+		Note        *string    `json:"note"`
+		ActedAt     *time.Time `json:"acted_at"`
+	}
+
+	SignOffVerifyResponse struct {
+		Valid       bool             `json:"valid"`
+		Message     string           `json:"message"`
+		FileHash    string           `json:"file_hash"`
+		ContentHash *string          `json:"content_hash"`
+		SignOff     *SignOffResponse `json:"sign_off"`
+	}
+)
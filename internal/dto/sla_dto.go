@@ -0,0 +1,36 @@
+package dto
+
+type (
+	SLARuleRequest struct {
+		ID string `json:"-"`
+		// Purpose left empty applies to the purposes without a rule
+		Purpose      *string `json:"purpose" binding:"omitempty,oneof=IFR IFA IFI IFC IFU"`
+		Resubmission bool    `json:"resubmission"`
+		WorkingDays  int     `json:"working_days" binding:"required,gte=1,lte=365"`
+		PackageID    string  `json:"-"`
+		UserId       string  `json:"-"`
+	}
+
+	SLARuleResponse struct {
+		ID           string  `json:"id"`
+		Purpose      *string `json:"purpose"`
+		Resubmission bool    `json:"resubmission"`
+		WorkingDays  int     `json:"working_days"`
+		PackageID    string  `json:"package_id"`
+	}
+
+	HolidayRequest struct {
+		ID        string `json:"-"`
+		Date      string `json:"date" binding:"required,datetime=2006-01-02"`
+		Name      string `json:"name" binding:"required"`
+		PackageID string `json:"-"`
+		UserId    string `json:"-"`
+	}
+
+	HolidayResponse struct {
+		ID        string `json:"id"`
+		Date      string `json:"date"`
+		Name      string `json:"name"`
+		PackageID string `json:"package_id"`
+	}
+)
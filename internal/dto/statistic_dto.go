@@ -1,14 +1,54 @@
 package dto
 
+import "time"
+
+const (
+	StatisticGranularityDay   = "day"
+	StatisticGranularityWeek  = "week"
+	StatisticGranularityMonth = "month"
+
+	StatisticSeriesPerPeriod  = "per_period"
+	StatisticSeriesCumulative = "cumulative"
+)
+
 type (
+	// StatisticSeriesFilter are the query params of a time series, From and
+	// To are dates. Without a granularity the periods are 2 days long. A
+	// cumulative series counts everything up to the end of each period, the
+	// S-curve of the package.
+	StatisticSeriesFilter struct {
+		From        *time.Time `form:"from" time_format:"2006-01-02"`
+		To          *time.Time `form:"to" time_format:"2006-01-02"`
+		Granularity string     `form:"granularity" binding:"omitempty,oneof=day week month"`
+		Series      string     `form:"series" binding:"omitempty,oneof=per_period cumulative"`
+		// set by the service, a series without from starts at its first
+		// data but never before Earliest
+		Earliest *time.Time `form:"-"`
+	}
+
+	StatisticCommentChartFilter struct {
+		StatisticSeriesFilter
+		GroupBy string `form:"group_by" binding:"omitempty,oneof=discipline_group reviewer document_type category"`
+	}
+
 	StatisticAOCAndCommentChart struct {
 		Name                        string `json:"name"`
+		Date                        string `json:"date"`
 		TotalDisciplineListDocument int    `json:"total_discipline_list_document"`
 		TotalDocuments              int    `json:"total_documents"`
 		TotalComments               int    `json:"total_comments"`
 		TotalCommentRejected        int    `json:"total_comment_rejected"`
 	}
 
+	StatisticCommentChart struct {
+		Name                 string `json:"name"`
+		Date                 string `json:"date"`
+		Group                string `json:"group"`
+		TotalComments        int    `json:"total_comments"`
+		CommentClosed        int    `json:"comment_closed"`
+		TotalCommentRejected int    `json:"total_comment_rejected"`
+	}
+
 	StatisticAOCAndCommentCard struct {
 		TotalDisciplineGroup         int `json:"total_discipline_group"`
 		TotalDocuments               int `json:"total_documents"`
@@ -17,6 +57,23 @@ type (
 		TotalDocumentsWithoutComment int `json:"total_documents_without_comment"`
 	}
 
+	// StatisticUserCard counts the work of a user in a package, assigned
+	// documents are those the user consolidates
+	StatisticUserCard struct {
+		TotalComments          int `json:"total_comments"`
+		CommentClosed          int `json:"comment_closed"`
+		TotalCommentRejected   int `json:"total_comment_rejected"`
+		TotalReplies           int `json:"total_replies"`
+		TotalAssignedDocuments int `json:"total_assigned_documents"`
+	}
+
+	StatisticMe struct {
+		StatisticUserCard
+		PackageID string                     `json:"package_id"`
+		Package   string                     `json:"package"`
+		SLA       StatisticSLAComplianceData `json:"sla"`
+	}
+
 	StatisticCommentUsersChart struct {
 		Name          string `json:"name"`
 		CommentClosed int    `json:"comment_closed"`
@@ -29,4 +86,47 @@ type (
 		CommentClosed int    `json:"comment_closed"`
 		TotalComment  int    `json:"total_comment"`
 	}
+
+	StatisticCommentClassChart struct {
+		ID            *string `json:"id"`
+		Code          string  `json:"code"`
+		Name          string  `json:"name"`
+		CommentClosed int     `json:"comment_closed"`
+		TotalComment  int     `json:"total_comment"`
+	}
+
+	StatisticReviewOutcomeChart struct {
+		Code           string `json:"code"`
+		Number         int    `json:"number"`
+		Name           string `json:"name"`
+		TotalDocuments int    `json:"total_documents"`
+	}
+
+	// StatisticSLAResponse is one review round of a discipline group or a
+	// reviewer, RespondedAt is empty while nothing was done in it
+	StatisticSLAResponse struct {
+		ID          string     `json:"id"`
+		Name        string     `json:"name"`
+		DueDate     time.Time  `json:"due_date"`
+		RespondedAt *time.Time `json:"responded_at"`
+	}
+
+	StatisticSLACompliance struct {
+		DisciplineGroups []StatisticSLAComplianceData `json:"discipline_groups"`
+		Reviewers        []StatisticSLAComplianceData `json:"reviewers"`
+	}
+
+	// StatisticSLAComplianceData counts the review rounds by how they went
+	// against their due date, ComplianceRate is the share of on time rounds
+	// among the ones that are due in percent
+	StatisticSLAComplianceData struct {
+		ID             string  `json:"id"`
+		Name           string  `json:"name"`
+		Total          int     `json:"total"`
+		OnTime         int     `json:"on_time"`
+		Late           int     `json:"late"`
+		Overdue        int     `json:"overdue"`
+		Pending        int     `json:"pending"`
+		ComplianceRate float64 `json:"compliance_rate"`
+	}
 )
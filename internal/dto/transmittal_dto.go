@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+type (
+	TransmittalRequest struct {
+		ID              string                       `json:"-"`
+		Direction       string                       `json:"direction" binding:"required,oneof=INCOMING OUTGOING"`
+		Purpose         string                       `json:"purpose" binding:"required,oneof=IFR IFA IFI IFC IFU"`
+		ReferenceNumber *string                      `json:"reference_number"`
+		Subject         string                       `json:"subject" binding:"required"`
+		Remarks         *string                      `json:"remarks"`
+		TransmittalDate time.Time                    `json:"transmittal_date" binding:"required"`
+		ReceivedAt      *time.Time                   `json:"received_at"`
+		ReplyToID       *string                      `json:"reply_to_id" binding:"omitempty,uuid"`
+		Documents       []TransmittalDocumentRequest `json:"documents" binding:"required,min=1,dive"`
+		PackageID       string                       `json:"-"`
+		UserId          string                       `json:"-"`
+	}
+
+	// TransmittalDocumentRequest lists a document, the revision defaults to
+	// the current revision of the document
+	TransmittalDocumentRequest struct {
+		DocumentID string  `json:"document_id" binding:"required,uuid"`
+		Revision   *string `json:"revision"`
+	}
+
+	TransmittalResponse struct {
+		ID              string                        `json:"id"`
+		Number          string                        `json:"number"`
+		Direction       string                        `json:"direction"`
+		Purpose         string                        `json:"purpose"`
+		ReferenceNumber *string                       `json:"reference_number"`
+		Subject         string                        `json:"subject"`
+		Remarks         *string                       `json:"remarks"`
+		TransmittalDate time.Time                     `json:"transmittal_date"`
+		ReceivedAt      *time.Time                    `json:"received_at"`
+		ReplyTo         *TransmittalReference         `json:"reply_to,omitempty"`
+		PackageID       string                        `json:"package_id"`
+		TotalDocuments  int                           `json:"total_documents"`
+		Documents       []TransmittalDocumentResponse `json:"documents,omitempty"`
+	}
+
+	TransmittalReference struct {
+		ID     string `json:"id"`
+		Number string `json:"number"`
+	}
+
+	TransmittalDocumentResponse struct {
+		DocumentID               string             `json:"document_id"`
+		CompanyDocumentNumber    string             `json:"company_document_number"`
+		ContractorDocumentNumber string             `json:"contractor_document_number"`
+		DocumentTitle            string             `json:"document_title"`
+		Revision                 string             `json:"revision"`
+		DueDate                  *time.Time         `json:"due_date"`
+		ReviewOutcome            *ReviewOutcomeCode `json:"review_outcome,omitempty"`
+	}
+)
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type AssignmentMatchField string
+
+const (
+	AssignmentMatchDiscipline       AssignmentMatchField = "discipline"
+	AssignmentMatchSubDiscipline    AssignmentMatchField = "sub_discipline"
+	AssignmentMatchDocumentType     AssignmentMatchField = "document_type"
+	AssignmentMatchDocumentCategory AssignmentMatchField = "document_category"
+	AssignmentMatchWBS              AssignmentMatchField = "wbs"
+)
+
+// AssignmentRule tells the assignment proposal who should review the
+// documents of a discipline group that match it. Rules run by priority,
+// lowest first, and the first match wins.
+type AssignmentRule struct {
+	ID            uuid.UUID            `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name          string               `json:"name" gorm:"not null"`
+	Priority      int                  `json:"priority" gorm:"not null;default:0"`
+	MatchField    AssignmentMatchField `json:"match_field" gorm:"not null"`
+	MatchValue    string               `json:"match_value" gorm:"not null"`
+	ReviewerCount int                  `json:"reviewer_count" gorm:"not null;default:1"`
+
+	// only consolidators of this discipline are proposed when set
+	UserDisciplineID *uuid.UUID `json:"user_discipline_id" gorm:"type:uuid"`
+	// this consolidator is always proposed when set
+	UserID            *uuid.UUID `json:"user_id" gorm:"type:uuid"`
+	DisciplineGroupID uuid.UUID  `json:"discipline_group_id" gorm:"type:uuid;not null;index"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	UserDiscipline  *UserDiscipline  `json:"user_discipline,omitempty" gorm:"foreignKey:UserDisciplineID"`
+	User            *User            `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	DisciplineGroup *DisciplineGroup `json:"discipline_group,omitempty" gorm:"foreignKey:DisciplineGroupID"`
+}
+
+var AssignmentMatchFields = []AssignmentMatchField{
+	AssignmentMatchDiscipline,
+	AssignmentMatchSubDiscipline,
+	AssignmentMatchDocumentType,
+	AssignmentMatchDocumentCategory,
+	AssignmentMatchWBS,
+}
+
+// Matches compares the rule value with the document field, ignoring case
+func (r AssignmentRule) Matches(document Document) bool {
+	var value string
+	switch r.MatchField {
+	case AssignmentMatchDiscipline:
+		value = document.Discipline
+	case AssignmentMatchSubDiscipline:
+		if document.SubDiscipline != nil {
+			value = *document.SubDiscipline
+		}
+	case AssignmentMatchDocumentType:
+		value = document.DocumentType
+	case AssignmentMatchDocumentCategory:
+		value = document.DocumentCategory
+	case AssignmentMatchWBS:
+		value = document.WBS
+	}
+
+	return value != "" && strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(r.MatchValue))
+}
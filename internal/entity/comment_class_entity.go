@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+type CommentClassKind string
+
+const (
+	CommentClassCategory CommentClassKind = "CATEGORY"
+	CommentClassSeverity CommentClassKind = "SEVERITY"
+)
+
+// CommentClass is a category (e.g. Design Basis, Safety, Editorial) or a
+// severity (e.g. Code A/B/C, Major/Minor) comments of a package are
+// classified with. Once a package defines classes of a kind every new
+// comment needs one. Rank orders the classes, for severities 1 is the most
+// severe. A severity may carry the review outcome its open comments hold the
+// document back to.
+type CommentClass struct {
+	ID          uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Kind        CommentClassKind `json:"kind" gorm:"not null"`
+	Code        string           `json:"code" gorm:"not null"`
+	Name        string           `json:"name" gorm:"not null"`
+	Description *string          `json:"description" gorm:""`
+	Rank        int              `json:"rank" gorm:"default:0;not null"`
+	// only for severities, see ReviewOutcomeCode
+	ReviewOutcome *ReviewOutcomeCode `json:"review_outcome" gorm:""`
+
+	PackageID uuid.UUID `json:"package_id" gorm:"type:uuid;not null;index"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	Package *Package `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+}
+
+// CodeOf is the code printed on the CRS, empty when the comment has no class
+// of the kind
+func (c *CommentClass) CodeOf() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.Code
+}
+
+// NameOf is the name printed on the CRS, empty when the comment has no class
+// of the kind
+func (c *CommentClass) NameOf() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.Name
+}
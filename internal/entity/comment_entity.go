@@ -1,6 +1,12 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"fmt"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/google/uuid"
+)
 
 type CommentStatus string
 
@@ -9,6 +15,29 @@ const (
 	CommentStatusReject   CommentStatus = "REJECT"
 )
 
+// CommentStage is where a top level comment is in consolidation. Reviewers
+// write drafts, the consolidator of the document accepts (possibly after
+// editing), merges or discards them. Only official comments are shown to the
+// contractor and go into the CRS.
+type CommentStage string
+
+const (
+	CommentStageDraft     CommentStage = "DRAFT"
+	CommentStageOfficial  CommentStage = "OFFICIAL"
+	CommentStageDiscarded CommentStage = "DISCARDED"
+	CommentStageMerged    CommentStage = "MERGED"
+)
+
+// CommentVisibility decides who reads a comment. Internal comments and
+// replies stay within the company, they are never shown to the contractor
+// nor exported to the CRS.
+type CommentVisibility string
+
+const (
+	CommentVisibilityPublic   CommentVisibility = "PUBLIC"
+	CommentVisibilityInternal CommentVisibility = "INTERNAL"
+)
+
 type Comment struct {
 	ID uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 
@@ -18,10 +47,42 @@ type Comment struct {
 	IsCloseOutComment bool           `json:"is_close_out_comment" gorm:"default:false"`
 	AttachFileUrl     *string        `json:"attach_file_url" gorm:""`
 	Status            *CommentStatus `json:"comment_status" gorm:""`
+	// comments from before consolidation existed are official, replies too
+	Stage            CommentStage      `json:"stage" gorm:"default:OFFICIAL;not null;index"`
+	ConsolidatedByID *uuid.UUID        `json:"consolidated_by_id" gorm:"type:uuid"`
+	ConsolidatedAt   *time.Time        `json:"consolidated_at" gorm:""`
+	Visibility       CommentVisibility `json:"visibility" gorm:"default:PUBLIC;not null;index"`
+
+	// anchor on the reviewed document, page is 1-based and the box is
+	// stored as fractions (0..1) of the page width and height
+	Page           *int     `json:"page" gorm:"index"`
+	AnchorX        *float64 `json:"anchor_x" gorm:""`
+	AnchorY        *float64 `json:"anchor_y" gorm:""`
+	AnchorWidth    *float64 `json:"anchor_width" gorm:""`
+	AnchorHeight   *float64 `json:"anchor_height" gorm:""`
+	AnchorQuote    *string  `json:"anchor_quote" gorm:""`
+	AnchorRevision string   `json:"anchor_revision" gorm:""`
 
 	DisciplineListDocumentID uuid.UUID  `json:"discipline_list_document_id" gorm:"not null"`
 	UserID                   uuid.UUID  `json:"user_id" gorm:"not null"`
 	CommentReplyID           *uuid.UUID `json:"comment_reply_id" gorm:""`
+	// every reply points to the top level comment through CommentReplyID,
+	// nested replies also keep the reply they answer. Depth is 0 for top
+	// level comments and 1 for direct replies.
+	ParentReplyID *uuid.UUID `json:"parent_reply_id" gorm:"type:uuid;index"`
+	Depth         int        `json:"depth" gorm:"default:0;not null"`
+	// set when a substitute wrote the comment for a user who is away
+	OnBehalfOfID *uuid.UUID `json:"on_behalf_of_id" gorm:"type:uuid"`
+	// set on creation when the comment reads like an earlier one on the same
+	// discipline list document
+	DuplicateOfID   *uuid.UUID `json:"duplicate_of_id" gorm:"type:uuid"`
+	SimilarityScore *float64   `json:"similarity_score" gorm:""`
+	// set when a consolidator merged the comment into another one, it is kept
+	// for traceability but no longer listed on its own
+	MergedIntoID *uuid.UUID `json:"merged_into_id" gorm:"type:uuid;index"`
+	// classes configured for the package, replies are not classified
+	CategoryID *uuid.UUID `json:"category_id" gorm:"type:uuid;index"`
+	SeverityID *uuid.UUID `json:"severity_id" gorm:"type:uuid;index"`
 
 	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
 	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
@@ -29,6 +90,67 @@ type Comment struct {
 
 	DisciplineListDocument *DisciplineListDocument `json:"discipline_list_document,omitempty" gorm:"foreignKey:DisciplineListDocumentID"`
 	User                   *User                   `json:"user" gorm:"foreignKey:UserID"`
+	OnBehalfOf             *User                   `json:"on_behalf_of,omitempty" gorm:"foreignKey:OnBehalfOfID"`
+	ConsolidatedBy         *User                   `json:"consolidated_by,omitempty" gorm:"foreignKey:ConsolidatedByID"`
+	Category               *CommentClass           `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Severity               *CommentClass           `json:"severity,omitempty" gorm:"foreignKey:SeverityID"`
 	CommentReply           *Comment                `json:"comment_reply,omitempty" gorm:"foreignKey:CommentReplyID"`
 	CommentReplies         []Comment               `json:"comment_replies,omitempty" gorm:"foreignKey:CommentReplyID"`
+	MergedComments         []Comment               `json:"merged_comments,omitempty" gorm:"foreignKey:MergedIntoID"`
+	Mentions               []CommentMention        `json:"mentions,omitempty" gorm:"foreignKey:CommentID"`
+	Reactions              []CommentReaction       `json:"reactions,omitempty" gorm:"foreignKey:CommentID"`
+}
+
+// InOfficialSet tells whether the comment is a row of the consolidated CRS
+func (c *Comment) InOfficialSet() bool {
+	return c.CommentReplyID == nil && c.MergedIntoID == nil && c.Stage == CommentStageOfficial && !c.IsInternal()
+}
+
+func (c *Comment) IsInternal() bool {
+	return c.Visibility == CommentVisibilityInternal
+}
+
+// PageLabel is the value written to the "Page" column of the CRS, comments
+// created before anchors existed fall back to the free-text section
+func (c *Comment) PageLabel() string {
+	if c.Page != nil {
+		return fmt.Sprintf("Page %d", *c.Page)
+	}
+
+	return c.Section
+}
+
+func (c *Comment) ToAnchor() *dto.CommentAnchor {
+	if c.Page == nil {
+		return nil
+	}
+
+	return &dto.CommentAnchor{
+		Page:     *c.Page,
+		X:        c.AnchorX,
+		Y:        c.AnchorY,
+		Width:    c.AnchorWidth,
+		Height:   c.AnchorHeight,
+		Quote:    c.AnchorQuote,
+		Revision: c.AnchorRevision,
+	}
+}
+
+func (c *Comment) SetAnchor(anchor *dto.CommentAnchor) {
+	if anchor == nil {
+		c.Page = nil
+		c.AnchorX, c.AnchorY, c.AnchorWidth, c.AnchorHeight = nil, nil, nil, nil
+		c.AnchorQuote = nil
+		c.AnchorRevision = ""
+		return
+	}
+
+	page := anchor.Page
+	c.Page = &page
+	c.AnchorX = anchor.X
+	c.AnchorY = anchor.Y
+	c.AnchorWidth = anchor.Width
+	c.AnchorHeight = anchor.Height
+	c.AnchorQuote = anchor.Quote
+	c.AnchorRevision = anchor.Revision
 }
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+type CommentReactionType string
+
+const (
+	CommentReactionSeen  CommentReactionType = "SEEN"
+	CommentReactionAgree CommentReactionType = "AGREE"
+)
+
+// CommentReactionTypes is the order reactions are listed in
+var CommentReactionTypes = []CommentReactionType{CommentReactionSeen, CommentReactionAgree}
+
+// CommentMention is a user called out with @ in a comment or reply, they
+// are notified when mentioned
+type CommentMention struct {
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+
+	CommentID uuid.UUID `json:"comment_id" gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Timestamp
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// CommentReaction is a light acknowledgement of a comment, a user gives each
+// type at most once
+type CommentReaction struct {
+	ID   uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Type CommentReactionType `json:"type" gorm:"not null"`
+
+	CommentID uuid.UUID `json:"comment_id" gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Timestamp
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// CommentRevision keeps the text of a comment as it was before an edit
+type CommentRevision struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Section  string    `json:"section" gorm:"not null"`
+	Comment  string    `json:"comment" gorm:"not null"`
+	Baseline string    `json:"baseline" gorm:"not null"`
+
+	CommentID uuid.UUID `json:"comment_id" gorm:"type:uuid;not null;index"`
+	EditedBy  uuid.UUID `json:"edited_by" gorm:"type:uuid;not null"`
+	Timestamp
+
+	Editor *User `json:"editor,omitempty" gorm:"foreignKey:EditedBy"`
+}
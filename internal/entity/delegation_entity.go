@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Delegation lets a substitute act for a user between StartAt and EndAt.
+// While it is active the substitute can work on the discipline groups and
+// list documents the user consolidates, and receives their notifications.
+type Delegation struct {
+	ID      uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	StartAt time.Time `json:"start_at" gorm:"not null"`
+	EndAt   time.Time `json:"end_at" gorm:"not null"`
+	Reason  *string   `json:"reason" gorm:""`
+
+	UserID       uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	SubstituteID uuid.UUID `json:"substitute_id" gorm:"type:uuid;not null;index"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	User       *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Substitute *User `json:"substitute,omitempty" gorm:"foreignKey:SubstituteID"`
+}
+
+func (d Delegation) IsActive(at time.Time) bool {
+	return !at.Before(d.StartAt) && at.Before(d.EndAt)
+}
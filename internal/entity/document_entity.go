@@ -13,6 +13,16 @@ const (
 	StatusDocumentIFU StatusDocument = "IFU"
 )
 
+// Purpose is what the document is issued for, it picks the SLA rule of the
+// review
+func (s StatusDocument) Purpose() TransmittalPurpose {
+	if s == StatusDocumentIFU {
+		return TransmittalPurposeUse
+	}
+
+	return TransmittalPurposeReview
+}
+
 type Document struct {
 	ID                       uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 	DocumentUrl              *string        `json:"document_url" gorm:""`
@@ -22,11 +32,14 @@ type Document struct {
 	CompanyDocumentNumber    string         `json:"company_document_number" gorm:""`
 	ContractorDocumentNumber string         `json:"contractor_document_number" gorm:""`
 	DocumentTitle            string         `json:"document_title" gorm:"not null"`
+	Revision                 string         `json:"revision" gorm:""`
+	PageCount                *int           `json:"page_count" gorm:""`
 	Discipline               string         `json:"discipline" gorm:""`
 	SubDiscipline            *string        `json:"sub_discipline" gorm:""`
 	DocumentType             string         `json:"document_type" gorm:""`
 	DocumentCategory         string         `json:"document_category" gorm:""`
 	DueDate                  *time.Time     `json:"due_date" gorm:""`
+	IssuedAt                 *time.Time     `json:"issued_at" gorm:""`
 	Status                   StatusDocument `json:"status" gorm:"not null"`
 
 	ContractorID uuid.UUID `json:"contractor_id" gorm:"not null"`
@@ -39,4 +52,28 @@ type Document struct {
 	Contractor              *User                    `json:"contractor,omitempty" gorm:"foreignKey:ContractorID"`
 	Package                 *Package                 `json:"package,omitempty" gorm:"foreignKey:PackageID"`
 	DisciplineListDocuments []DisciplineListDocument `json:"discipline_list_documents,omitempty" gorm:"foreignKey:DocumentID"`
+	ReviewOutcomes          []ReviewOutcome          `json:"review_outcomes,omitempty" gorm:"foreignKey:DocumentID"`
+}
+
+// OnCurrentRevision tells whether the comment was written on the revision
+// under review. An anchor names its revision, other comments count when they
+// were written after the revision was issued.
+func (d *Document) OnCurrentRevision(c *Comment) bool {
+	if c.AnchorRevision != "" {
+		return c.AnchorRevision == d.Revision
+	}
+
+	return d.IssuedAt == nil || !c.CreatedAt.Before(*d.IssuedAt)
+}
+
+// CurrentReviewOutcome is the outcome of the revision under review, nil until
+// it is set or when the review outcomes are not loaded
+func (d *Document) CurrentReviewOutcome() *ReviewOutcome {
+	for i := range d.ReviewOutcomes {
+		if d.ReviewOutcomes[i].Revision == d.Revision {
+			return &d.ReviewOutcomes[i]
+		}
+	}
+
+	return nil
 }
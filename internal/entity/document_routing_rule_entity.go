@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DocumentRoutingRule adds imported documents of a discipline, and optionally
+// of one document type, to a discipline group of the same package
+type DocumentRoutingRule struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Discipline   string    `json:"discipline" gorm:"not null"`
+	DocumentType *string   `json:"document_type" gorm:""` // null matches every type
+
+	PackageID         uuid.UUID `json:"package_id" gorm:"type:uuid;not null;index"`
+	DisciplineGroupID uuid.UUID `json:"discipline_group_id" gorm:"type:uuid;not null"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	Package         *Package         `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+	DisciplineGroup *DisciplineGroup `json:"discipline_group,omitempty" gorm:"foreignKey:DisciplineGroupID"`
+}
+
+// Matches compares discipline and document type ignoring case
+func (r DocumentRoutingRule) Matches(document Document) bool {
+	if !strings.EqualFold(strings.TrimSpace(document.Discipline), r.Discipline) {
+		return false
+	}
+
+	return r.DocumentType == nil || strings.EqualFold(strings.TrimSpace(document.DocumentType), *r.DocumentType)
+}
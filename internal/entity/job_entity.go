@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	JobType   string
+	JobStatus string
+)
+
+const (
+	JobTypePackagePDF           JobType = "PACKAGE_PDF"
+	JobTypePackageExcel         JobType = "PACKAGE_EXCEL"
+	JobTypeDisciplineGroupPDF   JobType = "DISCIPLINE_GROUP_PDF"
+	JobTypeDisciplineGroupExcel JobType = "DISCIPLINE_GROUP_EXCEL"
+
+	JobStatusPending   JobStatus = "PENDING"
+	JobStatusRunning   JobStatus = "RUNNING"
+	JobStatusSucceeded JobStatus = "SUCCEEDED"
+	JobStatusFailed    JobStatus = "FAILED"
+)
+
+type Job struct {
+	ID       uuid.UUID         `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Type     JobType           `json:"type" gorm:"not null;index"`
+	Status   JobStatus         `json:"status" gorm:"not null;default:PENDING;index"`
+	Progress int               `json:"progress" gorm:"not null;default:0"`
+	Params   map[string]string `json:"params" gorm:"type:jsonb;serializer:json"`
+	// identical requests share the same key while a job is pending or running
+	DedupKey string  `json:"dedup_key" gorm:"not null;index"`
+	Error    *string `json:"error" gorm:""`
+
+	ArtifactPath        *string `json:"artifact_path" gorm:""`
+	ArtifactName        *string `json:"artifact_name" gorm:""`
+	ArtifactContentType *string `json:"artifact_content_type" gorm:""`
+
+	StartedAt  *time.Time `json:"started_at" gorm:"type:timestamp without time zone"`
+	FinishedAt *time.Time `json:"finished_at" gorm:"type:timestamp without time zone"`
+	ExpiresAt  *time.Time `json:"expires_at" gorm:"type:timestamp without time zone;index"`
+
+	PackageID *uuid.UUID `json:"package_id" gorm:"type:uuid"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	Timestamp
+
+	Package *Package `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+	User    *User    `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (j Job) IsActive() bool {
+	return j.Status == JobStatusPending || j.Status == JobStatusRunning
+}
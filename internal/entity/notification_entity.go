@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	NotificationDocumentAssigned    NotificationType = "DOCUMENT_ASSIGNED"
+	NotificationCommentReplied      NotificationType = "COMMENT_REPLIED"
+	NotificationCommentMerged       NotificationType = "COMMENT_MERGED"
+	NotificationCommentConsolidated NotificationType = "COMMENT_CONSOLIDATED"
+	NotificationCommentMentioned    NotificationType = "COMMENT_MENTIONED"
+)
+
+type Notification struct {
+	ID      uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Type    NotificationType `json:"type" gorm:"not null"`
+	Title   string           `json:"title" gorm:"not null"`
+	Message string           `json:"message" gorm:"not null"`
+	ReadAt  *time.Time       `json:"read_at" gorm:""`
+
+	// recipient, a substitute gets a copy with OnBehalfOfID set to the
+	// user they are standing in for
+	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	OnBehalfOfID *uuid.UUID `json:"on_behalf_of_id" gorm:"type:uuid"`
+
+	DisciplineListDocumentID *uuid.UUID `json:"discipline_list_document_id" gorm:"type:uuid"`
+	CommentID                *uuid.UUID `json:"comment_id" gorm:"type:uuid"`
+
+	Timestamp
+
+	User       *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	OnBehalfOf *User `json:"on_behalf_of,omitempty" gorm:"foreignKey:OnBehalfOfID"`
+}
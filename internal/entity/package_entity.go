@@ -1,7 +1,10 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/CRS-Project/crs-backend/internal/dto"
+	"github.com/CRS-Project/crs-backend/internal/utils"
 	"github.com/google/uuid"
 )
 
@@ -9,6 +12,11 @@ type Package struct {
 	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 	Name        string    `json:"name" gorm:"not null;"`
 	Description string    `json:"description" gorm:""`
+	// working days the company has to review a document when none of the
+	// SLA rules of the package applies
+	ReviewPeriodDays int `json:"review_period_days" gorm:"default:14;not null"`
+	// IANA name, due dates and working days are counted in it
+	TimeZone string `json:"time_zone" gorm:"default:Asia/Jakarta;not null"`
 
 	DisciplineGroups []DisciplineGroup `json:"discipline_groups,omitempty" gorm:"foreignKey:PackageID"`
 
@@ -19,8 +27,15 @@ type Package struct {
 
 func (p *Package) ToInfo() dto.PackageInfo {
 	return dto.PackageInfo{
-		ID:          p.ID.String(),
-		Name:        p.Name,
-		Description: p.Description,
+		ID:               p.ID.String(),
+		Name:             p.Name,
+		Description:      p.Description,
+		ReviewPeriodDays: p.ReviewPeriodDays,
+		TimeZone:         p.TimeZone,
 	}
 }
+
+// Location is the time zone of the package
+func (p *Package) Location() *time.Location {
+	return utils.LoadLocation(p.TimeZone)
+}
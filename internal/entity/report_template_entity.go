@@ -0,0 +1,42 @@
+package entity
+
+import (
+	mypdf "github.com/CRS-Project/crs-backend/internal/pkg/pdf"
+	"github.com/google/uuid"
+)
+
+// ReportTemplate is the CRS layout of a package. A package without one uses
+// mypdf.DefaultTemplate.
+type ReportTemplate struct {
+	ID              uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	LogoUrl         *string             `json:"logo_url" gorm:""`
+	Title           string              `json:"title" gorm:"not null"`
+	PaperSize       string              `json:"paper_size" gorm:"not null;default:A4"`
+	Orientation     string              `json:"orientation" gorm:"not null;default:L"`
+	HeaderFields    []mypdf.HeaderField `json:"header_fields" gorm:"type:jsonb;serializer:json"`
+	Columns         []mypdf.Column      `json:"columns" gorm:"type:jsonb;serializer:json"`
+	FooterNote      string              `json:"footer_note" gorm:""`
+	SignatureBlocks []string            `json:"signature_blocks" gorm:"type:jsonb;serializer:json"`
+
+	PackageID uuid.UUID `json:"package_id" gorm:"type:uuid;not null"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	Package *Package `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+}
+
+// ToTemplate builds the renderer template, logoPath is the local file of LogoUrl
+func (t ReportTemplate) ToTemplate(logoPath string) mypdf.Template {
+	return mypdf.Template{
+		LogoPath:        logoPath,
+		Title:           t.Title,
+		PaperSize:       t.PaperSize,
+		Orientation:     t.Orientation,
+		HeaderFields:    t.HeaderFields,
+		Columns:         t.Columns,
+		FooterNote:      t.FooterNote,
+		SignatureBlocks: t.SignatureBlocks,
+	}
+}
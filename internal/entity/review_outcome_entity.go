@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewOutcomeCode is the code a document revision is returned to the
+// contractor with at the end of a review round
+type ReviewOutcomeCode string
+
+const (
+	ReviewOutcomeApproved             ReviewOutcomeCode = "APPROVED"
+	ReviewOutcomeApprovedWithComments ReviewOutcomeCode = "APPROVED_WITH_COMMENTS"
+	ReviewOutcomeReviseAndResubmit    ReviewOutcomeCode = "REVISE_AND_RESUBMIT"
+	ReviewOutcomeRejected             ReviewOutcomeCode = "REJECTED"
+)
+
+// ReviewOutcomeCodes lists the codes from 1 to 4, the later the worse
+var ReviewOutcomeCodes = []ReviewOutcomeCode{
+	ReviewOutcomeApproved,
+	ReviewOutcomeApprovedWithComments,
+	ReviewOutcomeReviseAndResubmit,
+	ReviewOutcomeRejected,
+}
+
+var reviewOutcomeNames = map[ReviewOutcomeCode]string{
+	ReviewOutcomeApproved:             "Approved",
+	ReviewOutcomeApprovedWithComments: "Approved with comments",
+	ReviewOutcomeReviseAndResubmit:    "Revise and resubmit",
+	ReviewOutcomeRejected:             "Rejected",
+}
+
+// Number is the code printed on the return, 0 for an unknown code
+func (c ReviewOutcomeCode) Number() int {
+	for i, code := range ReviewOutcomeCodes {
+		if code == c {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// Label reads like "3 - Revise and resubmit", a plain hyphen since the core
+// fonts of the pdf can't print a dash
+func (c ReviewOutcomeCode) Label() string {
+	if c.Number() == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d - %s", c.Number(), reviewOutcomeNames[c])
+}
+
+// ReviewOutcome is the code a revision of a document was returned with, set
+// by a consolidator of the document or a super admin. Each revision has at
+// most one.
+type ReviewOutcome struct {
+	ID       uuid.UUID         `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Revision string            `json:"revision" gorm:"not null"`
+	Code     ReviewOutcomeCode `json:"code" gorm:"not null"`
+	// what the rules suggested when the code was set
+	SuggestedCode ReviewOutcomeCode `json:"suggested_code" gorm:""`
+	Remarks       *string           `json:"remarks" gorm:""`
+	SetAt         time.Time         `json:"set_at" gorm:"not null"`
+
+	DocumentID uuid.UUID `json:"document_id" gorm:"type:uuid;not null;index"`
+	SetByID    uuid.UUID `json:"set_by_id" gorm:"type:uuid;not null"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	Document *Document `json:"document,omitempty" gorm:"foreignKey:DocumentID"`
+	SetBy    *User     `json:"set_by,omitempty" gorm:"foreignKey:SetByID"`
+}
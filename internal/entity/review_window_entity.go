@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewWindow is a period in which reviewers create comments. A window
+// without discipline group covers the whole package. Once a discipline group
+// has windows, its own or the package's, comments are only created while one
+// of them is open.
+type ReviewWindow struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name     string    `json:"name" gorm:"not null"`
+	OpensAt  time.Time `json:"opens_at" gorm:"not null"`
+	ClosesAt time.Time `json:"closes_at" gorm:"not null"`
+
+	PackageID         uuid.UUID  `json:"package_id" gorm:"type:uuid;not null;index"`
+	DisciplineGroupID *uuid.UUID `json:"discipline_group_id" gorm:"type:uuid;index"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	Package         *Package         `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+	DisciplineGroup *DisciplineGroup `json:"discipline_group,omitempty" gorm:"foreignKey:DisciplineGroupID"`
+}
+
+// IsOpen tells whether the window is open at the time, it opens at OpensAt
+// and is closed from ClosesAt on
+func (w *ReviewWindow) IsOpen(at time.Time) bool {
+	return !at.Before(w.OpensAt) && at.Before(w.ClosesAt)
+}
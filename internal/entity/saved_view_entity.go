@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type SavedViewResource string
+
+const (
+	SavedViewDocument               SavedViewResource = "DOCUMENT"
+	SavedViewComment                SavedViewResource = "COMMENT"
+	SavedViewDisciplineListDocument SavedViewResource = "DISCIPLINE_LIST_DOCUMENT"
+)
+
+// SavedView is a named set of list parameters (the same ones meta.Meta reads)
+// and the columns to show. It belongs to its owner, can be shared with the
+// package, and a super admin can publish one as the package default of a
+// resource, used when a listing is opened without parameters.
+type SavedView struct {
+	ID       uuid.UUID         `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name     string            `json:"name" gorm:"not null"`
+	Resource SavedViewResource `json:"resource" gorm:"not null;index"`
+
+	Take     int      `json:"take" gorm:""`
+	Sort     string   `json:"sort" gorm:""`
+	SortBy   string   `json:"sort_by" gorm:""`
+	Filter   string   `json:"filter" gorm:""`
+	FilterBy string   `json:"filter_by" gorm:""`
+	Query    string   `json:"q" gorm:""`
+	Columns  []string `json:"columns" gorm:"type:jsonb;serializer:json"`
+
+	IsShared  bool `json:"is_shared" gorm:"default:false"`
+	IsDefault bool `json:"is_default" gorm:"default:false"`
+
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	PackageID *uuid.UUID `json:"package_id" gorm:"type:uuid;index"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	User    *User    `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Package *Package `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+}
+
+// Params returns the view as list query params, unset ones are left out
+func (v SavedView) Params() map[string]string {
+	params := map[string]string{
+		"sort":      v.Sort,
+		"sort_by":   v.SortBy,
+		"filter":    v.Filter,
+		"filter_by": v.FilterBy,
+		"q":         v.Query,
+	}
+
+	if v.Take > 0 {
+		params["take"] = strconv.Itoa(v.Take)
+	}
+
+	for k, value := range params {
+		if value == "" {
+			delete(params, k)
+		}
+	}
+
+	return params
+}
+
+// VisibleTo tells whether the user can apply the view
+func (v SavedView) VisibleTo(user User) bool {
+	if v.UserID == user.ID || user.PackageID == nil {
+		return true
+	}
+
+	return (v.IsShared || v.IsDefault) && v.PackageID != nil && *v.PackageID == *user.PackageID
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	SignOffScope      string
+	SignOffStatus     string
+	SignOffStepStatus string
+)
+
+const (
+	SignOffScopePackage         SignOffScope = "PACKAGE"
+	SignOffScopeDisciplineGroup SignOffScope = "DISCIPLINE_GROUP"
+
+	SignOffStatusInProgress SignOffStatus = "IN_PROGRESS"
+	SignOffStatusCompleted  SignOffStatus = "COMPLETED"
+	SignOffStatusRejected   SignOffStatus = "REJECTED"
+
+	SignOffStepStatusPending  SignOffStepStatus = "PENDING"
+	SignOffStepStatusApproved SignOffStepStatus = "APPROVED"
+	SignOffStepStatusRejected SignOffStepStatus = "REJECTED"
+)
+
+// SignOff is the approval of an exported CRS. Steps are approved in order of
+// their sequence and every approval must see the same content hash.
+type SignOff struct {
+	ID     uuid.UUID     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Scope  SignOffScope  `json:"scope" gorm:"not null"`
+	Status SignOffStatus `json:"status" gorm:"not null;default:IN_PROGRESS"`
+	// hash of the CRS data when the sign-off started
+	ContentHash string `json:"content_hash" gorm:"not null"`
+	// sha256 of the signed pdf, set once every step is approved
+	FileHash     *string    `json:"file_hash" gorm:"index"`
+	ArtifactPath *string    `json:"artifact_path" gorm:""`
+	CompletedAt  *time.Time `json:"completed_at" gorm:"type:timestamp without time zone"`
+
+	PackageID         uuid.UUID  `json:"package_id" gorm:"type:uuid;not null"`
+	DisciplineGroupID *uuid.UUID `json:"discipline_group_id" gorm:"type:uuid"`
+	UserID            uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	Package         *Package         `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+	DisciplineGroup *DisciplineGroup `json:"discipline_group,omitempty" gorm:"foreignKey:DisciplineGroupID"`
+	User            *User            `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Steps           []SignOffStep    `json:"steps,omitempty" gorm:"foreignKey:SignOffID"`
+}
+
+type SignOffStep struct {
+	ID          uuid.UUID         `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Sequence    int               `json:"sequence" gorm:"not null"`
+	Role        string            `json:"role" gorm:"not null"`
+	Status      SignOffStepStatus `json:"status" gorm:"not null;default:PENDING"`
+	ContentHash *string           `json:"content_hash" gorm:""`
+	Note        *string           `json:"note" gorm:""`
+	ActedAt     *time.Time        `json:"acted_at" gorm:"type:timestamp without time zone"`
+
+	SignOffID uuid.UUID `json:"sign_off_id" gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Timestamp
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// CurrentStep returns the first step that is not approved yet
+func (s SignOff) CurrentStep() *SignOffStep {
+	for i := range s.Steps {
+		if s.Steps[i].Status != SignOffStepStatusApproved {
+			return &s.Steps[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SLARule is the number of working days the company has to review the
+// documents of a package issued for a purpose. Resubmission rules apply to
+// the revisions that come back after a review, the other rules to first
+// issues. A rule without purpose applies to the purposes that have no rule
+// of their own.
+type SLARule struct {
+	ID           uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Purpose      *TransmittalPurpose `json:"purpose" gorm:""`
+	Resubmission bool                `json:"resubmission" gorm:"default:false;not null"`
+	WorkingDays  int                 `json:"working_days" gorm:"not null"`
+
+	PackageID uuid.UUID `json:"package_id" gorm:"type:uuid;not null;index"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	Package *Package `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+}
+
+// Holiday is a day off in the calendar of a package, it is skipped when the
+// working days of a review are counted
+type Holiday struct {
+	ID   uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Date time.Time `json:"date" gorm:"type:date;not null"`
+	Name string    `json:"name" gorm:"not null"`
+
+	PackageID uuid.UUID `json:"package_id" gorm:"type:uuid;not null;index"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	Package *Package `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransmittalDirection tells who sent the transmittal. Contractors submit
+// documents in incoming transmittals, the company answers with outgoing
+// (return) transmittals.
+type TransmittalDirection string
+
+const (
+	TransmittalIncoming TransmittalDirection = "INCOMING"
+	TransmittalOutgoing TransmittalDirection = "OUTGOING"
+)
+
+// TransmittalPurpose is the purpose of issue of the documents
+type TransmittalPurpose string
+
+const (
+	TransmittalPurposeReview       TransmittalPurpose = "IFR"
+	TransmittalPurposeApproval     TransmittalPurpose = "IFA"
+	TransmittalPurposeInformation  TransmittalPurpose = "IFI"
+	TransmittalPurposeConstruction TransmittalPurpose = "IFC"
+	TransmittalPurposeUse          TransmittalPurpose = "IFU"
+)
+
+// Number formats the package-level sequence of the direction, e.g.
+// TR-IN-0007
+func (d TransmittalDirection) Number(sequence int) string {
+	prefix := "TR-IN"
+	if d == TransmittalOutgoing {
+		prefix = "TR-OUT"
+	}
+
+	return fmt.Sprintf("%s-%04d", prefix, sequence)
+}
+
+type Transmittal struct {
+	ID        uuid.UUID            `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Number    string               `json:"number" gorm:"not null"`
+	Sequence  int                  `json:"sequence" gorm:"not null"`
+	Direction TransmittalDirection `json:"direction" gorm:"not null;index"`
+	Purpose   TransmittalPurpose   `json:"purpose" gorm:"not null"`
+	// number given by the sender, contractors keep their own register
+	ReferenceNumber *string   `json:"reference_number" gorm:""`
+	Subject         string    `json:"subject" gorm:"not null"`
+	Remarks         *string   `json:"remarks" gorm:""`
+	TransmittalDate time.Time `json:"transmittal_date" gorm:"not null"`
+	// when the company received an incoming transmittal, the review period
+	// of its documents starts here
+	ReceivedAt *time.Time `json:"received_at" gorm:""`
+
+	PackageID uuid.UUID `json:"package_id" gorm:"type:uuid;not null;index"`
+	// the incoming transmittal an outgoing one answers
+	ReplyToID *uuid.UUID `json:"reply_to_id" gorm:"type:uuid"`
+
+	DeletedBy uuid.UUID `json:"deleted_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	Timestamp
+
+	Package   *Package              `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+	ReplyTo   *Transmittal          `json:"reply_to,omitempty" gorm:"foreignKey:ReplyToID"`
+	Documents []TransmittalDocument `json:"documents,omitempty" gorm:"foreignKey:TransmittalID"`
+}
+
+// TransmittalDocument is a document revision listed on a transmittal. The
+// review outcome is what an outgoing transmittal returned the revision with.
+type TransmittalDocument struct {
+	ID            uuid.UUID          `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Revision      string             `json:"revision" gorm:""`
+	ReviewOutcome *ReviewOutcomeCode `json:"review_outcome" gorm:""`
+
+	TransmittalID uuid.UUID `json:"transmittal_id" gorm:"type:uuid;not null;index"`
+	DocumentID    uuid.UUID `json:"document_id" gorm:"type:uuid;not null;index"`
+	Timestamp
+
+	Transmittal *Transmittal `json:"transmittal,omitempty" gorm:"foreignKey:TransmittalID"`
+	Document    *Document    `json:"document,omitempty" gorm:"foreignKey:DocumentID"`
+}
@@ -14,7 +14,6 @@ import (
 const (
 	MESSAGE_FAILED_VERIFY_TOKEN = "failed to verify token"
 	MESSAGE_USER_NOT_AUTHORIZED = "user not authorized"
-	MESSAGE_API_IS_LOCKED       = "api is now locked"
 )
 
 var (
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	MESSAGE_FAILED_CHECK_REVIEW_WINDOW = "failed check review window"
+	MESSAGE_REVIEW_WINDOW_CLOSED       = "review window is closed"
+)
+
+// ReviewWindow lets the request through only while a review window of the
+// discipline group of the discipline_list_document_id param is open, the
+// windows of the group and those of its package apply. The
+// discipline_group_id param has to be that group. A discipline group without any
+// window is always open and super admins are never held back. Writing,
+// replying to, editing and deleting comments all go through it, so a closed
+// window freezes the comments.
+//
+// It must run after Authenticate.
+func (m Middleware) ReviewWindow() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString("role") == string(entity.RoleSuperAdmin) {
+			ctx.Next()
+			return
+		}
+
+		var disciplineListDocument entity.DisciplineListDocument
+		if err := m.db.WithContext(ctx.Request.Context()).
+			Preload("DisciplineGroup.Package").
+			Where("id = ?", ctx.Param("discipline_list_document_id")).
+			First(&disciplineListDocument).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = myerror.New("discipline list document not found", http.StatusNotFound)
+			}
+			response.NewFailed(MESSAGE_FAILED_CHECK_REVIEW_WINDOW, err).SendWithAbort(ctx)
+			return
+		}
+
+		disciplineGroup := disciplineListDocument.DisciplineGroup
+		if disciplineGroup == nil || disciplineGroup.ID.String() != ctx.Param("discipline_group_id") {
+			response.NewFailed(MESSAGE_FAILED_CHECK_REVIEW_WINDOW,
+				myerror.New("discipline list document is not in this discipline group", http.StatusBadRequest)).SendWithAbort(ctx)
+			return
+		}
+
+		var reviewWindows []entity.ReviewWindow
+		if err := m.db.WithContext(ctx.Request.Context()).
+			Where("package_id = ? AND (discipline_group_id IS NULL OR discipline_group_id = ?)", disciplineGroup.PackageID, disciplineGroup.ID).
+			Order("opens_at asc").
+			Find(&reviewWindows).Error; err != nil {
+			response.NewFailed(MESSAGE_FAILED_CHECK_REVIEW_WINDOW, err).SendWithAbort(ctx)
+			return
+		}
+
+		if len(reviewWindows) == 0 {
+			ctx.Next()
+			return
+		}
+
+		now := time.Now()
+		var next, last *entity.ReviewWindow
+		for i := range reviewWindows {
+			if reviewWindows[i].IsOpen(now) {
+				ctx.Next()
+				return
+			}
+
+			if reviewWindows[i].OpensAt.After(now) {
+				if next == nil {
+					next = &reviewWindows[i]
+				}
+			} else if last == nil || reviewWindows[i].ClosesAt.After(last.ClosesAt) {
+				last = &reviewWindows[i]
+			}
+		}
+
+		// times are shown in the time zone of the package
+		location := utils.LoadLocation("")
+		if disciplineGroup.Package != nil {
+			location = disciplineGroup.Package.Location()
+		}
+		msg := fmt.Sprintf("comments on %s can't be changed now", disciplineGroup.ReviewFocus)
+		if next != nil {
+			msg = fmt.Sprintf("%s, the next review window %s opens at %s", msg, next.Name, next.OpensAt.In(location).Format("15.04 MST • 02 Jan 2006"))
+		} else if last != nil {
+			msg = fmt.Sprintf("%s, the review window %s closed at %s", msg, last.Name, last.ClosesAt.In(location).Format("15.04 MST • 02 Jan 2006"))
+		}
+
+		response.NewFailed(MESSAGE_REVIEW_WINDOW_CLOSED, myerror.New(msg, http.StatusForbidden)).SendWithAbort(ctx)
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CRS-Project/crs-backend/internal/api/repository"
+	"github.com/CRS-Project/crs-backend/internal/entity"
+	myerror "github.com/CRS-Project/crs-backend/internal/pkg/error"
+	"github.com/CRS-Project/crs-backend/internal/pkg/response"
+	"github.com/CRS-Project/crs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const MESSAGE_FAILED_APPLY_VIEW = "failed apply view"
+
+// list params a saved view sets, see entity.SavedView.Params
+var viewParams = []string{"take", "sort", "sort_by", "filter", "filter_by", "q"}
+
+// ApplyView fills the list params of the request from the saved view given
+// by view_id, params sent with the request win over the view's. Without a
+// view_id or any list param the package default view of the resource is
+// used when there is one. The applied view is echoed in X-View-Id.
+//
+// It must run after Authenticate.
+func (m Middleware) ApplyView(resource entity.SavedViewResource) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query := ctx.Request.URL.Query()
+		viewId := query.Get("view_id")
+
+		if viewId == "" {
+			for _, param := range viewParams {
+				if query.Has(param) {
+					ctx.Next()
+					return
+				}
+			}
+		}
+
+		userId, err := utils.GetUserIdFromCtx(ctx)
+		if err != nil {
+			response.NewFailed(MESSAGE_FAILED_APPLY_VIEW, err).SendWithAbort(ctx)
+			return
+		}
+
+		var user entity.User
+		if err := m.db.WithContext(ctx.Request.Context()).Where("id = ?", userId).First(&user).Error; err != nil {
+			response.NewFailed(MESSAGE_FAILED_APPLY_VIEW, err).SendWithAbort(ctx)
+			return
+		}
+
+		var view entity.SavedView
+		if viewId != "" {
+			if err := m.db.WithContext(ctx.Request.Context()).
+				Where("id = ? AND resource = ?", viewId, resource).
+				First(&view).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					err = myerror.New("view not found", http.StatusNotFound)
+				}
+				response.NewFailed(MESSAGE_FAILED_APPLY_VIEW, err).SendWithAbort(ctx)
+				return
+			}
+
+			if !view.VisibleTo(user) {
+				response.NewFailed(MESSAGE_FAILED_APPLY_VIEW, myerror.New("you don't have permission for this view", http.StatusUnauthorized)).SendWithAbort(ctx)
+				return
+			}
+		} else {
+			if user.PackageID == nil {
+				ctx.Next()
+				return
+			}
+
+			view, err = repository.NewSavedView(m.db).GetDefault(ctx.Request.Context(), nil, user.PackageID.String(), resource)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				ctx.Next()
+				return
+			}
+			if err != nil {
+				response.NewFailed(MESSAGE_FAILED_APPLY_VIEW, err).SendWithAbort(ctx)
+				return
+			}
+		}
+
+		for param, value := range view.Params() {
+			if !query.Has(param) {
+				query.Set(param, value)
+			}
+		}
+
+		ctx.Request.URL.RawQuery = query.Encode()
+		ctx.Header("X-View-Id", view.ID.String())
+		ctx.Next()
+	}
+}
@@ -1,6 +1,11 @@
 package meta
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/CRS-Project/crs-backend/internal/utils"
@@ -17,18 +22,52 @@ type Meta struct {
 	SortBy    string `json:"sort_by"`
 	Filter    string `json:"filter,omitempty"`
 	FilterBy  string `json:"filter_by,omitempty"`
+	// rich filter expression, see ParseQuery
+	Query string `json:"q,omitempty"`
+
+	// cursor pagination, enabled by sending the cursor query param (empty
+	// for the first page). Pages are then read by a stable sort key instead
+	// of an offset and the totals are not counted.
+	UseCursor  bool   `json:"-"`
+	Cursor     string `json:"-"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Cursor points at the row a page starts after. It is bound to the sort it
+// was made for, Prev reads the rows before it instead.
+type Cursor struct {
+	SortBy string `json:"s"`
+	Sort   string `json:"o"`
+	Value  any    `json:"v"`
+	ID     any    `json:"i"`
+	Prev   bool   `json:"p,omitempty"`
+}
+
+const defaultMaxTake = 100
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// MaxTake is the biggest page a request can ask for, set by
+// PAGINATION_MAX_TAKE
+func MaxTake() int {
+	if v, err := strconv.Atoi(os.Getenv("PAGINATION_MAX_TAKE")); err == nil && v > 0 {
+		return v
+	}
+
+	return defaultMaxTake
 }
 
 // New creates and initializes a Meta object with default pagination settings.
 // Default values are:
-// - Take: 10 (number of items per page)
+// - Take: MaxTake() (number of items per page, also the upper limit)
 // - Page: 0 (starting page)
 // - Sort: "asc" (ascending order)
-// - SortBy: "created_by" (column used for sorting)
+// - SortBy: "id" (column used for sorting)
 // Additional options can be applied to customize the Meta object.
 func New(ctx *gin.Context) Meta {
 	meta := Meta{
-		Take:   10000000,
+		Take:   MaxTake(),
 		Page:   0,
 		Sort:   "asc",
 		SortBy: "id",
@@ -65,12 +104,13 @@ func New(ctx *gin.Context) Meta {
 		meta.FilterBy = filterby
 	}
 
+	meta.fromContext(ctx)
 	return meta
 }
 
 func NewWithDefault(ctx *gin.Context, dtake int, dpage int, dsort string, dsortBy string) Meta {
 	if dtake == 0 {
-		dtake = 10000000
+		dtake = MaxTake()
 	}
 
 	if dpage == 0 {
@@ -123,9 +163,61 @@ func NewWithDefault(ctx *gin.Context, dtake int, dpage int, dsort string, dsortB
 		meta.FilterBy = filterby
 	}
 
+	meta.fromContext(ctx)
 	return meta
 }
 
+// fromContext reads the query expression and the cursor and caps the page
+// size
+func (m *Meta) fromContext(ctx *gin.Context) {
+	if q := ctx.Query("q"); q != "" {
+		m.Query = q
+	}
+
+	if cursor, ok := ctx.GetQuery("cursor"); ok {
+		m.UseCursor = true
+		m.Cursor = cursor
+	}
+
+	if maxTake := MaxTake(); m.Take > maxTake {
+		m.Take = maxTake
+	}
+}
+
+// EncodeCursor makes the opaque cursor sent to the client
+func EncodeCursor(cursor Cursor) string {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor returns the cursor of the request, nil for the first page.
+// A cursor made for another sort is rejected.
+func (m Meta) DecodeCursor() (*Cursor, error) {
+	if m.Cursor == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(m.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if cursor.SortBy != m.SortBy || cursor.Sort != m.Sort || cursor.ID == nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
 // Count calculates the total number of pages based on the total data count.
 // It sets the TotalData and TotalPage fields in the Meta struct.
 func (m *Meta) Count(totaldata int) {
@@ -158,6 +250,33 @@ func (m Meta) SeparateFilter() map[string]string {
 	return filterMap
 }
 
+// Uses tells whether the params sort, filter or query on field. A q that
+// doesn't parse uses nothing, the repository reports it.
+func (m Meta) Uses(field string) bool {
+	if m.SortBy == field {
+		return true
+	}
+
+	if _, ok := m.SeparateFilter()[field]; ok {
+		return true
+	}
+
+	expr, err := ParseQuery(m.Query)
+	if err != nil {
+		return false
+	}
+
+	for _, clause := range expr {
+		for _, condition := range clause {
+			if condition.Field == field {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (m *Meta) SetSort(sort string) {
 	m.Sort = sort
 }
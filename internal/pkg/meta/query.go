@@ -0,0 +1,215 @@
+package meta
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query expressions are sent in the q param. Clauses separated by ";" must
+// all match, alternatives inside a clause separated by "|" need only one:
+//
+//	due_date<@today;status in (IFR Comment,IFU);discipline=PIPING|sub_discipline is null
+//
+// Supported operators are =, !=, >, >=, <, <=, ~ (contains), !~, in (...),
+// not in (...), is null and is not null. Values may be wrapped in double
+// quotes to keep ; | , ( ) in them. @now and @today (optionally followed by
+// an offset in days, e.g. @today-7) stand for the request time.
+
+const (
+	OpEqual        = "="
+	OpNotEqual     = "!="
+	OpGreater      = ">"
+	OpGreaterEqual = ">="
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpContains     = "~"
+	OpNotContains  = "!~"
+	OpIn           = "in"
+	OpNotIn        = "not in"
+	OpIsNull       = "is null"
+	OpIsNotNull    = "is not null"
+)
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+// longer operators first so ">=" is not read as ">"
+var queryOperators = []string{
+	OpIsNotNull, OpIsNull, OpNotIn, OpIn,
+	OpGreaterEqual, OpLessEqual, OpNotEqual, OpNotContains,
+	OpEqual, OpGreater, OpLess, OpContains,
+}
+
+type (
+	// Condition compares one whitelisted field. Values holds a single value
+	// for comparisons, the list for in and nothing for null checks.
+	Condition struct {
+		Field    string
+		Operator string
+		Values   []string
+	}
+
+	// Expression is a list of clauses that must all match, each clause
+	// being a list of conditions of which one must match.
+	Expression [][]Condition
+)
+
+// ParseQuery parses the q param. The fields are not checked here, the
+// repository validates them against the fields it exposes.
+func ParseQuery(q string) (Expression, error) {
+	var expr Expression
+	for _, rawClause := range splitQuery(q, ';') {
+		if strings.TrimSpace(rawClause) == "" {
+			continue
+		}
+
+		var clause []Condition
+		for _, rawCondition := range splitQuery(rawClause, '|') {
+			condition, err := parseCondition(rawCondition)
+			if err != nil {
+				return nil, err
+			}
+			clause = append(clause, condition)
+		}
+
+		expr = append(expr, clause)
+	}
+
+	return expr, nil
+}
+
+func parseCondition(s string) (Condition, error) {
+	s = strings.TrimSpace(s)
+
+	end := 0
+	for end < len(s) && isFieldChar(s[end]) {
+		end++
+	}
+
+	if end == 0 {
+		return Condition{}, fmt.Errorf("%w: missing field in %q", ErrInvalidQuery, s)
+	}
+
+	field := s[:end]
+	rest := strings.TrimSpace(s[end:])
+	lower := strings.ToLower(rest)
+
+	for _, op := range queryOperators {
+		if !strings.HasPrefix(lower, op) {
+			continue
+		}
+
+		// word operators must be followed by a space or the end
+		value := strings.TrimSpace(rest[len(op):])
+		if isFieldChar(op[0]) && len(rest) > len(op) && isFieldChar(rest[len(op)]) {
+			continue
+		}
+
+		condition := Condition{Field: field, Operator: op}
+		switch op {
+		case OpIsNull, OpIsNotNull:
+			if value != "" {
+				return Condition{}, fmt.Errorf("%w: unexpected %q after %s", ErrInvalidQuery, value, op)
+			}
+		case OpIn, OpNotIn:
+			if !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+				return Condition{}, fmt.Errorf("%w: %s needs a list in parentheses", ErrInvalidQuery, op)
+			}
+
+			for _, v := range splitQuery(value[1:len(value)-1], ',') {
+				v, err := queryValue(v)
+				if err != nil {
+					return Condition{}, err
+				}
+				condition.Values = append(condition.Values, v)
+			}
+
+			if len(condition.Values) == 0 {
+				return Condition{}, fmt.Errorf("%w: empty list for %s", ErrInvalidQuery, field)
+			}
+		default:
+			v, err := queryValue(value)
+			if err != nil {
+				return Condition{}, err
+			}
+			condition.Values = []string{v}
+		}
+
+		return condition, nil
+	}
+
+	return Condition{}, fmt.Errorf("%w: unknown operator in %q", ErrInvalidQuery, s)
+}
+
+// queryValue unquotes a value and resolves the @now and @today macros
+func queryValue(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strings.ReplaceAll(s[1:len(s)-1], `\"`, `"`), nil
+	}
+
+	if !strings.HasPrefix(s, "@") {
+		return s, nil
+	}
+
+	now := time.Now()
+	base, offset := s, ""
+	if i := strings.IndexAny(s, "+-"); i > 0 {
+		base, offset = s[:i], s[i:]
+	}
+
+	var at time.Time
+	switch base {
+	case "@now":
+		at = now
+	case "@today":
+		at = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	default:
+		return "", fmt.Errorf("%w: unknown value %s", ErrInvalidQuery, s)
+	}
+
+	if offset != "" {
+		days, err := strconv.Atoi(strings.TrimSuffix(offset, "d"))
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid offset in %s", ErrInvalidQuery, s)
+		}
+		at = at.AddDate(0, 0, days)
+	}
+
+	return at.Format(time.RFC3339), nil
+}
+
+// splitQuery splits on sep outside of quotes and parentheses
+func splitQuery(s string, sep byte) []string {
+	var (
+		parts  []string
+		depth  int
+		quoted bool
+		start  int
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && quoted:
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+func isFieldChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
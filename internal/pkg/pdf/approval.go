@@ -0,0 +1,137 @@
+package mypdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const contentHashPrefix = "crs-content-hash:"
+
+var contentHashPattern = regexp.MustCompile(contentHashPrefix + `([0-9a-f]{64})`)
+
+type (
+	// ApprovalBlock is printed at the end of a signed sheet. ContentHash is
+	// the ContentHash of the rendered data and is embedded in the file.
+	ApprovalBlock struct {
+		ContentHash string
+		Approvals   []Approval
+	}
+
+	Approval struct {
+		Role        string
+		Name        string
+		ApprovedAt  string
+		ContentHash string
+	}
+)
+
+// GenerateSigned renders the sheet like Generate and adds the approval block
+// plus the content hash on every page and in the document keywords
+func GenerateSigned(req []GenerateRequestData, tpl Template, approval ApprovalBlock) (*bytes.Buffer, string, error) {
+	buf, _, err := generate(req, tpl, &approval)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return buf, "comment_resolution_sheet_signed.pdf", nil
+}
+
+// ContentHash hashes the data of a sheet independent of row numbering and
+// of the order the rows were loaded in, so the same comments always give the
+// same hash
+func ContentHash(req []GenerateRequestData) string {
+	var entries []string
+	for _, r := range req {
+		section, _ := json.Marshal([]any{r.PackageInfoData, r.DisciplineSectionData})
+		if len(r.CommentRow) == 0 {
+			entries = append(entries, string(section))
+			continue
+		}
+
+		for _, row := range r.CommentRow {
+			row.No = ""
+			data, _ := json.Marshal(row)
+			entries = append(entries, string(section)+string(data))
+		}
+	}
+	sort.Strings(entries)
+
+	sum := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// ExtractContentHash finds the content hash embedded by GenerateSigned
+func ExtractContentHash(file []byte) (string, bool) {
+	match := contentHashPattern.FindSubmatch(file)
+	if match == nil {
+		return "", false
+	}
+
+	return string(match[1]), true
+}
+
+func embedContentHash(pdf *gofpdf.Fpdf, hash string) {
+	pdf.SetKeywords(contentHashPrefix+hash, false)
+	pdf.SetFooterFunc(func() {
+		_, pageHeight := pdf.GetPageSize()
+		pdf.SetFont("Arial", "", 6)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.SetXY(marginSide, pageHeight-8)
+		pdf.Cell(200, 3, fmt.Sprintf("CRS content hash (SHA-256): %s", hash))
+		pdf.SetTextColor(ColorBlack[0], ColorBlack[1], ColorBlack[2])
+	})
+}
+
+// drawApprovalBlock draws one row per approval below y, or on a new page when
+// it does not fit
+func drawApprovalBlock(pdf *gofpdf.Fpdf, approval ApprovalBlock, y float64) {
+	pageWidth, pageHeight := pdf.GetPageSize()
+	rowHeight := 6.0
+	needed := 8 + rowHeight*float64(len(approval.Approvals)+1)
+
+	y += 5
+	if y+needed > pageHeight-marginBottom {
+		pdf.AddPage()
+		y = tableStartYPage
+	}
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetXY(marginSide, y)
+	pdf.Cell(100, 5, "APPROVAL")
+	y += 7
+
+	width := pageWidth - 2*marginSide
+	colWidths := []float64{width * 0.15, width * 0.25, width * 0.2, width * 0.4}
+	headers := []string{"Role", "Name", "Approved At", "Approved Content Hash"}
+
+	pdf.SetFont("Arial", "B", 7)
+	setFillColor(pdf, ColorGray)
+	x := marginSide
+	for i, header := range headers {
+		pdf.Rect(x, y, colWidths[i], rowHeight, "FD")
+		pdf.SetXY(x+1, y+1.5)
+		pdf.Cell(colWidths[i]-2, 3, header)
+		x += colWidths[i]
+	}
+	y += rowHeight
+
+	pdf.SetFont("Arial", "", 7)
+	for _, a := range approval.Approvals {
+		x = marginSide
+		for i, text := range []string{a.Role, a.Name, a.ApprovedAt, a.ContentHash} {
+			pdf.Rect(x, y, colWidths[i], rowHeight, "D")
+			pdf.SetXY(x+1, y+1.5)
+			pdf.Cell(colWidths[i]-2, 3, text)
+			x += colWidths[i]
+		}
+		y += rowHeight
+	}
+}
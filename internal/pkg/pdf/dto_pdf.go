@@ -1,5 +1,7 @@
 package mypdf
 
+import "io"
+
 type (
 	GenerateRequestData struct {
 		PackageInfoData       PackageInfoData
@@ -10,6 +12,9 @@ type (
 	PackageInfoData struct {
 		Package           string
 		ContractorInitial string
+		// label of the outcome of the document revision, left out of the
+		// sign-off hash while it is not set
+		ReviewOutcome string `json:",omitempty"`
 	}
 
 	DisciplineSectionData struct {
@@ -29,5 +34,59 @@ type (
 		DocStatus       string
 		Status          string
 		SMECloseComment string
+		// left out of the sign-off hash when empty, rows signed before
+		// comment classes existed keep their hash
+		Category string `json:",omitempty"`
+		Severity string `json:",omitempty"`
+	}
+
+	// TransmittalRequestData is the cover sheet of a transmittal, the values
+	// are printed as they are
+	TransmittalRequestData struct {
+		Number          string
+		Direction       string
+		Purpose         string
+		ReferenceNumber string
+		ReplyTo         string
+		Package         string
+		From            string
+		To              string
+		Subject         string
+		Remarks         string
+		TransmittalDate string
+		ReceivedAt      string
+		Documents       []TransmittalRow
+	}
+
+	TransmittalRow struct {
+		No                   string
+		DocumentNo           string
+		ContractorDocumentNo string
+		Title                string
+		Revision             string
+		DueDate              string
+		ReviewOutcome        string
+	}
+
+	MarkupRequestData struct {
+		Title    string
+		Source   io.ReadSeeker
+		Callouts []MarkupCallout
+	}
+
+	// MarkupCallout is a comment placed on the source document. Page 0 means
+	// the comment has no anchor and only shows up in the summary.
+	MarkupCallout struct {
+		No              string
+		Page            int
+		X               *float64
+		Y               *float64
+		Width           *float64
+		Height          *float64
+		Author          string
+		Comment         string
+		Status          string
+		CloseOutComment string
+		Closed          bool
 	}
 )
@@ -9,8 +9,12 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-// GenerateExcel membuat file Excel berdasarkan data request
-func GenerateExcel(req []GenerateRequestData) (*bytes.Buffer, string, error) {
+// GenerateExcel membuat file Excel berdasarkan data request dan template
+func GenerateExcel(req []GenerateRequestData, tpl Template) (*bytes.Buffer, string, error) {
+	if err := tpl.Validate(); err != nil {
+		return nil, "", err
+	}
+
 	f := excelize.NewFile()
 
 	// Hapus sheet default "Sheet1" nanti setelah kita buat sheet baru
@@ -26,7 +30,8 @@ func GenerateExcel(req []GenerateRequestData) (*bytes.Buffer, string, error) {
 		f.SetActiveSheet(index)
 
 		// Set lebar kolom agar proporsional mirip PDF
-		setColumnWidths(f, sheetName)
+		setColumnWidths(f, sheetName, tpl.Columns)
+		setPageLayout(f, sheetName, tpl)
 
 		// Siapkan Style
 		styles, err := createExcelStyles(f)
@@ -36,11 +41,11 @@ func GenerateExcel(req []GenerateRequestData) (*bytes.Buffer, string, error) {
 
 		// Gambar Layout
 		currentRow := 1
-		if err := drawExcelHeader(f, sheetName, &currentRow, styles); err != nil {
+		if err := drawExcelHeader(f, sheetName, tpl, &currentRow, styles); err != nil {
 			return nil, "", err
 		}
 
-		drawExcelPackageInfo(f, sheetName, r.PackageInfoData, &currentRow)
+		drawExcelPackageInfo(f, sheetName, tpl, r.PackageInfoData, &currentRow)
 
 		// Spasi sebelum tabel discipline
 		currentRow += 1
@@ -50,7 +55,9 @@ func GenerateExcel(req []GenerateRequestData) (*bytes.Buffer, string, error) {
 		// Spasi sebelum tabel utama
 		currentRow += 2
 
-		drawExcelMainTable(f, sheetName, r.CommentRow, &currentRow, styles)
+		drawExcelMainTable(f, sheetName, tpl.Columns, r.CommentRow, &currentRow, styles)
+
+		drawExcelFooter(f, sheetName, tpl, &currentRow, styles)
 	}
 
 	// Hapus sheet default jika tidak terpakai
@@ -71,21 +78,34 @@ func GenerateExcel(req []GenerateRequestData) (*bytes.Buffer, string, error) {
 
 // --- Helper Functions ---
 
-// setColumnWidths mengatur lebar kolom (perkiraan konversi mm ke width excel)
-func setColumnWidths(f *excelize.File, sheet string) {
-	// Mapping lebar kolom berdasarkan visual PDF:
-	// A: No, B: Page, C: SME Init, D: SME Comment
-	// E: RefDocNo, F: RefDocTitle, G: DocStatus, H: Status, I: CloseOut
-
-	f.SetColWidth(sheet, "A", "A", 6)  // No
-	f.SetColWidth(sheet, "B", "B", 10) // Page
-	f.SetColWidth(sheet, "C", "C", 12) // SME Initial
-	f.SetColWidth(sheet, "D", "D", 40) // SME Comment (Lebar agar muat banyak teks)
-	f.SetColWidth(sheet, "E", "E", 25) // Ref Doc No
-	f.SetColWidth(sheet, "F", "F", 30) // Ref Doc Title
-	f.SetColWidth(sheet, "G", "G", 15) // Doc Status
-	f.SetColWidth(sheet, "H", "H", 10) // Status
-	f.SetColWidth(sheet, "I", "I", 30) // SME Close Out
+// setColumnWidths mengatur lebar kolom sesuai template
+func setColumnWidths(f *excelize.File, sheet string, columns []Column) {
+	for i, c := range columns {
+		col := columnName(i)
+		f.SetColWidth(sheet, col, col, c.excelWidth())
+	}
+}
+
+// setPageLayout menyamakan ukuran kertas dan orientasi dengan pdf
+func setPageLayout(f *excelize.File, sheet string, tpl Template) {
+	// kode ukuran kertas excel: 1 Letter, 5 Legal, 8 A3, 9 A4, 11 A5
+	sizes := map[string]int{"Letter": 1, "Legal": 5, "A3": 8, "A4": 9, "A5": 11}
+	orientation := "landscape"
+	if tpl.Orientation == "P" {
+		orientation = "portrait"
+	}
+
+	size := sizes[tpl.PaperSize]
+	f.SetPageLayout(sheet, &excelize.PageLayoutOptions{
+		Size:        &size,
+		Orientation: &orientation,
+	})
+}
+
+// columnName mengubah index 0-based menjadi nama kolom excel (A, B, ..., AA)
+func columnName(i int) string {
+	name, _ := excelize.ColumnNumberToName(i + 1)
+	return name
 }
 
 type excelStyles struct {
@@ -157,47 +177,50 @@ func createExcelStyles(f *excelize.File) (*excelStyles, error) {
 	}, nil
 }
 
-func drawExcelHeader(f *excelize.File, sheet string, row *int, s *excelStyles) error {
+func drawExcelHeader(f *excelize.File, sheet string, tpl Template, row *int, s *excelStyles) error {
 	// Masukkan Logo
 	// Catatan: Pastikan path gambar valid. Jika error, gambar tidak muncul tapi file tetap tergenerate.
-	if err := f.AddPicture(sheet, fmt.Sprintf("A%d", *row), "./assets/image/Logo-CRS.png", &excelize.GraphicOptions{
-		ScaleX: 0.2,
-		ScaleY: 0.2,
-	}); err != nil {
-		fmt.Println("Warning: Logo not found or error loading:", err)
+	if tpl.LogoPath != "" {
+		if err := f.AddPicture(sheet, fmt.Sprintf("A%d", *row), tpl.LogoPath, &excelize.GraphicOptions{
+			ScaleX: 0.2,
+			ScaleY: 0.2,
+		}); err != nil {
+			fmt.Println("Warning: Logo not found or error loading:", err)
+		}
 	}
 
-	// Judul "COMMENT RESOLUTION SHEET"
-	// Merge cell dari D sampai I agar center visualnya pas
-	f.MergeCell(sheet, fmt.Sprintf("D%d", *row), fmt.Sprintf("I%d", *row+1))
-	f.SetCellValue(sheet, fmt.Sprintf("D%d", *row), "COMMENT RESOLUTION SHEET")
-	f.SetCellStyle(sheet, fmt.Sprintf("D%d", *row), fmt.Sprintf("I%d", *row+1), s.Title)
+	// Judul di-merge dari kolom ke-4 sampai kolom terakhir agar center visualnya pas
+	first, last := columnName(min(3, len(tpl.Columns)-1)), columnName(len(tpl.Columns)-1)
+	f.MergeCell(sheet, fmt.Sprintf("%s%d", first, *row), fmt.Sprintf("%s%d", last, *row+1))
+	f.SetCellValue(sheet, fmt.Sprintf("%s%d", first, *row), tpl.Title)
+	f.SetCellStyle(sheet, fmt.Sprintf("%s%d", first, *row), fmt.Sprintf("%s%d", last, *row+1), s.Title)
 
 	*row += 4 // Turun baris setelah header
 	return nil
 }
 
-func drawExcelPackageInfo(f *excelize.File, sheet string, data PackageInfoData, row *int) {
+func drawExcelPackageInfo(f *excelize.File, sheet string, tpl Template, data PackageInfoData, row *int) {
 	startRow := *row
 
-	// Kiri
-	f.SetCellValue(sheet, fmt.Sprintf("A%d", startRow), "Package")
-	f.SetCellValue(sheet, fmt.Sprintf("C%d", startRow), ": "+data.Package)
-
-	f.SetCellValue(sheet, fmt.Sprintf("A%d", startRow+1), "FEED Contractor")
-	f.SetCellValue(sheet, fmt.Sprintf("C%d", startRow+1), ": "+data.ContractorInitial)
-
-	// Kanan
-	f.SetCellValue(sheet, fmt.Sprintf("G%d", startRow), "Inc. Transmittal")
-	f.SetCellValue(sheet, fmt.Sprintf("H%d", startRow), ":")
-
-	f.SetCellValue(sheet, fmt.Sprintf("G%d", startRow+1), "Out. Transmittal")
-	f.SetCellValue(sheet, fmt.Sprintf("H%d", startRow+1), ":")
+	// Kanan memakai 3 kolom terakhir, minimal mulai dari kolom E
+	rightLabel := columnName(max(4, len(tpl.Columns)-3))
+	rightValue := columnName(max(5, len(tpl.Columns)-2))
+
+	left, right := 0, 0
+	for _, field := range tpl.HeaderFields {
+		if field.Right {
+			f.SetCellValue(sheet, fmt.Sprintf("%s%d", rightLabel, startRow+right), field.Label)
+			f.SetCellValue(sheet, fmt.Sprintf("%s%d", rightValue, startRow+right), ": "+field.Text(data))
+			right++
+			continue
+		}
 
-	f.SetCellValue(sheet, fmt.Sprintf("G%d", startRow+2), "Out. Transmittal Date")
-	f.SetCellValue(sheet, fmt.Sprintf("H%d", startRow+2), ":")
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", startRow+left), field.Label)
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", startRow+left), ": "+field.Text(data))
+		left++
+	}
 
-	*row += 4
+	*row += max(4, max(left, right)+1)
 }
 
 func drawExcelDisciplineSection(f *excelize.File, sheet string, data DisciplineSectionData, row *int, s *excelStyles) {
@@ -229,22 +252,14 @@ func drawExcelDisciplineSection(f *excelize.File, sheet string, data DisciplineS
 		mustNewStyle(f, &excelize.Style{Font: &excelize.Font{Italic: true, Size: 8, Family: "Arial"}}))
 }
 
-func drawExcelMainTable(f *excelize.File, sheet string, rows []CommentRow, row *int, s *excelStyles) {
-	headers := []string{
-		"No.", "Page *", "SME Initial", "SME\nComment",
-		"Ref. Document No.", "Ref. Document Title",
-		"Doc. Status", "Status", "SME Close Out\nComments",
-	}
-
-	colNames := []string{"A", "B", "C", "D", "E", "F", "G", "H", "I"}
-
+func drawExcelMainTable(f *excelize.File, sheet string, columns []Column, rows []CommentRow, row *int, s *excelStyles) {
 	// Draw Headers
-	for i, header := range headers {
-		cell := colNames[i] + strconv.Itoa(*row)
-		f.SetCellValue(sheet, cell, header)
+	for i, column := range columns {
+		cell := columnName(i) + strconv.Itoa(*row)
+		f.SetCellValue(sheet, cell, column.Label)
 
-		// 4 Kolom pertama Abu-abu, sisanya Kuning (Sesuai logika PDF: i >= 4)
-		if i >= 4 {
+		// Kolom yang di-highlight berwarna kuning, sisanya abu-abu
+		if column.Highlight {
 			f.SetCellStyle(sheet, cell, cell, s.HeaderYellow)
 		} else {
 			f.SetCellStyle(sheet, cell, cell, s.HeaderGray)
@@ -254,28 +269,52 @@ func drawExcelMainTable(f *excelize.File, sheet string, rows []CommentRow, row *
 
 	// Draw Data Rows
 	for _, dataRow := range rows {
-		vals := []string{
-			dataRow.No,
-			dataRow.Page,
-			dataRow.SMEInitial,
-			dataRow.SMEComment,
-			dataRow.RefDocNo,
-			dataRow.RefDocTitle,
-			dataRow.DocStatus,
-			dataRow.Status,
-			dataRow.SMECloseComment,
+		// wrap text akan menangani tinggi baris secara visual
+		for i, column := range columns {
+			cell := columnName(i) + strconv.Itoa(*row)
+			f.SetCellValue(sheet, cell, dataRow.Value(column.Key))
+			f.SetCellStyle(sheet, cell, cell, s.BodyText)
 		}
+		*row++
+	}
+}
 
-		// Cari tinggi baris maksimum berdasarkan panjang teks (manual estimation tidak sempurna di excel,
-		// tapi wrap text akan menanganinya secara visual)
+// drawExcelFooter menulis catatan kaki dan kotak tanda tangan di bawah tabel
+func drawExcelFooter(f *excelize.File, sheet string, tpl Template, row *int, s *excelStyles) {
+	if tpl.FooterNote == "" && len(tpl.SignatureBlocks) == 0 {
+		return
+	}
 
-		for i, val := range vals {
-			cell := colNames[i] + strconv.Itoa(*row)
-			f.SetCellValue(sheet, cell, val)
-			f.SetCellStyle(sheet, cell, cell, s.BodyText)
+	*row++
+	if tpl.FooterNote != "" {
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", *row), tpl.FooterNote)
+		f.SetCellStyle(sheet, fmt.Sprintf("A%d", *row), fmt.Sprintf("A%d", *row),
+			mustNewStyle(f, &excelize.Style{Font: &excelize.Font{Italic: true, Size: 8, Family: "Arial"}}))
+		*row += 2
+	}
+
+	if len(tpl.SignatureBlocks) == 0 {
+		return
+	}
+
+	// Bagi kolom tabel rata ke setiap kotak tanda tangan
+	span := max(1, len(tpl.Columns)/len(tpl.SignatureBlocks))
+	for i, label := range tpl.SignatureBlocks {
+		first, last := columnName(i*span), columnName(i*span+span-1)
+
+		f.MergeCell(sheet, fmt.Sprintf("%s%d", first, *row), fmt.Sprintf("%s%d", last, *row))
+		f.SetCellValue(sheet, fmt.Sprintf("%s%d", first, *row), label)
+		f.SetCellStyle(sheet, fmt.Sprintf("%s%d", first, *row), fmt.Sprintf("%s%d", last, *row), s.HeaderGray)
+
+		for j, line := range []string{"Name :", "Date :", "Signature :"} {
+			r := *row + 1 + j
+			f.MergeCell(sheet, fmt.Sprintf("%s%d", first, r), fmt.Sprintf("%s%d", last, r))
+			f.SetCellValue(sheet, fmt.Sprintf("%s%d", first, r), line)
+			f.SetCellStyle(sheet, fmt.Sprintf("%s%d", first, r), fmt.Sprintf("%s%d", last, r), s.BorderBox)
 		}
-		*row++
 	}
+
+	*row += 5
 }
 
 // Helper kecil untuk error handling style inline
@@ -0,0 +1,204 @@
+package mypdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
+)
+
+var (
+	ColorRed   = []int{200, 30, 30}
+	ColorGreen = []int{30, 140, 60}
+)
+
+const (
+	calloutRadius     = 8.0
+	calloutSpacing    = 20.0
+	summaryMargin     = 28.0
+	summaryPageHeight = 595.0
+	summaryRowLine    = 10.0
+	summaryFontSize   = 8.0
+)
+
+// GenerateMarkup stamps every callout onto its anchor page of the source
+// document and appends summary pages listing all comments. The output keeps
+// the source page sizes, so the unit is points instead of millimeters.
+func GenerateMarkup(req MarkupRequestData) (buf *bytes.Buffer, filename string, err error) {
+	// gofpdi panics when the source is not a readable pdf
+	defer func() {
+		if r := recover(); r != nil {
+			buf, filename, err = nil, "", fmt.Errorf("failed to read source document: %v", r)
+		}
+	}()
+
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+
+	importer := gofpdi.NewImporter()
+	var source io.ReadSeeker = req.Source
+
+	// the first import loads the reader so the page sizes become available
+	firstTpl := importer.ImportPageFromStream(pdf, &source, 1, "/MediaBox")
+	sizes := importer.GetPageSizes()
+
+	calloutsByPage := make(map[int][]MarkupCallout)
+	for _, c := range req.Callouts {
+		if c.Page < 1 || c.Page > len(sizes) {
+			continue
+		}
+		calloutsByPage[c.Page] = append(calloutsByPage[c.Page], c)
+	}
+
+	for page := 1; page <= len(sizes); page++ {
+		w, h := sizes[page]["/MediaBox"]["w"], sizes[page]["/MediaBox"]["h"]
+
+		tpl := firstTpl
+		if page > 1 {
+			tpl = importer.ImportPageFromStream(pdf, &source, page, "/MediaBox")
+		}
+
+		pdf.AddPageFormat("P", gofpdf.SizeType{Wd: w, Ht: h})
+		importer.UseImportedTemplate(pdf, tpl, 0, 0, w, h)
+
+		drawCallouts(pdf, calloutsByPage[page], w, h)
+	}
+
+	drawMarkupSummary(pdf, req)
+
+	if err := pdf.Error(); err != nil {
+		return nil, "", err
+	}
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, "", err
+	}
+
+	return &out, "marked_up_document.pdf", nil
+}
+
+// drawCallouts draws the anchor boxes and their numbered markers. Callouts
+// without a box are stacked along the right edge of the page.
+func drawCallouts(pdf *gofpdf.Fpdf, callouts []MarkupCallout, pageWidth, pageHeight float64) {
+	marginIndex := 0
+	for _, c := range callouts {
+		color := ColorRed
+		if c.Closed {
+			color = ColorGreen
+		}
+
+		var cx, cy float64
+		if c.X != nil && c.Y != nil && c.Width != nil && c.Height != nil {
+			x, y := *c.X*pageWidth, *c.Y*pageHeight
+			pdf.SetDrawColor(color[0], color[1], color[2])
+			pdf.SetLineWidth(1.2)
+			pdf.Rect(x, y, *c.Width*pageWidth, *c.Height*pageHeight, "D")
+
+			cx, cy = x, y
+		} else {
+			cx = pageWidth - calloutRadius*2
+			cy = calloutSpacing + float64(marginIndex)*calloutSpacing
+			marginIndex++
+		}
+
+		// keep the marker inside the page
+		cx = clamp(cx, calloutRadius, pageWidth-calloutRadius)
+		cy = clamp(cy, calloutRadius, pageHeight-calloutRadius)
+
+		setFillColor(pdf, color)
+		pdf.SetDrawColor(ColorWhite[0], ColorWhite[1], ColorWhite[2])
+		pdf.SetLineWidth(1)
+		pdf.Circle(cx, cy, calloutRadius, "FD")
+
+		pdf.SetTextColor(ColorWhite[0], ColorWhite[1], ColorWhite[2])
+		pdf.SetFont("Arial", "B", 7)
+		pdf.SetXY(cx-calloutRadius, cy-calloutRadius)
+		pdf.CellFormat(calloutRadius*2, calloutRadius*2, c.No, "", 0, "CM", false, 0, "")
+	}
+
+	pdf.SetTextColor(ColorBlack[0], ColorBlack[1], ColorBlack[2])
+}
+
+// drawMarkupSummary appends landscape pages with one row per callout
+func drawMarkupSummary(pdf *gofpdf.Fpdf, req MarkupRequestData) {
+	colWidths := []float64{30, 45, 90, 300, 60, 261}
+	headers := []string{"No.", "Page", "SME Initial", "SME Comment", "Status", "SME Close Out Comments"}
+
+	addSummaryPage := func() float64 {
+		pdf.AddPageFormat("L", gofpdf.SizeType{Wd: 595, Ht: 842})
+		setupPDFDefaults(pdf)
+
+		pdf.SetFont("Arial", "B", 14)
+		pdf.SetXY(summaryMargin, summaryMargin)
+		pdf.Cell(300, 16, "COMMENT SUMMARY")
+
+		pdf.SetFont("Arial", "", summaryFontSize)
+		pdf.SetXY(summaryMargin, summaryMargin+20)
+		pdf.Cell(600, 10, req.Title)
+
+		y := summaryMargin + 40
+		pdf.SetFont("Arial", "B", summaryFontSize)
+		setFillColor(pdf, ColorGray)
+		x := summaryMargin
+		for i, header := range headers {
+			pdf.Rect(x, y, colWidths[i], 16, "FD")
+			pdf.SetXY(x+2, y+3)
+			pdf.Cell(colWidths[i]-4, summaryRowLine, header)
+			x += colWidths[i]
+		}
+
+		pdf.SetFont("Arial", "", summaryFontSize)
+		return y + 16
+	}
+
+	y := addSummaryPage()
+	if len(req.Callouts) == 0 {
+		pdf.SetXY(summaryMargin, y+4)
+		pdf.Cell(300, summaryRowLine, "No comments on this document")
+		return
+	}
+
+	for _, c := range req.Callouts {
+		page := "-"
+		if c.Page > 0 {
+			page = fmt.Sprintf("%d", c.Page)
+		}
+
+		rowData := []string{c.No, page, c.Author, c.Comment, c.Status, c.CloseOutComment}
+
+		maxLines := 1
+		for i, text := range rowData {
+			lines := len(pdf.SplitLines([]byte(text), colWidths[i]-4))
+			if lines > maxLines {
+				maxLines = lines
+			}
+		}
+		rowHeight := 4 + float64(maxLines)*summaryRowLine
+
+		if y+rowHeight > summaryPageHeight-summaryMargin {
+			y = addSummaryPage()
+		}
+
+		x := summaryMargin
+		for i, text := range rowData {
+			pdf.Rect(x, y, colWidths[i], rowHeight, "D")
+			pdf.SetXY(x+2, y+2)
+			pdf.MultiCell(colWidths[i]-4, summaryRowLine, text, "", "L", false)
+			x += colWidths[i]
+		}
+		y += rowHeight
+	}
+}
+
+func clamp(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -16,24 +16,44 @@ var (
 
 const (
 	marginBottom    = 15.0
-	pageHeight      = 210.0
+	marginSide      = 10.0
 	minRowHeight    = 6.0
 	headerHeight    = 8.0
 	tableStartYPage = 10.0
+	signatureHeight = 28.0
 )
 
-func Generate(req []GenerateRequestData) (*bytes.Buffer, string, error) {
-	pdf := gofpdf.New("L", "mm", "A4", "")
+// Generate renders the comment resolution sheet using the given template
+func Generate(req []GenerateRequestData, tpl Template) (*bytes.Buffer, string, error) {
+	return generate(req, tpl, nil)
+}
+
+func generate(req []GenerateRequestData, tpl Template, approval *ApprovalBlock) (*bytes.Buffer, string, error) {
+	if err := tpl.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	pdf := gofpdf.New(tpl.Orientation, "mm", tpl.PaperSize, "")
 	pdf.SetAutoPageBreak(false, 0)
+	if approval != nil {
+		embedContentHash(pdf, approval.ContentHash)
+	}
 
+	colWidths := fitColumnWidths(pdf, tpl.Columns)
+	y := 0.0
 	for _, r := range req {
 		pdf.AddPage()
 		setupPDFDefaults(pdf)
-		drawHeader(pdf)
-		drawPackageInfo(pdf, r.PackageInfoData)
+		drawHeader(pdf, tpl)
+		drawPackageInfo(pdf, tpl, r.PackageInfoData)
 		drawDisciplineSection(pdf, r.DisciplineSectionData)
 
-		drawMainTableWithPageBreak(pdf, r.CommentRow)
+		y = drawMainTableWithPageBreak(pdf, tpl.Columns, colWidths, r.CommentRow)
+		y = drawFooter(pdf, tpl, y)
+	}
+
+	if approval != nil {
+		drawApprovalBlock(pdf, *approval, y)
 	}
 
 	filename := "comment_resolution_sheet.pdf"
@@ -52,48 +72,48 @@ func setupPDFDefaults(pdf *gofpdf.Fpdf) {
 	pdf.SetLineWidth(0.3)
 }
 
-func drawHeader(pdf *gofpdf.Fpdf) {
+func drawHeader(pdf *gofpdf.Fpdf, tpl Template) {
 	logoOpt := gofpdf.ImageOptions{
-		ImageType: "PNG",
-		ReadDpi:   true,
+		ReadDpi: true,
 	}
 
-	pdf.ImageOptions("./assets/image/Logo-CRS.png", 10, 8, 55, 12, false, logoOpt, 0, "")
+	if tpl.LogoPath != "" {
+		pdf.ImageOptions(tpl.LogoPath, 10, 8, 55, 12, false, logoOpt, 0, "")
+		// a missing logo should not break the whole sheet
+		if pdf.Err() {
+			pdf.ClearError()
+		}
+	}
 
+	pageWidth, _ := pdf.GetPageSize()
 	pdf.SetFont("Arial", "B", 16)
-	pdf.SetXY(100, 10)
-	pdf.Cell(30, 8, "COMMENT RESOLUTION SHEET")
+	titleWidth := pdf.GetStringWidth(tpl.Title)
+	pdf.SetXY((pageWidth-titleWidth)/2, 10)
+	pdf.Cell(titleWidth, 8, tpl.Title)
 }
 
-func drawPackageInfo(pdf *gofpdf.Fpdf, data PackageInfoData) {
+func drawPackageInfo(pdf *gofpdf.Fpdf, tpl Template, data PackageInfoData) {
 	pdf.SetFont("Arial", "", 8)
 	startY := 38.0
+	pageWidth, _ := pdf.GetPageSize()
+
+	left, right := 0, 0
+	for _, field := range tpl.HeaderFields {
+		labelX, valueX := 10.0, 45.0
+		i := left
+		if field.Right {
+			labelX, valueX = pageWidth-97, pageWidth-52
+			i = right
+			right++
+		} else {
+			left++
+		}
 
-	leftInfo := [][2]string{
-		{"Package", fmt.Sprintf(": %s", data.Package)},
-		{"FEED Contractor", fmt.Sprintf(": %s", data.ContractorInitial)},
-	}
-
-	for i, info := range leftInfo {
-		y := startY + float64(i*5)
-		pdf.SetXY(10, y)
-		pdf.Cell(35, 4, info[0])
-		pdf.SetXY(45, y)
-		pdf.Cell(60, 4, info[1])
-	}
-
-	rightInfo := []string{
-		"Inc. Transmittal",
-		"Out. Transmittal",
-		"Out. Transmittal Date",
-	}
-
-	for i, label := range rightInfo {
 		y := startY + float64(i*5)
-		pdf.SetXY(200, y)
-		pdf.Cell(40, 4, label)
-		pdf.SetXY(245, y)
-		pdf.Cell(5, 4, ":")
+		pdf.SetXY(labelX, y)
+		pdf.Cell(35, 4, field.Label)
+		pdf.SetXY(valueX, y)
+		pdf.Cell(60, 4, fmt.Sprintf(": %s", field.Text(data)))
 	}
 }
 
@@ -149,24 +169,36 @@ func drawDisciplineSection(pdf *gofpdf.Fpdf, data DisciplineSectionData) {
 	pdf.Cell(100, 4, "*Please manually sort page number in ascending order")
 }
 
+// fitColumnWidths scales the template widths down when they do not fit the
+// printable width of the page
+func fitColumnWidths(pdf *gofpdf.Fpdf, columns []Column) []float64 {
+	pageWidth, _ := pdf.GetPageSize()
+	available := pageWidth - 2*marginSide
+
+	total := 0.0
+	for _, c := range columns {
+		total += c.Width
+	}
+
+	scale := 1.0
+	if total > available {
+		scale = available / total
+	}
+
+	widths := make([]float64, len(columns))
+	for i, c := range columns {
+		widths[i] = c.Width * scale
+	}
+
+	return widths
+}
+
 // calculateRowHeight calculates the height needed for a row based on its content
-func calculateRowHeight(pdf *gofpdf.Fpdf, row CommentRow, colWidths []float64) float64 {
+func calculateRowHeight(pdf *gofpdf.Fpdf, rowData []string, colWidths []float64) float64 {
 	pdf.SetFont("Arial", "", 7)
 	lineHeight := 3.0
 	maxLines := 1
 
-	rowData := []string{
-		row.No,
-		row.Page,
-		row.SMEInitial,
-		row.SMEComment,
-		row.RefDocNo,
-		row.RefDocTitle,
-		row.DocStatus,
-		row.Status,
-		row.SMECloseComment,
-	}
-
 	// Calculate lines needed for each column
 	for i, text := range rowData {
 		if text == "" {
@@ -189,33 +221,27 @@ func calculateRowHeight(pdf *gofpdf.Fpdf, row CommentRow, colWidths []float64) f
 }
 
 // drawMainTableWithPageBreak draws the main table with manual page break handling
-func drawMainTableWithPageBreak(pdf *gofpdf.Fpdf, rows []CommentRow) {
+// and returns the y position below the last row
+func drawMainTableWithPageBreak(pdf *gofpdf.Fpdf, columns []Column, colWidths []float64, rows []CommentRow) float64 {
+	_, pageHeight := pdf.GetPageSize()
 	tableStartY := 86.0
 	currentY := tableStartY
 
-	colWidths := []float64{10, 20, 20, 40, 40, 40, 30, 25, 40}
-	headers := []string{
-		"No.",
-		"Page *",
-		"SME Initial",
-		"SME\nComment",
-		"Ref. Document No.",
-		"Ref. Document Title",
-		"Doc. Status",
-		"Status",
-		"SME Close Out\nComments",
-	}
-
 	// Draw header pertama kali
-	drawTableHeaders(pdf, headers, colWidths, currentY)
+	drawTableHeaders(pdf, columns, colWidths, currentY)
 	currentY += headerHeight
 
 	pdf.SetFont("Arial", "", 7)
 
 	// Loop through all rows
 	for _, row := range rows {
+		rowData := make([]string, len(columns))
+		for i, c := range columns {
+			rowData[i] = row.Value(c.Key)
+		}
+
 		// Calculate height for this row
-		rowHeight := calculateRowHeight(pdf, row, colWidths)
+		rowHeight := calculateRowHeight(pdf, rowData, colWidths)
 
 		// Cek apakah masih cukup ruang untuk row ini
 		if currentY+rowHeight > pageHeight-marginBottom {
@@ -224,24 +250,26 @@ func drawMainTableWithPageBreak(pdf *gofpdf.Fpdf, rows []CommentRow) {
 			currentY = tableStartYPage
 
 			// Draw header lagi di halaman baru
-			drawTableHeaders(pdf, headers, colWidths, currentY)
+			drawTableHeaders(pdf, columns, colWidths, currentY)
 			currentY += headerHeight
 			pdf.SetFont("Arial", "", 7)
 		}
 
 		// Draw row with calculated height
-		drawTableRowMultiline(pdf, row, currentY, colWidths, rowHeight)
+		drawTableRowMultiline(pdf, rowData, currentY, colWidths, rowHeight)
 		currentY += rowHeight
 	}
+
+	return currentY
 }
 
 // drawTableHeaders draws the table header row
-func drawTableHeaders(pdf *gofpdf.Fpdf, headers []string, colWidths []float64, y float64) {
+func drawTableHeaders(pdf *gofpdf.Fpdf, columns []Column, colWidths []float64, y float64) {
 	pdf.SetFont("Arial", "B", 7)
-	x := 10.0
+	x := marginSide
 
-	for i, header := range headers {
-		if i >= 4 {
+	for i, column := range columns {
+		if column.Highlight {
 			setFillColor(pdf, ColorYellow)
 		} else {
 			setFillColor(pdf, ColorGray)
@@ -249,45 +277,79 @@ func drawTableHeaders(pdf *gofpdf.Fpdf, headers []string, colWidths []float64, y
 
 		pdf.Rect(x, y, colWidths[i], headerHeight, "FD")
 		pdf.SetXY(x+1, y+1)
-		pdf.MultiCell(colWidths[i]-2, 3, header, "", "C", false)
+		pdf.MultiCell(colWidths[i]-2, 3, column.Label, "", "C", false)
 		x += colWidths[i]
 	}
 }
 
 // drawTableRowMultiline draws a single data row with multiline support
-func drawTableRowMultiline(pdf *gofpdf.Fpdf, row CommentRow, y float64, colWidths []float64, height float64) {
-	x := 10.0
-	rowData := []string{
-		row.No,
-		row.Page,
-		row.SMEInitial,
-		row.SMEComment,
-		row.RefDocNo,
-		row.RefDocTitle,
-		row.DocStatus,
-		row.Status,
-		row.SMECloseComment,
-	}
+func drawTableRowMultiline(pdf *gofpdf.Fpdf, rowData []string, y float64, colWidths []float64, height float64) {
+	x := marginSide
 
 	for i, data := range rowData {
 		// Draw cell border
 		pdf.Rect(x, y, colWidths[i], height, "D")
 
-		// Draw text with wrapping
-		pdf.SetXY(x+1, y+1)
-
-		// Save current position for MultiCell
-		startX := x + 1
-		startY := y + 1
-
 		// Use MultiCell for text wrapping
-		pdf.SetXY(startX, startY)
+		pdf.SetXY(x+1, y+1)
 		pdf.MultiCell(colWidths[i]-2, 3, data, "", "L", false)
 
 		x += colWidths[i]
 	}
 }
 
+// drawFooter draws the footer note and the signature blocks below the table,
+// moving to a new page when they do not fit. It returns the y position below
+// the footer.
+func drawFooter(pdf *gofpdf.Fpdf, tpl Template, y float64) float64 {
+	if tpl.FooterNote == "" && len(tpl.SignatureBlocks) == 0 {
+		return y
+	}
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	needed := 10.0
+	if len(tpl.SignatureBlocks) > 0 {
+		needed += signatureHeight
+	}
+
+	y += 5
+	if y+needed > pageHeight-marginBottom {
+		pdf.AddPage()
+		y = tableStartYPage
+	}
+
+	if tpl.FooterNote != "" {
+		pdf.SetFont("Arial", "I", 7)
+		pdf.SetXY(marginSide, y)
+		pdf.MultiCell(pageWidth-2*marginSide, 3, tpl.FooterNote, "", "L", false)
+		y = pdf.GetY() + 3
+	}
+
+	if len(tpl.SignatureBlocks) == 0 {
+		return y
+	}
+
+	blockWidth := (pageWidth - 2*marginSide) / float64(len(tpl.SignatureBlocks))
+	for i, label := range tpl.SignatureBlocks {
+		x := marginSide + float64(i)*blockWidth
+
+		pdf.SetFont("Arial", "B", 7)
+		setFillColor(pdf, ColorGray)
+		pdf.Rect(x, y, blockWidth, 6, "FD")
+		pdf.SetXY(x+1, y+1.5)
+		pdf.Cell(blockWidth-2, 3, label)
+
+		pdf.Rect(x, y+6, blockWidth, signatureHeight-6, "D")
+		pdf.SetFont("Arial", "", 7)
+		for j, line := range []string{"Name", "Date", "Signature"} {
+			pdf.SetXY(x+1, y+8+float64(j)*6)
+			pdf.Cell(blockWidth-2, 3, line+" :")
+		}
+	}
+
+	return y + signatureHeight
+}
+
 // setFillColor sets the fill color from RGB array
 func setFillColor(pdf *gofpdf.Fpdf, color []int) {
 	pdf.SetFillColor(color[0], color[1], color[2])
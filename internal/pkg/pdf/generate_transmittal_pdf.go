@@ -0,0 +1,127 @@
+package mypdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const transmittalTableStartY = 92.0
+
+var transmittalColumns = []Column{
+	{Label: "No.", Width: 10},
+	{Label: "Company Document No.", Width: 40},
+	{Label: "Contractor Document No.", Width: 35},
+	{Label: "Document Title", Width: 55},
+	{Label: "Rev.", Width: 10},
+	{Label: "Due Date", Width: 20},
+	{Label: "Review Outcome", Width: 20},
+}
+
+// GenerateTransmittal renders the cover sheet of a transmittal on A4
+// portrait, listing its documents and leaving room to sign for sending and
+// receipt
+func GenerateTransmittal(req TransmittalRequestData) (*bytes.Buffer, string, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+
+	pdf.AddPage()
+	setupPDFDefaults(pdf)
+	drawHeader(pdf, Template{LogoPath: DefaultLogoPath, Title: "DOCUMENT TRANSMITTAL"})
+	drawTransmittalInfo(pdf, req)
+
+	y := drawTransmittalDocuments(pdf, req.Documents)
+	drawFooter(pdf, Template{
+		FooterNote:      req.Remarks,
+		SignatureBlocks: []string{"Sent By", "Received By"},
+	}, y)
+
+	if err := pdf.Error(); err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, "", err
+	}
+
+	return &buf, fmt.Sprintf("transmittal_%s.pdf", req.Number), nil
+}
+
+// drawTransmittalInfo draws the transmittal fields in two columns
+func drawTransmittalInfo(pdf *gofpdf.Fpdf, req TransmittalRequestData) {
+	pageWidth, _ := pdf.GetPageSize()
+	left := [][2]string{
+		{"Transmittal No.", req.Number},
+		{"Direction", req.Direction},
+		{"Package", req.Package},
+		{"From", req.From},
+		{"To", req.To},
+	}
+	right := [][2]string{
+		{"Date", req.TransmittalDate},
+		{"Received", req.ReceivedAt},
+		{"Purpose of Issue", req.Purpose},
+		{"Sender Reference", req.ReferenceNumber},
+		{"Reply To", req.ReplyTo},
+	}
+
+	pdf.SetFont("Arial", "", 8)
+	for i, field := range left {
+		y := 30 + float64(i*5)
+		pdf.SetXY(marginSide, y)
+		pdf.Cell(30, 4, field[0])
+		pdf.SetXY(marginSide+30, y)
+		pdf.Cell(60, 4, fmt.Sprintf(": %s", field[1]))
+	}
+
+	for i, field := range right {
+		y := 30 + float64(i*5)
+		pdf.SetXY(pageWidth/2+10, y)
+		pdf.Cell(30, 4, field[0])
+		pdf.SetXY(pageWidth/2+40, y)
+		pdf.Cell(50, 4, fmt.Sprintf(": %s", field[1]))
+	}
+
+	pdf.SetFont("Arial", "B", 8)
+	setFillColor(pdf, ColorGray)
+	pdf.Rect(marginSide, 60, pageWidth-2*marginSide, 6, "FD")
+	pdf.SetXY(marginSide+1, 61.5)
+	pdf.Cell(40, 4, "Subject")
+
+	pdf.SetFont("Arial", "", 8)
+	pdf.Rect(marginSide, 66, pageWidth-2*marginSide, 20, "D")
+	pdf.SetXY(marginSide+1, 67)
+	pdf.MultiCell(pageWidth-2*marginSide-2, 4, strings.TrimSpace(req.Subject), "", "L", false)
+}
+
+// drawTransmittalDocuments lists the documents, repeating the table header
+// on every page, and returns the y position below the last row
+func drawTransmittalDocuments(pdf *gofpdf.Fpdf, rows []TransmittalRow) float64 {
+	_, pageHeight := pdf.GetPageSize()
+	colWidths := fitColumnWidths(pdf, transmittalColumns)
+
+	y := transmittalTableStartY
+	drawTableHeaders(pdf, transmittalColumns, colWidths, y)
+	y += headerHeight
+
+	for _, row := range rows {
+		rowData := []string{row.No, row.DocumentNo, row.ContractorDocumentNo, row.Title, row.Revision, row.DueDate, row.ReviewOutcome}
+		rowHeight := calculateRowHeight(pdf, rowData, colWidths)
+
+		if y+rowHeight > pageHeight-marginBottom {
+			pdf.AddPage()
+			y = tableStartYPage
+			drawTableHeaders(pdf, transmittalColumns, colWidths, y)
+			y += headerHeight
+			pdf.SetFont("Arial", "", 7)
+		}
+
+		drawTableRowMultiline(pdf, rowData, y, colWidths, rowHeight)
+		y += rowHeight
+	}
+
+	return y
+}
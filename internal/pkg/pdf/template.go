@@ -0,0 +1,214 @@
+package mypdf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	ColumnNo              = "no"
+	ColumnPage            = "page"
+	ColumnSMEInitial      = "sme_initial"
+	ColumnSMEComment      = "sme_comment"
+	ColumnRefDocNo        = "ref_doc_no"
+	ColumnRefDocTitle     = "ref_doc_title"
+	ColumnDocStatus       = "doc_status"
+	ColumnStatus          = "status"
+	ColumnSMECloseComment = "sme_close_comment"
+	ColumnCategory        = "category"
+	ColumnSeverity        = "severity"
+
+	HeaderPackage            = "package"
+	HeaderContractor         = "contractor"
+	HeaderIncTransmittal     = "inc_transmittal"
+	HeaderOutTransmittal     = "out_transmittal"
+	HeaderOutTransmittalDate = "out_transmittal_date"
+	HeaderReviewOutcome      = "review_outcome"
+
+	DefaultLogoPath = "./assets/image/Logo-CRS.png"
+)
+
+var (
+	ColumnKeys = []string{
+		ColumnNo, ColumnPage, ColumnSMEInitial, ColumnSMEComment, ColumnRefDocNo,
+		ColumnRefDocTitle, ColumnDocStatus, ColumnStatus, ColumnSMECloseComment,
+		ColumnCategory, ColumnSeverity,
+	}
+	HeaderKeys = []string{
+		HeaderPackage, HeaderContractor, HeaderIncTransmittal, HeaderOutTransmittal, HeaderOutTransmittalDate,
+		HeaderReviewOutcome,
+	}
+	PaperSizes = []string{"A3", "A4", "A5", "Letter", "Legal"}
+
+	ErrTemplateNoColumns = errors.New("template needs at least one column")
+)
+
+type (
+	// Template controls the layout of the comment resolution sheet. Widths
+	// are in millimeters for the pdf; ExcelWidth falls back to Width when 0.
+	Template struct {
+		LogoPath        string
+		Title           string
+		PaperSize       string
+		Orientation     string
+		HeaderFields    []HeaderField
+		Columns         []Column
+		FooterNote      string
+		SignatureBlocks []string
+	}
+
+	// HeaderField prints Value when it is set, otherwise the value of Key
+	HeaderField struct {
+		Label string `json:"label"`
+		Key   string `json:"key"`
+		Value string `json:"value"`
+		Right bool   `json:"right"`
+	}
+
+	Column struct {
+		Key        string  `json:"key"`
+		Label      string  `json:"label"`
+		Width      float64 `json:"width"`
+		ExcelWidth float64 `json:"excel_width"`
+		Highlight  bool    `json:"highlight"`
+	}
+)
+
+// DefaultTemplate is the layout used when a package has no template
+func DefaultTemplate() Template {
+	return Template{
+		LogoPath:    DefaultLogoPath,
+		Title:       "COMMENT RESOLUTION SHEET",
+		PaperSize:   "A4",
+		Orientation: "L",
+		HeaderFields: []HeaderField{
+			{Label: "Package", Key: HeaderPackage},
+			{Label: "FEED Contractor", Key: HeaderContractor},
+			{Label: "Inc. Transmittal", Key: HeaderIncTransmittal, Right: true},
+			{Label: "Out. Transmittal", Key: HeaderOutTransmittal, Right: true},
+			{Label: "Out. Transmittal Date", Key: HeaderOutTransmittalDate, Right: true},
+			{Label: "Review Outcome", Key: HeaderReviewOutcome, Right: true},
+		},
+		Columns: []Column{
+			{Key: ColumnNo, Label: "No.", Width: 10, ExcelWidth: 6},
+			{Key: ColumnPage, Label: "Page *", Width: 20, ExcelWidth: 10},
+			{Key: ColumnSMEInitial, Label: "SME Initial", Width: 20, ExcelWidth: 12},
+			{Key: ColumnSMEComment, Label: "SME\nComment", Width: 40, ExcelWidth: 40},
+			{Key: ColumnSeverity, Label: "Code", Width: 15, ExcelWidth: 8},
+			{Key: ColumnCategory, Label: "Category", Width: 25, ExcelWidth: 15},
+			{Key: ColumnRefDocNo, Label: "Ref. Document No.", Width: 40, ExcelWidth: 25, Highlight: true},
+			{Key: ColumnRefDocTitle, Label: "Ref. Document Title", Width: 40, ExcelWidth: 30, Highlight: true},
+			{Key: ColumnDocStatus, Label: "Doc. Status", Width: 30, ExcelWidth: 15, Highlight: true},
+			{Key: ColumnStatus, Label: "Status", Width: 25, ExcelWidth: 10, Highlight: true},
+			{Key: ColumnSMECloseComment, Label: "SME Close Out\nComments", Width: 40, ExcelWidth: 30, Highlight: true},
+		},
+	}
+}
+
+// Validate reports the first problem that would break rendering
+func (t Template) Validate() error {
+	if len(t.Columns) == 0 {
+		return ErrTemplateNoColumns
+	}
+
+	if !contains(PaperSizes, t.PaperSize) {
+		return fmt.Errorf("paper size must be one of %s", strings.Join(PaperSizes, ", "))
+	}
+
+	if t.Orientation != "L" && t.Orientation != "P" {
+		return errors.New("orientation must be L or P")
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range t.Columns {
+		if !contains(ColumnKeys, c.Key) {
+			return fmt.Errorf("unknown column %s", c.Key)
+		}
+		if seen[c.Key] {
+			return fmt.Errorf("column %s is used more than once", c.Key)
+		}
+		if c.Width <= 0 || c.ExcelWidth < 0 {
+			return fmt.Errorf("column %s must have a positive width", c.Key)
+		}
+		seen[c.Key] = true
+	}
+
+	for _, h := range t.HeaderFields {
+		if h.Value == "" && !contains(HeaderKeys, h.Key) {
+			return fmt.Errorf("header field %s needs a value or one of the keys %s", h.Label, strings.Join(HeaderKeys, ", "))
+		}
+	}
+
+	return nil
+}
+
+// Value returns the cell text for a column key
+func (r CommentRow) Value(key string) string {
+	switch key {
+	case ColumnNo:
+		return r.No
+	case ColumnPage:
+		return r.Page
+	case ColumnSMEInitial:
+		return r.SMEInitial
+	case ColumnSMEComment:
+		return r.SMEComment
+	case ColumnRefDocNo:
+		return r.RefDocNo
+	case ColumnRefDocTitle:
+		return r.RefDocTitle
+	case ColumnDocStatus:
+		return r.DocStatus
+	case ColumnStatus:
+		return r.Status
+	case ColumnSMECloseComment:
+		return r.SMECloseComment
+	case ColumnCategory:
+		return r.Category
+	case ColumnSeverity:
+		return r.Severity
+	default:
+		return ""
+	}
+}
+
+// Value returns the header text for a header key
+func (d PackageInfoData) Value(key string) string {
+	switch key {
+	case HeaderPackage:
+		return d.Package
+	case HeaderContractor:
+		return d.ContractorInitial
+	case HeaderReviewOutcome:
+		return d.ReviewOutcome
+	default:
+		return ""
+	}
+}
+
+func (h HeaderField) Text(data PackageInfoData) string {
+	if h.Value != "" {
+		return h.Value
+	}
+
+	return data.Value(h.Key)
+}
+
+func (c Column) excelWidth() float64 {
+	if c.ExcelWidth > 0 {
+		return c.ExcelWidth
+	}
+
+	return c.Width
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+// Package similarity scores how alike two short texts are, the same way
+// pg_trgm does: both texts are normalized, split into words and compared by
+// the share of word trigrams they have in common.
+package similarity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize lowercases the text and keeps letters and digits only, words
+// are separated by a single space
+func Normalize(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// Trigrams returns the set of trigrams of the normalized words. Each word is
+// padded with two spaces in front and one behind, so short words and word
+// starts weigh in.
+func Trigrams(text string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, word := range strings.Fields(Normalize(text)) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+
+	return set
+}
+
+// Score returns the Jaccard index of the trigram sets, from 0 (nothing in
+// common) to 1 (same words)
+func Score(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range a {
+		if _, ok := b[trigram]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// Compare is Score on two raw texts
+func Compare(a, b string) float64 {
+	return Score(Trigrams(a), Trigrams(b))
+}
+
+// Clusters groups the texts whose score reaches the threshold, directly or
+// through other texts of the group. Only groups of two or more are returned,
+// as indexes into texts, with the best score found inside each group.
+func Clusters(texts []string, threshold float64) ([][]int, []float64) {
+	trigrams := make([]map[string]struct{}, len(texts))
+	for i, text := range texts {
+		trigrams[i] = Trigrams(text)
+	}
+
+	parent := make([]int, len(texts))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	best := make([]float64, len(texts))
+	for i := range texts {
+		for j := i + 1; j < len(texts); j++ {
+			score := Score(trigrams[i], trigrams[j])
+			if score < threshold {
+				continue
+			}
+
+			ri, rj := find(i), find(j)
+			if ri != rj {
+				parent[rj] = ri
+				best[ri] = max(best[ri], best[rj])
+			}
+			best[ri] = max(best[ri], score)
+		}
+	}
+
+	var (
+		groups [][]int
+		scores []float64
+		index  = map[int]int{}
+	)
+	for i := range texts {
+		root := find(i)
+		if _, ok := index[root]; !ok {
+			index[root] = len(groups)
+			groups = append(groups, nil)
+			scores = append(scores, best[root])
+		}
+		groups[index[root]] = append(groups[index[root]], i)
+	}
+
+	var (
+		clusters      [][]int
+		clusterScores []float64
+	)
+	for i, group := range groups {
+		if len(group) > 1 {
+			clusters = append(clusters, group)
+			clusterScores = append(clusterScores, scores[i])
+		}
+	}
+
+	return clusters, clusterScores
+}
@@ -53,6 +53,17 @@ func DeleteFile(path string) error {
 	return nil
 }
 
+// GetUploadedFilePath maps a url served from /api/static back to the file
+// stored under assets/uploads. It returns false for files hosted elsewhere.
+func GetUploadedFilePath(url string) (string, bool) {
+	_, filename, found := strings.Cut(url, "/api/static/")
+	if !found || filename == "" || strings.Contains(filename, "..") {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s/uploads/%s", PATH, filename), true
+}
+
 func GetExtensions(filename string) string {
 	ext := strings.Split(filename, ".")
 	return ext[len(ext)-1]
@@ -0,0 +1,28 @@
+package utils
+
+import "time"
+
+// DefaultTimeZone is the time zone of the project site, packages without a
+// time zone of their own use it
+const DefaultTimeZone = "Asia/Jakarta"
+
+// LoadLocation loads the time zone by name, an empty or unknown name falls
+// back to DefaultTimeZone
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		name = DefaultTimeZone
+	}
+
+	location, err := time.LoadLocation(name)
+	if err == nil {
+		return location
+	}
+
+	location, err = time.LoadLocation(DefaultTimeZone)
+	if err != nil {
+		// no tz database on the host, Asia/Jakarta has no daylight saving
+		return time.FixedZone("WIB", 7*60*60)
+	}
+
+	return location
+}